@@ -0,0 +1,256 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+)
+
+/*
+BatchVerifier - verifies many range proofs at once
+
+Every proof added to a BatchVerifier must have been made with the
+CryptoParams the verifier was created from, but single and aggregated proofs
+of any size that fits the generators can be mixed. Verify writes the two
+verification equations of each proof as a list of (scalar, point) terms that
+sum to the identity when the proof is valid, weights each equation with a
+fresh random scalar and checks the sum of all of them with a single
+multi-exponentiation. If that check fails the batch is bisected to find the
+proofs responsible.
+*/
+type BatchVerifier struct {
+	ec      *CryptoParams
+	entries []batchEntry
+}
+
+// batchEntry - a proof waiting to be verified, already checked for shape
+type batchEntry struct {
+	mrp   *MultiRangeProof
+	comms []ECPoint // padded to a power of two
+	bits  int       // bits per value
+}
+
+// batchTerms - the weighted verification equations of one proof
+type batchTerms struct {
+	g, h, u  *big.Int   // scalars on G, H and U
+	bpg, bph []*big.Int // scalars on the prefix of BPG and BPH the proof uses
+	points   []ECPoint  // points specific to the proof: V, T1, T2, A, S, L and R
+	scalars  []*big.Int
+}
+
+// NewBatchVerifier returns an empty BatchVerifier for proofs made with ec
+func (ec *CryptoParams) NewBatchVerifier() *BatchVerifier {
+	return &BatchVerifier{ec: ec}
+}
+
+// Len returns the number of proofs in the batch
+func (bv *BatchVerifier) Len() int {
+	return len(bv.entries)
+}
+
+// AddRangeProof adds a single value proof and its commitment to the batch
+func (bv *BatchVerifier) AddRangeProof(comm ECPoint, rp *RangeProof) error {
+	if rp == nil {
+		return fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	return bv.AddMultiRangeProof([]ECPoint{comm}, rp.multi())
+}
+
+// AddMultiRangeProof adds an aggregated proof and its commitments to the
+// batch. Malformed proofs are rejected here rather than by Verify.
+func (bv *BatchVerifier) AddMultiRangeProof(comms []ECPoint, mrp *MultiRangeProof) error {
+	if mrp == nil {
+		return fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	if err := mrp.check(bv.ec.group()); err != nil {
+		return err
+	}
+	if err := checkPoints(bv.ec.group(), comms...); err != nil {
+		return err
+	}
+
+	// the number of IPA rounds fixes how many generators the proof used
+	comms = padCommitments(comms)
+	m := len(comms)
+	rounds := len(mrp.IPP.L)
+	if rounds > 30 || 1<<uint(rounds) > len(bv.ec.BPG) || 1<<uint(rounds) > len(bv.ec.BPH) {
+		return fmt.Errorf("%w: proof needs more generators than the params have", ErrMalformedProof)
+	}
+	size := 1 << uint(rounds)
+	if m == 0 || size%m != 0 {
+		return lengthError("AddMultiRangeProof", size, m)
+	}
+	bits := size / m
+	if mrp.Bits != 0 && mrp.Bits != bits {
+		return fmt.Errorf("%w: proof is over %d bits but has %d rounds for %d values", ErrMalformedProof, mrp.Bits, rounds, m)
+	}
+	if _, err := bv.ec.proofSize(bits, m); err != nil {
+		return err
+	}
+	if err := mrp.IPP.check(bv.ec.group(), size); err != nil {
+		return err
+	}
+
+	bv.entries = append(bv.entries, batchEntry{mrp, comms, bits})
+	return nil
+}
+
+/*
+Verify checks every proof in the batch
+
+It returns true if all of them are valid. Otherwise it returns false together
+with the indices, in the order the proofs were added, of every proof that
+failed on its own.
+*/
+func (bv *BatchVerifier) Verify() (bool, []int, error) {
+	terms := make([]batchTerms, len(bv.entries))
+	for i := range bv.entries {
+		t, err := bv.ec.batchTerms(&bv.entries[i])
+		if err != nil {
+			return false, nil, err
+		}
+		terms[i] = t
+	}
+
+	failed := bv.ec.bisect(terms, 0)
+	return len(failed) == 0, failed, nil
+}
+
+// bisect returns the indices of the proofs in terms that fail, offset by the
+// position of terms in the batch
+func (ec *CryptoParams) bisect(terms []batchTerms, offset int) []int {
+	if len(terms) == 0 || ec.checkTerms(terms) {
+		return nil
+	}
+	if len(terms) == 1 {
+		return []int{offset}
+	}
+
+	half := len(terms) / 2
+	return append(ec.bisect(terms[:half], offset), ec.bisect(terms[half:], offset+half)...)
+}
+
+// checkTerms sums the terms of several proofs with one multi-exponentiation
+// and reports whether the result is the identity
+func (ec *CryptoParams) checkTerms(terms []batchTerms) bool {
+	g, h, u := big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	var bpg, bph []*big.Int
+	var points []ECPoint
+	var scalars []*big.Int
+
+	for _, t := range terms {
+		g.Add(g, t.g)
+		h.Add(h, t.h)
+		u.Add(u, t.u)
+		for len(bpg) < len(t.bpg) {
+			bpg = append(bpg, big.NewInt(0))
+			bph = append(bph, big.NewInt(0))
+		}
+		for i := range t.bpg {
+			bpg[i].Add(bpg[i], t.bpg[i])
+			bph[i].Add(bph[i], t.bph[i])
+		}
+		points = append(points, t.points...)
+		scalars = append(scalars, t.scalars...)
+	}
+
+	points = append(points, ec.G, ec.H, ec.U)
+	scalars = append(scalars, g, h, u)
+	points = append(points, ec.BPG[:len(bpg)]...)
+	scalars = append(scalars, bpg...)
+	points = append(points, ec.BPH[:len(bph)]...)
+	scalars = append(scalars, bph...)
+
+	sum := ec.msm(points, scalars)
+	return sum.X.Sign() == 0 && sum.Y.Sign() == 0
+}
+
+/*
+batchTerms replays the transcript of a proof and writes its two verification
+equations as terms that sum to the identity, each weighted by a random scalar.
+
+	r1 * ((t - delta(y,z)) G + tau H - x T1 - x^2 T2 - sum_j z^(2+j) V_j)
+	r2 * (A + x S - mu H + sum_j (x_j^2 L_j + x_j^-2 R_j) + w (t - ab) U
+	      + sum_i (-z - a s_i) G_i + (z + y^-i (z^(2+j) 2^(i mod n) - b s_i^-1)) H_i)
+
+where j in the last line is the value that bit i belongs to. The terms of the
+inner product argument come from its IPAScalars.
+*/
+func (ec *CryptoParams) batchTerms(e *batchEntry) (batchTerms, error) {
+	mrp := e.mrp
+	m := len(e.comms)
+	n := e.bits
+	size := n * m
+	bt := batchTerms{}
+
+	t := ec.rangeProofTranscript(n, e.comms)
+	t.AppendPoint("A", mrp.A)
+	t.AppendPoint("S", mrp.S)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+	t.AppendPoint("T1", mrp.T1)
+	t.AppendPoint("T2", mrp.T2)
+	cx := t.ChallengeScalar("x")
+	t.AppendScalar("tau_x", mrp.Tau)
+	t.AppendScalar("mu", mrp.Mu)
+	t.AppendScalar("t_hat", mrp.Th)
+	ipa := ec.rangeProofIPA(size, cy)
+	sc := ipa.verificationScalars(t, mrp.Th, &mrp.IPP)
+
+	r1, err := ec.randScalar()
+	if err != nil {
+		return bt, err
+	}
+	r2, err := ec.randScalar()
+	if err != nil {
+		return bt, err
+	}
+
+	mul := func(a ...*big.Int) *big.Int {
+		res := big.NewInt(1)
+		for _, v := range a {
+			res.Mod(res.Mul(res, v), ec.N)
+		}
+		return res
+	}
+	neg := func(a *big.Int) *big.Int {
+		return new(big.Int).Mod(new(big.Int).Neg(a), ec.N)
+	}
+
+	PowersOfY := ec.PowerVector(size, cy)
+	PowersOfZ := ec.PowerVector(m, cz)
+	z2 := mul(cz, cz)
+	x2 := mul(cx, cx)
+
+	// first equation: t(x) is committed to correctly
+	delta := ec.DeltaMRP(PowersOfY, cz, m)
+	bt.g = mul(r1, new(big.Int).Sub(mrp.Th, delta))
+	bt.h = new(big.Int).Sub(mul(r1, mrp.Tau), mul(r2, mrp.Mu))
+	for j := range e.comms {
+		bt.points = append(bt.points, e.comms[j])
+		bt.scalars = append(bt.scalars, neg(mul(r1, z2, PowersOfZ[j])))
+	}
+	bt.points = append(bt.points, mrp.T1, mrp.T2)
+	bt.scalars = append(bt.scalars, neg(mul(r1, cx)), neg(mul(r1, x2)))
+
+	// second equation: the inner product argument for l(x) and r(x)
+	bt.points = append(bt.points, mrp.A, mrp.S)
+	bt.scalars = append(bt.scalars, r2, mul(r2, cx))
+	for j := range sc.L {
+		bt.points = append(bt.points, mrp.IPP.L[j], mrp.IPP.R[j])
+		bt.scalars = append(bt.scalars, mul(r2, sc.L[j]), mul(r2, sc.R[j]))
+	}
+	bt.u = mul(r2, sc.U)
+
+	PowerOfTwos := ec.PowerVector(n, big.NewInt(2))
+
+	bt.bpg = make([]*big.Int, size)
+	bt.bph = make([]*big.Int, size)
+	for i := 0; i < size; i++ {
+		bt.bpg[i] = mul(r2, new(big.Int).Sub(sc.G[i], cz))
+
+		zp := mul(z2, PowersOfZ[i/n], PowerOfTwos[i%n], ipa.hScale[i])
+		bt.bph[i] = mul(r2, new(big.Int).Add(new(big.Int).Add(cz, zp), sc.H[i]))
+	}
+
+	return bt, nil
+}
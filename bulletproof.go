@@ -1,33 +1,35 @@
 package bp_go
 
 import (
-	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
 	"math/big"
+	"math/bits"
 	"fmt"
-	"math"
 
 	"github.com/decred/dcrd/dcrec/secp256k1"
 	"errors"
 )
 
-// EC - An instance of CryptoParams
-var EC CryptoParams
-
-// VecLength - the length of the vector
-var VecLength = 64
+// curve - the secp256k1 curve every ECPoint lives on. It is never reassigned,
+// unlike the generators, which belong to a CryptoParams value.
+var curve = secp256k1.S256()
 
+// VerifyTrans rebuilds a serialised range proof and verifies it against the
+// commitment (x, y) using a freshly generated set of key generators.
 func VerifyTrans(key int, x, y *big.Int, proof string) (bool, error) {
-	EC = NewECPrimeGroupKey(key)
+	ec := NewECPrimeGroupKey(key)
 	comm := ECPoint{x, y}
 	rangeProof := RangeProof{}
 	err := rangeProof.Rebuild(proof)
 	if err != nil {
 		return false, err
 	}
-	valid := RPVerifyTrans(&comm, &rangeProof)
+	valid, err := ec.RPVerifyTrans(&comm, &rangeProof)
+	if err != nil {
+		return false, err
+	}
 
 	if !valid {
 		err := errors.New("The range proof failed to verify")
@@ -37,14 +39,15 @@ func VerifyTrans(key int, x, y *big.Int, proof string) (bool, error) {
 	return valid, nil
 }
 
-// ECPoint - an elliptic curve point
+// ECPoint - an elliptic curve point. Its methods work on secp256k1; points
+// of any other group go through the Group of their CryptoParams.
 type ECPoint struct {
 	X, Y *big.Int
 }
 
 // Equal returns true if points p (self) and p2 (arg) are the same.
 func (p ECPoint) Equal(p2 ECPoint) bool {
-	if p.X.Cmp(p2.X) == 0 && p2.Y.Cmp(p2.Y) == 0 {
+	if p.X.Cmp(p2.X) == 0 && p.Y.Cmp(p2.Y) == 0 {
 		return true
 	}
 	return false
@@ -52,50 +55,75 @@ func (p ECPoint) Equal(p2 ECPoint) bool {
 
 // Mult multiplies point p by scalar s and returns the resulting point
 func (p ECPoint) Mult(s *big.Int) ECPoint {
-	modS := new(big.Int).Mod(s, EC.N)
-	X, Y := EC.C.ScalarMult(p.X, p.Y, modS.Bytes())
+	modS := new(big.Int).Mod(s, curve.N)
+	X, Y := curve.ScalarMult(p.X, p.Y, modS.Bytes())
 	return ECPoint{X, Y}
 }
 
 // Add adds points p and p2 and returns the resulting point
 func (p ECPoint) Add(p2 ECPoint) ECPoint {
-	X, Y := EC.C.Add(p.X, p.Y, p2.X, p2.Y)
+	X, Y := curve.Add(p.X, p.Y, p2.X, p2.Y)
 	return ECPoint{X, Y}
 }
 
 // Neg returns the additive inverse of point p
 func (p ECPoint) Neg() ECPoint {
 	negY := new(big.Int).Neg(p.Y)
-	modValue := negY.Mod(negY, EC.C.Params().P) // mod P is fine here because we're describing a curve point
+	modValue := negY.Mod(negY, curve.P) // mod P is fine here because we're describing a curve point
 	return ECPoint{p.X, modValue}
 }
 
+// Bytes returns the 33 byte SEC1 compressed encoding of the secp256k1 point
+// p, 02 or 03 for the parity of Y followed by X
 func (p ECPoint) Bytes() []byte {
-	key := secp256k1.NewPublicKey(p.X, p.Y)
-	return key.SerializeCompressed()
+	buf := make([]byte, 33)
+	buf[0] = 2 | byte(p.Y.Bit(0))
+	p.X.FillBytes(buf[1:])
+	return buf
 }
 
+// Rebuild sets p to the secp256k1 point encoded by Bytes, recovering Y as
+// the square root of X^3 + 7 with the parity of the first byte
 func (p *ECPoint) Rebuild(buf []byte) error {
-	key, err := secp256k1.ParsePubKey(buf)
-	if err != nil {
-		return err
+	if len(buf) != 33 || buf[0]&^1 != 2 {
+		return fmt.Errorf("%w: not a compressed secp256k1 point", ErrInvalidPoint)
+	}
+	x := new(big.Int).SetBytes(buf[1:])
+	if x.Cmp(curve.P) >= 0 {
+		return fmt.Errorf("%w: X is not reduced mod P", ErrInvalidPoint)
+	}
+	y, ok := curveSqrt(curve.P, new(big.Int), curve.B, x)
+	if !ok {
+		return fmt.Errorf("%w: X is not on secp256k1", ErrInvalidPoint)
 	}
-	p.X = key.X
-	p.Y = key.Y
+	if y.Bit(0) != uint(buf[0]&1) {
+		y.Sub(curve.P, y)
+	}
+	p.X, p.Y = x, y
 	return nil
 }
 
-// CryptoParams - the struct containing the crypto params for the rangeproofs
+/*
+CryptoParams - the struct containing the crypto params for the rangeproofs
+
+Every commit, prove and verify call is a method on a CryptoParams value, so
+several sets of generators (for example with different vector lengths) can be
+used side by side in one process. The package level functions of the same
+name are thin wrappers that use the default EC instance.
+*/
 type CryptoParams struct {
-	C   elliptic.Curve      // curve
-	KC  *secp256k1.KoblitzCurve // curve
-	BPG []ECPoint           // slice of gen 1 for BP
-	BPH []ECPoint           // slice of gen 2 for BP
-	N   *big.Int            // scalar prime
-	U   ECPoint             // a point that is a fixed group element with an unknown discrete-log relative to g,h
-	V   int                 // Vector length
-	G   ECPoint             // G value for commitments of a single value
-	H   ECPoint             // H value for commitments of a single value
+	Group  Group            // group the points live in, secp256k1 if nil
+	BPG    []ECPoint        // slice of gen 1 for BP
+	BPH    []ECPoint        // slice of gen 2 for BP
+	N      *big.Int         // scalar prime
+	U      ECPoint          // a point that is a fixed group element with an unknown discrete-log relative to g,h
+	V      int              // Vector length
+	G      ECPoint          // G value for commitments of a single value
+	H      ECPoint          // H value for commitments of a single value
+	Hash   TranscriptHash   // hash used for Fiat-Shamir transcripts, SHA256 by default
+	id     []byte           // digest of the generators, see ID
+	tables *fixedBaseTables // precomputed multiples of the generators, see fixedTables
+	fr     *scalarField     // constant time arithmetic mod N
 }
 
 // Zero - returns a Zero ECPoint
@@ -103,114 +131,166 @@ func (c CryptoParams) Zero() ECPoint {
 	return ECPoint{big.NewInt(0), big.NewInt(0)}
 }
 
-func check(e error) {
-	if e != nil {
-		panic(e)
+// randScalar returns a uniformly random scalar mod N
+func (ec *CryptoParams) randScalar() (*big.Int, error) {
+	return rand.Int(rand.Reader, ec.N)
+}
+
+// valid reports whether p has both coordinates set and lies on the curve
+func (p ECPoint) valid() bool {
+	return p.X != nil && p.Y != nil && curve.IsOnCurve(p.X, p.Y)
+}
+
+// checkPoints returns ErrInvalidPoint if any of the points is not in g
+func checkPoints(g Group, points ...ECPoint) error {
+	for i, p := range points {
+		if !g.IsOnCurve(p) {
+			return fmt.Errorf("%w: point %d is not on the curve", ErrInvalidPoint, i)
+		}
 	}
+	return nil
 }
 
-// InnerProdArg - Stores the values of the InnerProduct Arguements
-type InnerProdArg struct {
-	L []ECPoint
-	R []ECPoint
-	A *big.Int
-	B *big.Int
+// isPowerOfTwo reports whether n is a positive power of two
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
 }
 
 // GenerateNewParams - Creates new EC Parameters to be used in the bulletproofs
-func GenerateNewParams(G, H []ECPoint, x *big.Int, L, R, P ECPoint) ([]ECPoint, []ECPoint, ECPoint) {
-	nprime := len(G) / 2
+func (ec *CryptoParams) GenerateNewParams(G, H []ECPoint, x *big.Int, L, R, P ECPoint) ([]ECPoint, []ECPoint, ECPoint, error) {
+	if len(G) != len(H) || len(G)%2 != 0 {
+		return nil, nil, ECPoint{}, lengthError("GenerateNewParams", len(G), len(H))
+	}
+	Gprime, Hprime, Pprime := ec.generateNewParams(G, H, x, L, R, P)
+	return Gprime, Hprime, Pprime, nil
+}
 
-	Gprime := make([]ECPoint, nprime)
-	Hprime := make([]ECPoint, nprime)
+func (ec *CryptoParams) generateNewParams(G, H []ECPoint, x *big.Int, L, R, P ECPoint) ([]ECPoint, []ECPoint, ECPoint) {
+	nprime := len(G) / 2
 
-	xinv := new(big.Int).ModInverse(x, EC.N)
+	xinv := new(big.Int).ModInverse(x, ec.N)
 
 	// Gprime = xinv * G[:nprime] + x*G[nprime:]
 	// Hprime = x * H[:nprime] + xinv*H[nprime:]
+	Gprime := ec.ops().fold(G[:nprime], G[nprime:], xinv, x)
+	Hprime := ec.ops().fold(H[:nprime], H[nprime:], x, xinv)
 
-	for i := range Gprime {
-		//fmt.Printf("i: %d && i+nprime: %d\n", i, i+nprime)
-		Gprime[i] = G[i].Mult(xinv).Add(G[i+nprime].Mult(x))
-		Hprime[i] = H[i].Mult(x).Add(H[i+nprime].Mult(xinv))
-	}
+	x2 := new(big.Int).Mod(new(big.Int).Mul(x, x), ec.N)
+	xinv2 := new(big.Int).ModInverse(x2, ec.N)
 
-	x2 := new(big.Int).Mod(new(big.Int).Mul(x, x), EC.N)
-	xinv2 := new(big.Int).ModInverse(x2, EC.N)
-
-	Pprime := L.Mult(x2).Add(P).Add(R.Mult(xinv2)) // x^2 * L + P + xinv^2 * R
+	Pprime := ec.msm([]ECPoint{L, P, R}, []*big.Int{x2, big.NewInt(1), xinv2}) // x^2 * L + P + xinv^2 * R
 
 	return Gprime, Hprime, Pprime
 }
 
-// InnerProduct - The length here always has to be a power of two
-func InnerProduct(a []*big.Int, b []*big.Int) *big.Int {
+// InnerProduct - the inner product <a, b> mod N of two vectors of any length,
+// or ErrLengthMismatch if their lengths differ
+func (ec *CryptoParams) InnerProduct(a []*big.Int, b []*big.Int) (*big.Int, error) {
 	if len(a) != len(b) {
-		fmt.Println("InnerProduct: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(a): %d\n", len(a))
-		fmt.Printf("len(b): %d\n", len(b))
+		return nil, lengthError("InnerProduct", len(a), len(b))
 	}
+	return ec.innerProduct(a, b), nil
+}
 
+func (ec *CryptoParams) innerProduct(a []*big.Int, b []*big.Int) *big.Int {
 	c := big.NewInt(0)
 
 	for i := range a {
 		tmp1 := new(big.Int).Mul(a[i], b[i])
-		c = new(big.Int).Add(c, new(big.Int).Mod(tmp1, EC.N))
+		c = new(big.Int).Add(c, new(big.Int).Mod(tmp1, ec.N))
 	}
 
-	return new(big.Int).Mod(c, EC.N)
+	return new(big.Int).Mod(c, ec.N)
 }
 
 //VectorAdd - adds the vector arrays
-func VectorAdd(v []*big.Int, w []*big.Int) []*big.Int {
+func (ec *CryptoParams) VectorAdd(v []*big.Int, w []*big.Int) ([]*big.Int, error) {
 	if len(v) != len(w) {
-		fmt.Println("VectorAdd: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(v): %d\n", len(v))
-		fmt.Printf("len(w): %d\n", len(w))
+		return nil, lengthError("VectorAdd", len(v), len(w))
 	}
+	return ec.vectorAdd(v, w), nil
+}
+
+func (ec *CryptoParams) vectorAdd(v []*big.Int, w []*big.Int) []*big.Int {
 	result := make([]*big.Int, len(v))
 
 	for i := range v {
-		result[i] = new(big.Int).Mod(new(big.Int).Add(v[i], w[i]), EC.N)
+		result[i] = new(big.Int).Mod(new(big.Int).Add(v[i], w[i]), ec.N)
 	}
 
 	return result
 }
 
-// VectorHadamard - add more details later
-func VectorHadamard(v, w []*big.Int) []*big.Int {
+// VectorHadamard - multiplies the vectors element by element
+func (ec *CryptoParams) VectorHadamard(v, w []*big.Int) ([]*big.Int, error) {
 	if len(v) != len(w) {
-		fmt.Println("VectorHadamard: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(v): %d\n", len(w))
-		fmt.Printf("len(w): %d\n", len(v))
+		return nil, lengthError("VectorHadamard", len(v), len(w))
 	}
+	return ec.vectorHadamard(v, w), nil
+}
 
+func (ec *CryptoParams) vectorHadamard(v, w []*big.Int) []*big.Int {
 	result := make([]*big.Int, len(v))
 
 	for i := range v {
-		result[i] = new(big.Int).Mod(new(big.Int).Mul(v[i], w[i]), EC.N)
+		result[i] = new(big.Int).Mod(new(big.Int).Mul(v[i], w[i]), ec.N)
 	}
 
 	return result
 }
 
 // VectorAddScalar - adds scalar vectors together
-func VectorAddScalar(v []*big.Int, s *big.Int) []*big.Int {
+func (ec *CryptoParams) VectorAddScalar(v []*big.Int, s *big.Int) []*big.Int {
 	result := make([]*big.Int, len(v))
 
 	for i := range v {
-		result[i] = new(big.Int).Mod(new(big.Int).Add(v[i], s), EC.N)
+		result[i] = new(big.Int).Mod(new(big.Int).Add(v[i], s), ec.N)
 	}
 
 	return result
 }
 
 // ScalarVectorMul - multiplies two scalar vectors together
-func ScalarVectorMul(v []*big.Int, s *big.Int) []*big.Int {
+func (ec *CryptoParams) ScalarVectorMul(v []*big.Int, s *big.Int) []*big.Int {
 	result := make([]*big.Int, len(v))
 
 	for i := range v {
-		result[i] = new(big.Int).Mod(new(big.Int).Mul(v[i], s), EC.N)
+		result[i] = new(big.Int).Mod(new(big.Int).Mul(v[i], s), ec.N)
+	}
+
+	return result
+}
+
+/*
+BatchInvert - inverts every element of v mod N
+
+Uses Montgomery's trick: one ModInverse of the product of all the elements
+and three multiplications for each of them, rather than a ModInverse each.
+Every element must be non zero mod N.
+*/
+func (ec *CryptoParams) BatchInvert(v []*big.Int) ([]*big.Int, error) {
+	for i := range v {
+		if v[i] == nil || new(big.Int).Mod(v[i], ec.N).Sign() == 0 {
+			return nil, fmt.Errorf("%w: element %d", ErrNotInvertible, i)
+		}
+	}
+	return ec.batchInvert(v), nil
+}
+
+func (ec *CryptoParams) batchInvert(v []*big.Int) []*big.Int {
+	result := make([]*big.Int, len(v))
+	acc := big.NewInt(1)
+	for i := range v {
+		result[i] = acc
+		acc = new(big.Int).Mod(new(big.Int).Mul(acc, v[i]), ec.N)
+	}
+
+	// acc is 1 / (v_0 ... v_i) at the start of round i
+	acc.ModInverse(acc, ec.N)
+	for i := len(v) - 1; i >= 0; i-- {
+		prefix := result[i]
+		result[i] = new(big.Int).Mod(new(big.Int).Mul(prefix, acc), ec.N)
+		acc = new(big.Int).Mod(new(big.Int).Mul(acc, v[i]), ec.N)
 	}
 
 	return result
@@ -220,72 +300,156 @@ func ScalarVectorMul(v []*big.Int, s *big.Int) []*big.Int {
 InnerProductProveSub - Inner Product Argument
 Proves that <a,b>=c
 This is a building block for BulletProofs
+
+Each round appends L and R to t and squeezes the round challenge from it.
+The prover never needs the commitment P, so it is accepted but not used.
+Vectors of any length are padded as described at padGenerators, and proof
+must have room for the rounds of the padded length.
 */
-func InnerProductProveSub(proof InnerProdArg, G, H []ECPoint, a []*big.Int, b []*big.Int, u ECPoint, P ECPoint) InnerProdArg {
-	//fmt.Printf("Proof so far: %s\n", proof)
-	if len(a) == 1 {
-		// Prover sends a & b
-		//fmt.Printf("a: %d && b: %d\n", a[0], b[0])
-		proof.A = a[0]
-		proof.B = b[0]
-		return proof
+func (ec *CryptoParams) InnerProductProveSub(t *Transcript, proof InnerProdArg, G, H []ECPoint, a []*big.Int, b []*big.Int, u ECPoint, P ECPoint) (InnerProdArg, error) {
+	if len(G) != len(H) || len(G) != len(a) || len(a) != len(b) || len(a) == 0 {
+		return InnerProdArg{}, lengthError("InnerProductProveSub", len(G), len(H), len(a), len(b))
+	}
+	n := padCount(len(a))
+	if len(proof.L) < bits.Len(uint(n))-1 || len(proof.R) != len(proof.L) {
+		return InnerProdArg{}, lengthError("InnerProductProveSub", len(proof.L), len(proof.R))
 	}
+	G, H = padGenerators(ec.group(), G, H)
+	return ec.innerProductProveSub(t, proof, ec.ctGens(G, H, u), nil, ec.padScalars(a, n), ec.padScalars(b, n)), nil
+}
 
-	curIt := int(math.Log2(float64(len(a)))) - 1
+/*
+innerProductProveSub runs the remaining rounds of the argument, folding the
+generators G and H of gens, a and b in place. gens must have u as its one
+extra point, and if hScale is not nil the argument is over hScale[i] * H[i].
+The first round is stored last in proof, and t hands out the challenges, so
+the same rounds serve both Transcript and MerlinTranscript.
+
+a and b are secret, so everything computed from them uses the constant time
+scalar and point arithmetic. The generators are public and are folded only as
+far as the prover needs them. Each is kept as a public factor times a point,
+G_i = gs_i G"_i, so that a round
+
+	G'_i = x^-1 G_i + x G_(n'+i) = x^-1 gs_i (G"_i + x^2 (gs_(n'+i) / gs_i) G"_(n'+i))
+
+takes one scalar multiplication per generator instead of two, hScale is
+never multiplied into H, and the generators are not folded after the last
+round at all.
+*/
+func (ec *CryptoParams) innerProductProveSub(t ipaTranscript, proof InnerProdArg, gens ctGens, hScale []*big.Int, a, b []scalar) InnerProdArg {
+	n := len(a)
+	gs := make([]scalar, n)
+	hs := make([]scalar, n)
+	for i := 0; i < n; i++ {
+		gs[i] = ec.fr.one()
+		hs[i] = ec.fr.one()
+		if hScale != nil {
+			hs[i] = ec.fr.fromBig(hScale[i])
+		}
+	}
 
-	nprime := len(a) / 2
-	cl := InnerProduct(a[:nprime], b[nprime:]) // either this line
-	cr := InnerProduct(a[nprime:], b[:nprime]) // or this line
-	L := TwoVectorPCommitWithGens(G[nprime:], H[:nprime], a[:nprime], b[nprime:]).Add(u.Mult(cl))
-	R := TwoVectorPCommitWithGens(G[:nprime], H[nprime:], a[nprime:], b[:nprime]).Add(u.Mult(cr))
+	// scratch space for the scalars of L and R, reused every round
+	sg := make([]scalar, n/2)
+	sh := make([]scalar, n/2)
+	for ; n > 1; n /= 2 {
+		curIt := bits.Len(uint(n)) - 2
+		nprime := n / 2
 
-	proof.L[curIt] = L
-	proof.R[curIt] = R
+		// L = <a_lo, G_hi> + <b_hi, H_lo> + cl u
+		cl := scalarInnerProduct(a[:nprime], b[nprime:n])
+		for i := 0; i < nprime; i++ {
+			sg[i].mul(&a[i], &gs[nprime+i])
+			sh[i].mul(&b[nprime+i], &hs[i])
+		}
+		L := gens.commit(nprime, sg[:nprime], 0, sh[:nprime], []scalar{cl})
 
-	// prover sends L & R and gets a challenge
-	s256 := sha256.Sum256([]byte(
-		L.X.String() + L.Y.String() +
-			R.X.String() + R.Y.String()))
+		// R = <a_hi, G_lo> + <b_lo, H_hi> + cr u
+		cr := scalarInnerProduct(a[nprime:n], b[:nprime])
+		for i := 0; i < nprime; i++ {
+			sg[i].mul(&a[nprime+i], &gs[i])
+			sh[i].mul(&b[i], &hs[nprime+i])
+		}
+		R := gens.commit(0, sg[:nprime], nprime, sh[:nprime], []scalar{cr})
 
-	x := new(big.Int).SetBytes(s256[:])
+		proof.L[curIt] = L
+		proof.R[curIt] = R
 
-	Gprime, Hprime, Pprime := GenerateNewParams(G, H, x, L, R, P)
-	xinv := new(big.Int).ModInverse(x, EC.N)
+		// prover sends L & R and gets a challenge
+		x := t.ipaRound(L, R)
+		sx := ec.fr.fromBig(x)
+		sxinv := ec.fr.fromBig(new(big.Int).ModInverse(x, ec.N))
+
+		// a' = x a_lo + x^-1 a_hi, b' = x^-1 b_lo + x b_hi
+		var tmp scalar
+		for i := 0; i < nprime; i++ {
+			a[i].mul(&a[i], &sx)
+			tmp.mul(&a[nprime+i], &sxinv)
+			a[i].add(&a[i], &tmp)
+			b[i].mul(&b[i], &sxinv)
+			tmp.mul(&b[nprime+i], &sx)
+			b[i].add(&b[i], &tmp)
+		}
 
-	// or these two lines
-	aprime := VectorAdd(
-		ScalarVectorMul(a[:nprime], x),
-		ScalarVectorMul(a[nprime:], xinv))
-	bprime := VectorAdd(
-		ScalarVectorMul(b[:nprime], xinv),
-		ScalarVectorMul(b[nprime:], x))
+		if nprime > 1 {
+			var sx2, sxinv2 scalar
+			sx2.mul(&sx, &sx)
+			sxinv2.mul(&sxinv, &sxinv)
+			gens.fold(n, gs, hs, &sxinv, &sx2, &sx, &sxinv2)
+		}
+	}
 
-	return InnerProductProveSub(proof, Gprime, Hprime, aprime, bprime, u, Pprime)
+	// Prover sends a & b
+	proof.A = a[0].big()
+	proof.B = b[0].big()
+	return proof
 }
 
-// InnerProductProve - validate the inner product
-func InnerProductProve(a []*big.Int, b []*big.Int, c *big.Int, P, U ECPoint, G, H []ECPoint) InnerProdArg {
-	loglen := int(math.Log2(float64(len(a))))
-
-	challenges := make([]*big.Int, loglen+1)
-	Lvals := make([]ECPoint, loglen)
-	Rvals := make([]ECPoint, loglen)
-
-	runningProof := InnerProdArg{
-		Lvals,
-		Rvals,
-		big.NewInt(0),
-		big.NewInt(0)}
-
-	// randomly generate an x value from public data
-	x := sha256.Sum256([]byte(P.X.String() + P.Y.String()))
+// foldScaled folds the public generators s_i P_i of one round in place, so
+// the first half holds outer s_i (P_i + inner (s_(n'+i) / s_i) P_(n'+i)), see
+// innerProductProveSub
+func foldScaled(P []jacobianPoint, s []scalar, outer, inner *scalar) {
+	nprime := len(P) / 2
+	sinv := scalarBatchInverse(s[:nprime])
+	var k scalar
+	var q jacobianPoint
+	for i := 0; i < nprime; i++ {
+		k.mul(inner, &s[nprime+i])
+		k.mul(&k, &sinv[i])
+		kl := k.limbs()
+		q.mul(&P[nprime+i], &kl)
+		P[i].add(&P[i], &q)
+		s[i].mul(&s[i], outer)
+	}
+	normalizeBatch(P[:nprime])
+}
 
-	challenges[loglen] = new(big.Int).SetBytes(x[:])
+// InnerProductProve - validate the inner product
+//
+// The vectors may have any length, see padGenerators.
+func (ec *CryptoParams) InnerProductProve(a []*big.Int, b []*big.Int, c *big.Int, P, U ECPoint, G, H []ECPoint) (InnerProdArg, error) {
+	if len(G) != len(H) || len(G) != len(a) || len(a) != len(b) || len(a) == 0 {
+		return InnerProdArg{}, lengthError("InnerProductProve", len(G), len(H), len(a), len(b))
+	}
+	ipa, err := ec.NewIPA(G, H, U)
+	if err != nil {
+		return InnerProdArg{}, err
+	}
+	return ipa.Prove(ipa.Transcript(P, c), a, b)
+}
 
-	Pprime := P.Add(U.Mult(new(big.Int).Mul(new(big.Int).SetBytes(x[:]), c)))
-	ux := U.Mult(new(big.Int).SetBytes(x[:]))
-	//fmt.Printf("Prover Pprime value to run sub off of: %s\n", Pprime)
-	return InnerProductProveSub(runningProof, G, H, a, b, ux, Pprime)
+// checkInnerProductInputs validates everything an inner product verifier
+// reads before it starts doing curve arithmetic
+func (ec *CryptoParams) checkInnerProductInputs(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp *InnerProdArg) error {
+	if c == nil {
+		return fmt.Errorf("%w: missing inner product value", ErrMalformedProof)
+	}
+	if len(G) != len(H) || len(G) == 0 {
+		return lengthError("InnerProductVerify", len(G), len(H))
+	}
+	if err := checkPoints(ec.group(), P, U); err != nil {
+		return err
+	}
+	return ipp.check(ec.group(), padCount(len(G)))
 }
 
 /*
@@ -298,46 +462,46 @@ all g' and h' computations
 P : the Pedersen commitment we are verifying is a commitment to the innner product
 ipp : the proof
 */
-func InnerProductVerify(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) bool {
-	s1 := sha256.Sum256([]byte(P.X.String() + P.Y.String()))
-	chal1 := new(big.Int).SetBytes(s1[:])
-	ux := U.Mult(chal1)
+func (ec *CryptoParams) InnerProductVerify(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) (bool, error) {
+	if err := ec.checkInnerProductInputs(c, P, U, G, H, &ipp); err != nil {
+		return false, err
+	}
+
+	ipa, err := ec.NewIPA(G, H, U)
+	if err != nil {
+		return false, err
+	}
+	t := ipa.Transcript(P, c)
+	G, H = ipa.G, ipa.H
+	chal1 := t.ChallengeScalar("w")
+	ux := ec.mult(U, chal1)
 	curIt := len(ipp.L) - 1
 
 	Gprime := G
 	Hprime := H
-	Pprime := P.Add(ux.Mult(c)) // line 6 from protocol 1
-	//fmt.Printf("New Commitment value with u^cx: %s \n", Pprime)
+	Pprime := ec.add(P, ec.mult(ux, c)) // line 6 from protocol 1
 
 	for curIt >= 0 {
 		Lval := ipp.L[curIt]
 		Rval := ipp.R[curIt]
 
 		// prover sends L & R and gets a challenge
-		s256 := sha256.Sum256([]byte(
-			Lval.X.String() + Lval.Y.String() +
-				Rval.X.String() + Rval.Y.String()))
+		t.AppendPoint("L", Lval)
+		t.AppendPoint("R", Rval)
+		chal2 := t.ChallengeScalar("x")
 
-		chal2 := new(big.Int).SetBytes(s256[:])
-
-		Gprime, Hprime, Pprime = GenerateNewParams(Gprime, Hprime, chal2, Lval, Rval, Pprime)
+		Gprime, Hprime, Pprime = ec.generateNewParams(Gprime, Hprime, chal2, Lval, Rval, Pprime)
 		curIt--
 	}
-	ccalc := new(big.Int).Mod(new(big.Int).Mul(ipp.A, ipp.B), EC.N)
+	ccalc := new(big.Int).Mod(new(big.Int).Mul(ipp.A, ipp.B), ec.N)
 
-	Pcalc1 := Gprime[0].Mult(ipp.A)
-	Pcalc2 := Hprime[0].Mult(ipp.B)
-	Pcalc3 := ux.Mult(ccalc)
-	Pcalc := Pcalc1.Add(Pcalc2).Add(Pcalc3)
+	Pcalc := ec.msm([]ECPoint{Gprime[0], Hprime[0], ux}, []*big.Int{ipp.A, ipp.B, ccalc})
 
 	if !Pprime.Equal(Pcalc) {
-		fmt.Println("IPVerify - Final Commitment checking failed")
-		fmt.Printf("Final Pprime value: %s \n", Pprime)
-		fmt.Printf("Calculated Pprime value to check against: %s \n", Pcalc)
-		return false
+		return false, nil
 	}
 
-	return true
+	return true, nil
 }
 
 /*
@@ -345,74 +509,15 @@ InnerProductVerifyFast
 Given a inner product proof, verifies the correctness of the proof. Does the same as above except
 we replace n separate exponentiations with a single multi-exponentiation.
 */
-func InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) bool {
-	s1 := sha256.Sum256([]byte(P.X.String() + P.Y.String()))
-	chal1 := new(big.Int).SetBytes(s1[:])
-	challenges := make([]*big.Int, len(ipp.L))
-	ux := U.Mult(chal1)
-	curIt := len(ipp.L)
-
-	// check all challenges
-
-
-	for j := curIt - 1; j >= 0; j-- {
-		Lval := ipp.L[j]
-		Rval := ipp.R[j]
-
-		// prover sends L & R and gets a challenge
-		s256 := sha256.Sum256([]byte(
-			Lval.X.String() + Lval.Y.String() +
-				Rval.X.String() + Rval.Y.String()))
-
-		challenges[j]= new(big.Int).SetBytes(s256[:])
-
-	}
-	// begin computing
-
-	curIt--
-	Pprime := P.Add(ux.Mult(c)) // line 6 from protocol 1
-
-	tmp1 := EC.Zero()
-	for j := curIt; j >= 0; j-- {
-		x2 := new(big.Int).Exp(challenges[j], big.NewInt(2), EC.N)
-		x2i := new(big.Int).ModInverse(x2, EC.N)
-		//fmt.Println(tmp1)
-		tmp1 = ipp.L[j].Mult(x2).Add(ipp.R[j].Mult(x2i)).Add(tmp1)
-		//fmt.Println(tmp1)
-	}
-	rhs := Pprime.Add(tmp1)
-
-	sScalars := make([]*big.Int, EC.V)
-	invsScalars := make([]*big.Int, EC.V)
-
-	for i := 0; i < EC.V; i++ {
-		si := big.NewInt(1)
-		for j := curIt; j >= 0; j-- {
-			// original challenge if the jth bit of i is 1, inverse challenge otherwise
-
-			chal := challenges[j]
-			if big.NewInt(int64(i)).Bit(j) == 0 {
-				chal = new(big.Int).ModInverse(chal, EC.N)
-			}
-			// fmt.Printf("Challenge raised to value: %d\n", chal)
-			si = new(big.Int).Mod(new(big.Int).Mul(si, chal), EC.N)
-		}
-		//fmt.Printf("Si value: %d\n", si)
-		sScalars[i] = si
-		invsScalars[i] = new(big.Int).ModInverse(si, EC.N)
+func (ec *CryptoParams) InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) (bool, error) {
+	if err := ec.checkInnerProductInputs(c, P, U, G, H, &ipp); err != nil {
+		return false, err
 	}
-
-	ccalc := new(big.Int).Mod(new(big.Int).Mul(ipp.A, ipp.B), EC.N)
-	lhs := TwoVectorPCommitWithGens(G, H, ScalarVectorMul(sScalars, ipp.A), ScalarVectorMul(invsScalars, ipp.B)).Add(ux.Mult(ccalc))
-
-	if !rhs.Equal(lhs) {
-		fmt.Println("IPVerify - Final Commitment checking failed")
-		fmt.Printf("Final rhs value: %s \n", rhs)
-		fmt.Printf("Final lhs value: %s \n", lhs)
-		return false
+	ipa, err := ec.NewIPA(G, H, U)
+	if err != nil {
+		return false, err
 	}
-
-	return true
+	return ipa.Verify(ipa.Transcript(P, c), P, c, &ipp)
 }
 
 // PadLeft - from here: https://play.golang.org/p/zciRZvD0Gr with a fix
@@ -462,33 +567,39 @@ func reverse(l []*big.Int) []*big.Int {
 	return result
 }
 
-func PowerVector(l int, base *big.Int) []*big.Int {
+func (ec *CryptoParams) PowerVector(l int, base *big.Int) []*big.Int {
 	result := make([]*big.Int, l)
 
-	for i := 0; i < l; i++ {
-		result[i] = new(big.Int).Exp(base, big.NewInt(int64(i)), EC.N)
+	if l == 0 {
+		return result
+	}
+	result[0] = big.NewInt(1)
+	for i := 1; i < l; i++ {
+		result[i] = new(big.Int).Mod(new(big.Int).Mul(result[i-1], base), ec.N)
 	}
 
 	return result
 }
 
-func RandVector(l int) []*big.Int {
+func (ec *CryptoParams) RandVector(l int) ([]*big.Int, error) {
 	result := make([]*big.Int, l)
 
 	for i := 0; i < l; i++ {
-		x, err := rand.Int(rand.Reader, EC.N)
-		check(err)
+		x, err := ec.randScalar()
+		if err != nil {
+			return nil, err
+		}
 		result[i] = x
 	}
 
-	return result
+	return result, nil
 }
 
-func VectorSum(y []*big.Int) *big.Int {
+func (ec *CryptoParams) VectorSum(y []*big.Int) *big.Int {
 	result := big.NewInt(0)
 
 	for _, j := range y {
-		result = new(big.Int).Mod(new(big.Int).Add(result, j), EC.N)
+		result = new(big.Int).Mod(new(big.Int).Add(result, j), ec.N)
 	}
 
 	return result
@@ -496,6 +607,7 @@ func VectorSum(y []*big.Int) *big.Int {
 
 type RangeProof struct {
 	Comm Commitment
+	Bits int // the value is proven to be in [0, 2^Bits)
 	A    ECPoint
 	S    ECPoint
 	T1   ECPoint
@@ -506,527 +618,184 @@ type RangeProof struct {
 	IPP  InnerProdArg
 }
 
+// multi returns the proof as an aggregate proof over a single value
+func (rp *RangeProof) multi() *MultiRangeProof {
+	return &MultiRangeProof{
+		Comms: []Commitment{rp.Comm},
+		Bits:  rp.Bits,
+		A:     rp.A,
+		S:     rp.S,
+		T1:    rp.T1,
+		T2:    rp.T2,
+		Tau:   rp.Tau,
+		Th:    rp.Th,
+		Mu:    rp.Mu,
+		IPP:   rp.IPP,
+	}
+}
+
 /*
 Delta is a helper function that is used in the range proof
 
 \delta(y, z) = (z-z^2)<1^n, y^n> - z^3<1^n, 2^n>
 */
 
-func Delta(y []*big.Int, z *big.Int) *big.Int {
+func (ec *CryptoParams) Delta(y []*big.Int, z *big.Int) *big.Int {
 	result := big.NewInt(0)
 
 	// (z-z^2)<1^n, y^n>
-	z2 := new(big.Int).Mod(new(big.Int).Mul(z, z), EC.N)
-	t1 := new(big.Int).Mod(new(big.Int).Sub(z, z2), EC.N)
-	t2 := new(big.Int).Mod(new(big.Int).Mul(t1, VectorSum(y)), EC.N)
+	z2 := new(big.Int).Mod(new(big.Int).Mul(z, z), ec.N)
+	t1 := new(big.Int).Mod(new(big.Int).Sub(z, z2), ec.N)
+	t2 := new(big.Int).Mod(new(big.Int).Mul(t1, ec.VectorSum(y)), ec.N)
 
 	// z^3<1^n, 2^n>
-	z3 := new(big.Int).Mod(new(big.Int).Mul(z2, z), EC.N)
-	po2sum := new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(EC.V)), EC.N), big.NewInt(1))
-	t3 := new(big.Int).Mod(new(big.Int).Mul(z3, po2sum), EC.N)
+	z3 := new(big.Int).Mod(new(big.Int).Mul(z2, z), ec.N)
+	po2sum := new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(ec.V)), ec.N), big.NewInt(1))
+	t3 := new(big.Int).Mod(new(big.Int).Mul(z3, po2sum), ec.N)
 
-	result = new(big.Int).Mod(new(big.Int).Sub(t2, t3), EC.N)
+	result = new(big.Int).Mod(new(big.Int).Sub(t2, t3), ec.N)
 
 	return result
 }
 
 // Calculates (aL - z*1^n) + sL*x
-func CalculateL(aL, sL []*big.Int, z, x *big.Int) []*big.Int {
-	result := make([]*big.Int, len(aL))
-
-	tmp1 := VectorAddScalar(aL, new(big.Int).Neg(z))
-	tmp2 := ScalarVectorMul(sL, x)
+func (ec *CryptoParams) CalculateL(aL, sL []*big.Int, z, x *big.Int) ([]*big.Int, error) {
+	if len(aL) != len(sL) {
+		return nil, lengthError("CalculateL", len(aL), len(sL))
+	}
+	return ec.calculateL(aL, sL, z, x), nil
+}
 
-	result = VectorAdd(tmp1, tmp2)
+func (ec *CryptoParams) calculateL(aL, sL []*big.Int, z, x *big.Int) []*big.Int {
+	tmp1 := ec.VectorAddScalar(aL, new(big.Int).Neg(z))
+	tmp2 := ec.ScalarVectorMul(sL, x)
 
-	return result
+	return ec.vectorAdd(tmp1, tmp2)
 }
 
-func CalculateR(aR, sR, y, po2 []*big.Int, z, x *big.Int) []*big.Int {
+func (ec *CryptoParams) CalculateR(aR, sR, y, po2 []*big.Int, z, x *big.Int) ([]*big.Int, error) {
 	if len(aR) != len(sR) || len(aR) != len(y) || len(y) != len(po2) {
-		fmt.Println("CalculateR: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(aR): %d\n", len(aR))
-		fmt.Printf("len(sR): %d\n", len(sR))
-		fmt.Printf("len(y): %d\n", len(y))
-		fmt.Printf("len(po2): %d\n", len(po2))
+		return nil, lengthError("CalculateR", len(aR), len(sR), len(y), len(po2))
 	}
 
-	result := make([]*big.Int, len(aR))
-
-	z2 := new(big.Int).Exp(z, big.NewInt(2), EC.N)
-	tmp11 := VectorAddScalar(aR, z)
-	tmp12 := ScalarVectorMul(sR, x)
-	tmp1 := VectorHadamard(y, VectorAdd(tmp11, tmp12))
-	tmp2 := ScalarVectorMul(po2, z2)
-
-	result = VectorAdd(tmp1, tmp2)
-
-	return result
+	z2 := new(big.Int).Exp(z, big.NewInt(2), ec.N)
+	return ec.calculateRMRP(aR, sR, y, ec.ScalarVectorMul(po2, z2), z, x), nil
 }
 
 /*
 RPProver : Range Proof Prove
 
-Given a value v, provides a range proof that v is inside 0 to 2^64-1
-*/
-func RPProve(v *big.Int) RangeProof {
+Given a value v, provides a range proof that v is inside 0 to 2^ec.V-1
 
-	rpresult := RangeProof{}
-
-	PowerOfTwos := PowerVector(EC.V, big.NewInt(2))
-
-	if v.Cmp(big.NewInt(0)) == -1 {
-		panic("Value is below range! Not proving")
-	}
-
-	if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(EC.V)), EC.N)) == 1 {
-		panic("Value is above range! Not proving.")
-	}
-
-	gamma, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-	comm := EC.G.Mult(v).Add(EC.H.Mult(gamma))
-	rpresult.Comm.Comm = comm
-
-	// break up v into its bitwise representation
-	//aL := 0
-	aL := reverse(StrToBigIntArray(PadLeft(fmt.Sprintf("%b", v), "0", EC.V)))
-	aR := VectorAddScalar(aL, big.NewInt(-1))
-
-	alpha, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	A := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, aL, aR).Add(EC.H.Mult(alpha))
-	rpresult.A = A
-
-	sL := RandVector(EC.V)
-	sR := RandVector(EC.V)
-
-	rho, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
-	rpresult.S = S
-
-	chal1s256 := sha256.Sum256([]byte(A.X.String() + A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
-
-	chal2s256 := sha256.Sum256([]byte(S.X.String() + S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
-
-	z2 := new(big.Int).Exp(cz, big.NewInt(2), EC.N)
-	// need to generate l(X), r(X), and t(X)=<l(X),r(X)>
-
-
-	PowerOfCY := PowerVector(EC.V, cy)
-	// fmt.Println(PowerOfCY)
-	l0 := VectorAddScalar(aL, new(big.Int).Neg(cz))
-	// l1 := sL
-	r0 := VectorAdd(
-		VectorHadamard(
-			PowerOfCY,
-			VectorAddScalar(aR, cz)),
-		ScalarVectorMul(
-			PowerOfTwos,
-			z2))
-	r1 := VectorHadamard(sR, PowerOfCY)
-
-	//calculate t0
-	t0 := new(big.Int).Mod(new(big.Int).Add(new(big.Int).Mul(v, z2), Delta(PowerOfCY, cz)), EC.N)
-
-	t1 := new(big.Int).Mod(new(big.Int).Add(InnerProduct(sL, r0), InnerProduct(l0, r1)), EC.N)
-	t2 := InnerProduct(sL, r1)
-
-	// given the t_i values, we can generate commitments to them
-	tau1, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-	tau2, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	T1 := EC.G.Mult(t1).Add(EC.H.Mult(tau1)) //commitment to t1
-	T2 := EC.G.Mult(t2).Add(EC.H.Mult(tau2)) //commitment to t2
-
-	rpresult.T1 = T1
-	rpresult.T2 = T2
-
-	chal3s256 := sha256.Sum256([]byte(T1.X.String() + T1.Y.String() + T2.X.String() + T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
-
-	left := CalculateL(aL, sL, cz, cx)
-	right := CalculateR(aR, sR, PowerOfCY, PowerOfTwos, cz, cx)
-
-	thatPrime := new(big.Int).Mod( // t0 + t1*x + t2*x^2
-		new(big.Int).Add(
-			t0,
-			new(big.Int).Add(
-				new(big.Int).Mul(
-					t1, cx),
-				new(big.Int).Mul(
-					new(big.Int).Mul(cx, cx),
-					t2))), EC.N)
-
-	that := InnerProduct(left, right) // NOTE: BP Java implementation calculates this from the t_i
-
-	// thatPrime and that should be equal
-	if thatPrime.Cmp(that) != 0 {
-		fmt.Println("Proving -- Uh oh! Two diff ways to compute same value not working")
-		fmt.Printf("\tthatPrime = %s\n", thatPrime.String())
-		fmt.Printf("\tthat = %s \n", that.String())
-	}
-
-	rpresult.Th = thatPrime
-
-	taux1 := new(big.Int).Mod(new(big.Int).Mul(tau2, new(big.Int).Mul(cx, cx)), EC.N)
-	taux2 := new(big.Int).Mod(new(big.Int).Mul(tau1, cx), EC.N)
-	taux3 := new(big.Int).Mod(new(big.Int).Mul(z2, gamma), EC.N)
-	taux := new(big.Int).Mod(new(big.Int).Add(taux1, new(big.Int).Add(taux2, taux3)), EC.N)
-
-	rpresult.Tau = taux
-
-	mu := new(big.Int).Mod(new(big.Int).Add(alpha, new(big.Int).Mul(rho, cx)), EC.N)
-	rpresult.Mu = mu
-
-	HPrime := make([]ECPoint, len(EC.BPH))
-
-	for i := range HPrime {
-		HPrime[i] = EC.BPH[i].Mult(new(big.Int).ModInverse(PowerOfCY[i], EC.N))
-	}
-
-	// for testing
-	tmp1 := EC.Zero()
-	zneg := new(big.Int).Mod(new(big.Int).Neg(cz), EC.N)
-	for i := range EC.BPG {
-		tmp1 = tmp1.Add(EC.BPG[i].Mult(zneg))
-	}
-
-	tmp2 := EC.Zero()
-	for i := range HPrime {
-		val1 := new(big.Int).Mul(cz, PowerOfCY[i])
-		val2 := new(big.Int).Mul(new(big.Int).Mul(cz, cz), PowerOfTwos[i])
-		tmp2 = tmp2.Add(HPrime[i].Mult(new(big.Int).Add(val1, val2)))
+Use RPProveBits to choose the bit length.
+*/
+func (ec *CryptoParams) RPProve(v *big.Int) (RangeProof, error) {
+	gamma, err := ec.randScalar()
+	if err != nil {
+		return RangeProof{}, err
 	}
 
-	P1 := A.Add(S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.U.Mult(that)).Add(EC.H.Mult(mu).Neg())
-
-	P2 := TwoVectorPCommitWithGens(EC.BPG, HPrime, left, right)
-	fmt.Println(P1)
-	fmt.Println(P2)
-
-	rpresult.IPP = InnerProductProve(left, right, that, P2, EC.U, EC.BPG, HPrime)
-
-	return rpresult
+	return ec.rpProve(gamma, v, ec.V)
 }
 
 /*
-RPProveTrans : Range Proof Prover customised for transactions
+RPProveBits : Range Proof Prove with an explicit bit length
 
-Given a value v, provides a range proof that v is inside 0 to 2^64-1
+Given a value v, provides a range proof that v is inside 0 to 2^n-1. n must be
+one of BitLengths and ec must have at least n generators.
 */
-func RPProveTrans(gamma *big.Int, v *big.Int) RangeProof {
-
-	rpresult := RangeProof{}
-
-	PowerOfTwos := PowerVector(EC.V, big.NewInt(2))
-
-	if v.Cmp(big.NewInt(0)) == -1 {
-		panic("Value is below range! Not proving")
-	}
-
-	if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(EC.V)), EC.N)) == 1 {
-		panic("Value is above range! Not proving.")
-	}
-
-	comm := EC.G.Mult(v).Add(EC.H.Mult(gamma))
-	rpresult.Comm.Comm = comm
-
-	// break up v into its bitwise representation
-	//aL := 0
-	aL := reverse(StrToBigIntArray(PadLeft(fmt.Sprintf("%b", v), "0", EC.V)))
-	aR := VectorAddScalar(aL, big.NewInt(-1))
-
-	alpha, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	A := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, aL, aR).Add(EC.H.Mult(alpha))
-	rpresult.A = A
-
-	sL := RandVector(EC.V)
-	sR := RandVector(EC.V)
-
-	rho, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
-	rpresult.S = S
-
-	chal1s256 := sha256.Sum256([]byte(A.X.String() + A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
-
-	chal2s256 := sha256.Sum256([]byte(S.X.String() + S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
-
-	z2 := new(big.Int).Exp(cz, big.NewInt(2), EC.N)
-	// need to generate l(X), r(X), and t(X)=<l(X),r(X)>
-
-	/*
-			Java code on how to calculate t1 and t2
-
-				FieldVector ys = FieldVector.from(VectorX.iterate(n, BigInteger.ONE, y::multiply),q); //powers of y
-			    FieldVector l0 = aL.add(z.negate());
-		        FieldVector l1 = sL;
-		        FieldVector twoTimesZSquared = twos.times(zSquared);
-		        FieldVector r0 = ys.hadamard(aR.add(z)).add(twoTimesZSquared);
-		        FieldVector r1 = sR.hadamard(ys);
-		        BigInteger k = ys.sum().multiply(z.subtract(zSquared)).subtract(zCubed.shiftLeft(n).subtract(zCubed));
-		        BigInteger t0 = k.add(zSquared.multiply(number));
-		        BigInteger t1 = l1.innerPoduct(r0).add(l0.innerPoduct(r1));
-		        BigInteger t2 = l1.innerPoduct(r1);
-		   		PolyCommitment<T> polyCommitment = PolyCommitment.from(base, t0, VectorX.of(t1, t2));
-
-
-	*/
-	PowerOfCY := PowerVector(EC.V, cy)
-	// fmt.Println(PowerOfCY)
-	l0 := VectorAddScalar(aL, new(big.Int).Neg(cz))
-	// l1 := sL
-	r0 := VectorAdd(
-		VectorHadamard(
-			PowerOfCY,
-			VectorAddScalar(aR, cz)),
-		ScalarVectorMul(
-			PowerOfTwos,
-			z2))
-	r1 := VectorHadamard(sR, PowerOfCY)
-
-	//calculate t0
-	t0 := new(big.Int).Mod(new(big.Int).Add(new(big.Int).Mul(v, z2), Delta(PowerOfCY, cz)), EC.N)
-
-	t1 := new(big.Int).Mod(new(big.Int).Add(InnerProduct(sL, r0), InnerProduct(l0, r1)), EC.N)
-	t2 := InnerProduct(sL, r1)
-
-	// given the t_i values, we can generate commitments to them
-	tau1, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-	tau2, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	T1 := EC.G.Mult(t1).Add(EC.H.Mult(tau1)) //commitment to t1
-	T2 := EC.G.Mult(t2).Add(EC.H.Mult(tau2)) //commitment to t2
-
-	rpresult.T1 = T1
-	rpresult.T2 = T2
-
-	chal3s256 := sha256.Sum256([]byte(T1.X.String() + T1.Y.String() + T2.X.String() + T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
-
-	left := CalculateL(aL, sL, cz, cx)
-	right := CalculateR(aR, sR, PowerOfCY, PowerOfTwos, cz, cx)
-
-	thatPrime := new(big.Int).Mod( // t0 + t1*x + t2*x^2
-		new(big.Int).Add(
-			t0,
-			new(big.Int).Add(
-				new(big.Int).Mul(
-					t1, cx),
-				new(big.Int).Mul(
-					new(big.Int).Mul(cx, cx),
-					t2))), EC.N)
-
-	that := InnerProduct(left, right) // NOTE: BP Java implementation calculates this from the t_i
-
-	// thatPrime and that should be equal
-	if thatPrime.Cmp(that) != 0 {
-		fmt.Println("Proving -- Uh oh! Two diff ways to compute same value not working")
-		fmt.Printf("\tthatPrime = %s\n", thatPrime.String())
-		fmt.Printf("\tthat = %s \n", that.String())
+func (ec *CryptoParams) RPProveBits(v *big.Int, n int) (RangeProof, error) {
+	if err := checkBitLength(n); err != nil {
+		return RangeProof{}, err
 	}
-
-	rpresult.Th = thatPrime
-
-	taux1 := new(big.Int).Mod(new(big.Int).Mul(tau2, new(big.Int).Mul(cx, cx)), EC.N)
-	taux2 := new(big.Int).Mod(new(big.Int).Mul(tau1, cx), EC.N)
-	taux3 := new(big.Int).Mod(new(big.Int).Mul(z2, gamma), EC.N)
-	taux := new(big.Int).Mod(new(big.Int).Add(taux1, new(big.Int).Add(taux2, taux3)), EC.N)
-
-	rpresult.Tau = taux
-
-	mu := new(big.Int).Mod(new(big.Int).Add(alpha, new(big.Int).Mul(rho, cx)), EC.N)
-	rpresult.Mu = mu
-
-	HPrime := make([]ECPoint, len(EC.BPH))
-
-	for i := range HPrime {
-		HPrime[i] = EC.BPH[i].Mult(new(big.Int).ModInverse(PowerOfCY[i], EC.N))
+	gamma, err := ec.randScalar()
+	if err != nil {
+		return RangeProof{}, err
 	}
 
-
-	P := TwoVectorPCommitWithGens(EC.BPG, HPrime, left, right)
-
-	rpresult.IPP = InnerProductProve(left, right, that, P, EC.U, EC.BPG, HPrime)
-
-	return rpresult
+	return ec.rpProve(gamma, v, n)
 }
 
-func RPVerify(rp RangeProof) bool {
-	// create the challenge variables
-	chal1s256 := sha256.Sum256([]byte(rp.A.X.String() + rp.A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
-
-	chal2s256 := sha256.Sum256([]byte(rp.S.X.String() + rp.S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
-
-	chal3s256 := sha256.Sum256([]byte(rp.T1.X.String() + rp.T1.Y.String() + rp.T2.X.String() + rp.T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
-
-	// given challenges are correct, very range proof
-	PowersOfY := PowerVector(EC.V, cy)
-
-	// t_hat * G + tau * H
-	lhs := EC.G.Mult(rp.Th).Add(EC.H.Mult(rp.Tau))
-
-	// z^2 * V + delta(y,z) * G + x * T1 + x^2 * T2
-	rhs := rp.Comm.Comm.Mult(new(big.Int).Mul(cz, cz)).Add(
-		EC.G.Mult(Delta(PowersOfY, cz))).Add(
-		rp.T1.Mult(cx)).Add(
-		rp.T2.Mult(new(big.Int).Mul(cx, cx)))
-
-	if !lhs.Equal(rhs) {
-		fmt.Println("RPVerify - Uh oh! Check line (63) of verification")
-		fmt.Println(rhs)
-		fmt.Println(lhs)
-		return false
-	}
-
-	tmp1 := EC.Zero()
-	zneg := new(big.Int).Mod(new(big.Int).Neg(cz), EC.N)
-	for i := range EC.BPG {
-		tmp1 = tmp1.Add(EC.BPG[i].Mult(zneg))
-	}
-
-	PowerOfTwos := PowerVector(EC.V, big.NewInt(2))
-	tmp2 := EC.Zero()
-	// generate h'
-	HPrime := make([]ECPoint, len(EC.BPH))
-
-	for i := range HPrime {
-		mi := new(big.Int).ModInverse(PowersOfY[i], EC.N)
-		HPrime[i] = EC.BPH[i].Mult(mi)
-	}
-
-	for i := range HPrime {
-		val1 := new(big.Int).Mul(cz, PowersOfY[i])
-		val2 := new(big.Int).Mul(new(big.Int).Mul(cz, cz), PowerOfTwos[i])
-		tmp2 = tmp2.Add(HPrime[i].Mult(new(big.Int).Add(val1, val2)))
-	}
-
-	// without subtracting this value should equal muCH + l[i]G[i] + r[i]H'[i]
-	// we want to make sure that the innerproduct checks out, so we subtract it
-	P := rp.A.Add(rp.S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.H.Mult(rp.Mu).Neg())
-	//fmt.Println(P)
+/*
+RPProveTrans : Range Proof Prover customised for transactions
 
-	if !InnerProductVerifyFast(rp.Th, P, EC.U, EC.BPG, HPrime, rp.IPP) {
-		fmt.Println("RPVerify - Uh oh! Check line (65) of verification!")
-		return false
-	}
+Given a value v, provides a range proof that v is inside 0 to 2^ec.V-1
 
-	return true
+A single range proof is an aggregate proof over one value, so this shares its
+prover with MRPProveTrans.
+*/
+func (ec *CryptoParams) RPProveTrans(gamma *big.Int, v *big.Int) (RangeProof, error) {
+	return ec.rpProve(gamma, v, ec.V)
 }
 
-func RPVerifyTrans(comm *ECPoint, rp *RangeProof) bool {
-	// Create the challenges
-	chal1s256 := sha256.Sum256([]byte(rp.A.X.String() + rp.A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
-
-	chal2s256 := sha256.Sum256([]byte(rp.S.X.String() + rp.S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
-
-	chal3s256 := sha256.Sum256([]byte(rp.T1.X.String() + rp.T1.Y.String() + rp.T2.X.String() + rp.T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
-
-	// given challenges are correct, very range proof
-	PowersOfY := PowerVector(EC.V, cy)
-
-	// t_hat * G + tau * H
-	lhs := EC.G.Mult(rp.Th).Add(EC.H.Mult(rp.Tau))
-
-	// z^2 * V + delta(y,z) * G + x * T1 + x^2 * T2
-	rhs := comm.Mult(new(big.Int).Mul(cz, cz)).Add(
-		EC.G.Mult(Delta(PowersOfY, cz))).Add(
-		rp.T1.Mult(cx)).Add(
-		rp.T2.Mult(new(big.Int).Mul(cx, cx)))
-
-	if !lhs.Equal(rhs) {
-		fmt.Println("RPVerify - Uh oh! Check line (63) of verification")
-		fmt.Println(rhs)
-		fmt.Println(lhs)
-		return false
-	}
-
-	tmp1 := EC.Zero()
-	zneg := new(big.Int).Mod(new(big.Int).Neg(cz), EC.N)
-	for i := range EC.BPG {
-		tmp1 = tmp1.Add(EC.BPG[i].Mult(zneg))
+// RPProveTransBits - see RPProveTrans and RPProveBits
+func (ec *CryptoParams) RPProveTransBits(gamma *big.Int, v *big.Int, n int) (RangeProof, error) {
+	if err := checkBitLength(n); err != nil {
+		return RangeProof{}, err
 	}
+	return ec.rpProve(gamma, v, n)
+}
 
-	PowerOfTwos := PowerVector(EC.V, big.NewInt(2))
-	tmp2 := EC.Zero()
-	// generate h'
-	HPrime := make([]ECPoint, len(EC.BPH))
+func (ec *CryptoParams) rpProve(gamma *big.Int, v *big.Int, n int) (RangeProof, error) {
+	mrp, comms, err := ec.mrpProve([]*big.Int{v}, []*big.Int{gamma}, n)
+	if err != nil {
+		return RangeProof{}, err
+	}
+
+	return RangeProof{
+		Comm: Commitment{Comm: comms[0]},
+		Bits: mrp.Bits,
+		A:    mrp.A,
+		S:    mrp.S,
+		T1:   mrp.T1,
+		T2:   mrp.T2,
+		Tau:  mrp.Tau,
+		Th:   mrp.Th,
+		Mu:   mrp.Mu,
+		IPP:  mrp.IPP,
+	}, nil
+}
 
-	for i := range HPrime {
-		mi := new(big.Int).ModInverse(PowersOfY[i], EC.N)
-		HPrime[i] = EC.BPH[i].Mult(mi)
-	}
+// RPVerify verifies a range proof against the commitment stored in the proof
+func (ec *CryptoParams) RPVerify(rp RangeProof) (bool, error) {
+	return ec.RPVerifyTrans(&rp.Comm.Comm, &rp)
+}
 
-	for i := range HPrime {
-		val1 := new(big.Int).Mul(cz, PowersOfY[i])
-		val2 := new(big.Int).Mul(new(big.Int).Mul(cz, cz), PowerOfTwos[i])
-		tmp2 = tmp2.Add(HPrime[i].Mult(new(big.Int).Add(val1, val2)))
+// RPVerifyTrans verifies a range proof against the commitment comm
+func (ec *CryptoParams) RPVerifyTrans(comm *ECPoint, rp *RangeProof) (bool, error) {
+	if comm == nil || rp == nil {
+		return false, fmt.Errorf("%w: missing commitment or proof", ErrMalformedProof)
 	}
+	return ec.MRPVerify(rp.multi(), []ECPoint{*comm})
+}
 
-	// without subtracting this value should equal muCH + l[i]G[i] + r[i]H'[i]
-	// we want to make sure that the innerproduct checks out, so we subtract it
-	P := rp.A.Add(rp.S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.H.Mult(rp.Mu).Neg())
-	//fmt.Println(P)
-
-	if !InnerProductVerifyFast(rp.Th, P, EC.U, EC.BPG, HPrime, rp.IPP) {
-		fmt.Println("RPVerify - Uh oh! Check line (65) of verification!")
-		return false
+// RPVerifyBits verifies that rp shows the value committed to in comm is in
+// [0, 2^n)
+func (ec *CryptoParams) RPVerifyBits(comm *ECPoint, rp *RangeProof, n int) (bool, error) {
+	if comm == nil || rp == nil {
+		return false, fmt.Errorf("%w: missing commitment or proof", ErrMalformedProof)
 	}
-
-	return true
+	return ec.MRPVerifyBits(rp.multi(), []ECPoint{*comm}, n)
 }
 
 // Calculates (aL - z*1^n) + sL*x
-func CalculateLMRP(aL, sL []*big.Int, z, x *big.Int) []*big.Int {
-	result := make([]*big.Int, len(aL))
-
-	tmp1 := VectorAddScalar(aL, new(big.Int).Neg(z))
-	tmp2 := ScalarVectorMul(sL, x)
-
-	result = VectorAdd(tmp1, tmp2)
-
-	return result
+func (ec *CryptoParams) CalculateLMRP(aL, sL []*big.Int, z, x *big.Int) ([]*big.Int, error) {
+	return ec.CalculateL(aL, sL, z, x)
 }
 
-func CalculateRMRP(aR, sR, y, zTimesTwo []*big.Int, z, x *big.Int) []*big.Int {
+func (ec *CryptoParams) CalculateRMRP(aR, sR, y, zTimesTwo []*big.Int, z, x *big.Int) ([]*big.Int, error) {
 	if len(aR) != len(sR) || len(aR) != len(y) || len(y) != len(zTimesTwo) {
-		fmt.Println("CalculateR: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(aR): %d\n", len(aR))
-		fmt.Printf("len(sR): %d\n", len(sR))
-		fmt.Printf("len(y): %d\n", len(y))
-		fmt.Printf("len(po2): %d\n", len(zTimesTwo))
+		return nil, lengthError("CalculateRMRP", len(aR), len(sR), len(y), len(zTimesTwo))
 	}
+	return ec.calculateRMRP(aR, sR, y, zTimesTwo, z, x), nil
+}
 
-	result := make([]*big.Int, len(aR))
-
-	tmp11 := VectorAddScalar(aR, z)
-	tmp12 := ScalarVectorMul(sR, x)
-	tmp1 := VectorHadamard(y, VectorAdd(tmp11, tmp12))
-
-	result = VectorAdd(tmp1, zTimesTwo)
+func (ec *CryptoParams) calculateRMRP(aR, sR, y, zTimesTwo []*big.Int, z, x *big.Int) []*big.Int {
+	tmp11 := ec.VectorAddScalar(aR, z)
+	tmp12 := ec.ScalarVectorMul(sR, x)
+	tmp1 := ec.vectorHadamard(y, ec.vectorAdd(tmp11, tmp12))
 
-	return result
+	return ec.vectorAdd(tmp1, zTimesTwo)
 }
 
 /*
@@ -1035,32 +804,33 @@ DeltaMRP is a helper function that is used in the multi range proof
 \delta(y, z) = (z-z^2)<1^n, y^n> - \sum_j z^3+j<1^n, 2^n>
 */
 
-func DeltaMRP(y []*big.Int, z *big.Int, m int) *big.Int {
+func (ec *CryptoParams) DeltaMRP(y []*big.Int, z *big.Int, m int) *big.Int {
 	result := big.NewInt(0)
 
 	// (z-z^2)<1^n, y^n>
-	z2 := new(big.Int).Mod(new(big.Int).Mul(z, z), EC.N)
-	t1 := new(big.Int).Mod(new(big.Int).Sub(z, z2), EC.N)
-	t2 := new(big.Int).Mod(new(big.Int).Mul(t1, VectorSum(y)), EC.N)
+	z2 := new(big.Int).Mod(new(big.Int).Mul(z, z), ec.N)
+	t1 := new(big.Int).Mod(new(big.Int).Sub(z, z2), ec.N)
+	t2 := new(big.Int).Mod(new(big.Int).Mul(t1, ec.VectorSum(y)), ec.N)
 
 	// \sum_j z^3+j<1^n, 2^n>
 	// <1^n, 2^n> = 2^n - 1
-	po2sum := new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(EC.V/m)), EC.N), big.NewInt(1))
+	po2sum := new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(len(y)/m)), ec.N), big.NewInt(1))
 	t3 := big.NewInt(0)
 
 	for j := 0; j < m; j++ {
-		zp := new(big.Int).Exp(z, big.NewInt(3+int64(j)), EC.N)
-		tmp1 := new(big.Int).Mod(new(big.Int).Mul(zp, po2sum), EC.N)
-		t3 = new(big.Int).Mod(new(big.Int).Add(t3, tmp1), EC.N)
+		zp := new(big.Int).Exp(z, big.NewInt(3+int64(j)), ec.N)
+		tmp1 := new(big.Int).Mod(new(big.Int).Mul(zp, po2sum), ec.N)
+		t3 = new(big.Int).Mod(new(big.Int).Add(t3, tmp1), ec.N)
 	}
 
-	result = new(big.Int).Mod(new(big.Int).Sub(t2, t3), EC.N)
+	result = new(big.Int).Mod(new(big.Int).Sub(t2, t3), ec.N)
 
 	return result
 }
 
 type MultiRangeProof struct {
 	Comms []Commitment
+	Bits  int // every value is proven to be in [0, 2^Bits)
 	A     ECPoint
 	S     ECPoint
 	T1    ECPoint
@@ -1069,184 +839,75 @@ type MultiRangeProof struct {
 	Th    *big.Int
 	Mu    *big.Int
 	IPP   InnerProdArg
-
 }
 
-
-/*
-MultiRangeProof Prove
-Takes in a list of values and provides an aggregate
-range proof for all the values.
-
-changes:
- all values are concatenated
- r(x) is computed differently
- tau_x calculation is different
- delta calculation is different
-
-{(g, h \in G, \textbf{V} \in G^m ; \textbf{v, \gamma} \in Z_p^m) :
-	V_j = h^{\gamma_j}g^{v_j} \wedge v_j \in [0, 2^n - 1] \forall j \in [1, m]}
-*/
-func MRPProve(values []*big.Int) ([]ECPoint, MultiRangeProof) {
-	// EC.V has the total number of values and bits we can support
-
-	MRPResult := MultiRangeProof{}
-
-	m := len(values)
-	bitsPerValue := EC.V / m
-
-	// we concatenate the binary representation of the values
-
-	PowerOfTwos := PowerVector(bitsPerValue, big.NewInt(2))
-
-	Comms := make([]ECPoint, m)
-	gammas := make([]*big.Int, m)
-	aLConcat := make([]*big.Int, EC.V)
-	aRConcat := make([]*big.Int, EC.V)
-
-	for j := range values {
-		v := values[j]
-		if v.Cmp(big.NewInt(0)) == -1 {
-			panic("Value is below range! Not proving")
-		}
-
-		if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(bitsPerValue)), EC.N)) == 1 {
-			panic("Value is above range! Not proving.")
-		}
-
-		gamma, err := rand.Int(rand.Reader, EC.N)
-		check(err)
-		Comms[j]= EC.G.Mult(v).Add(EC.H.Mult(gamma))
-		gammas[j] = gamma
-
-		// break up v into its bitwise representation
-		aL := reverse(StrToBigIntArray(PadLeft(fmt.Sprintf("%b", v), "0", bitsPerValue)))
-		aR := VectorAddScalar(aL, big.NewInt(-1))
-
-		for i := range aR {
-			aLConcat[bitsPerValue*j+i] = aL[i]
-			aRConcat[bitsPerValue*j+i] = aR[i]
-		}
+// check makes sure every field of the proof is present and every point is
+// in g
+func (mrp *MultiRangeProof) check(g Group) error {
+	if mrp.Tau == nil || mrp.Th == nil || mrp.Mu == nil {
+		return fmt.Errorf("%w: missing tau, t hat or mu", ErrMalformedProof)
 	}
+	return checkPoints(g, mrp.A, mrp.S, mrp.T1, mrp.T2)
+}
 
-
-	alpha, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	A := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, aLConcat, aRConcat).Add(EC.H.Mult(alpha))
-	MRPResult.A = A
-
-	sL := RandVector(EC.V)
-	sR := RandVector(EC.V)
-
-	rho, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
-	MRPResult.S = S
-
-	chal1s256 := sha256.Sum256([]byte(A.X.String() + A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
-
-	chal2s256 := sha256.Sum256([]byte(S.X.String() + S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
-
-	zPowersTimesTwoVec := make([]*big.Int, EC.V)
-	for j := 0; j < m; j++ {
-		zp := new(big.Int).Exp(cz, big.NewInt(2+int64(j)), EC.N)
-		for i := 0; i < bitsPerValue; i++ {
-			zPowersTimesTwoVec[j*bitsPerValue+i] = new(big.Int).Mod(new(big.Int).Mul(PowerOfTwos[i], zp), EC.N)
-		}
+// bitsPerValue returns the default number of bits each of m values is proven
+// over when the caller does not give one, or ErrLengthMismatch if the vector
+// length cannot be split evenly between m values padded to a power of two
+func (ec *CryptoParams) bitsPerValue(m int) (int, error) {
+	m = padCount(m)
+	if m == 0 || ec.V%m != 0 || !isPowerOfTwo(ec.V) {
+		return 0, lengthError("bitsPerValue", ec.V, m)
 	}
+	return ec.V / m, nil
+}
 
-	PowerOfCY := PowerVector(EC.V, cy)
-	// fmt.Println(PowerOfCY)
-	l0 := VectorAddScalar(aLConcat, new(big.Int).Neg(cz))
-	l1 := sL
-	r0 := VectorAdd(
-		VectorHadamard(
-			PowerOfCY,
-			VectorAddScalar(aRConcat, cz)),
-		zPowersTimesTwoVec)
-	r1 := VectorHadamard(sR, PowerOfCY)
-
-	//calculate t0
-	vz2 := big.NewInt(0)
-	z2 := new(big.Int).Mod(new(big.Int).Mul(cz, cz), EC.N)
-	PowerOfCZ := PowerVector(m, cz)
-	for j := 0; j < m; j++ {
-		vz2 = new(big.Int).Add(vz2,
-			new(big.Int).Mul(
-				PowerOfCZ[j],
-				new(big.Int).Mul(values[j], z2)))
-		vz2 = new(big.Int).Mod(vz2, EC.N)
+// padCount returns the number of values an aggregate proof of m values is
+// made over, which is m rounded up to a power of two
+func padCount(m int) int {
+	if m <= 1 {
+		return m
 	}
+	return 1 << uint(bits.Len(uint(m-1)))
+}
 
-	t0 := new(big.Int).Mod(new(big.Int).Add(vz2, DeltaMRP(PowerOfCY, cz, m)), EC.N)
-
-	t1 := new(big.Int).Mod(new(big.Int).Add(InnerProduct(l1, r0), InnerProduct(l0, r1)), EC.N)
-	t2 := InnerProduct(l1, r1)
-
-	// given the t_i values, we can generate commitments to them
-	tau1, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-	tau2, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	T1 := EC.G.Mult(t1).Add(EC.H.Mult(tau1)) //commitment to t1
-	T2 := EC.G.Mult(t2).Add(EC.H.Mult(tau2)) //commitment to t2
-
-	MRPResult.T1 = T1
-	MRPResult.T2 = T2
-
-	chal3s256 := sha256.Sum256([]byte(T1.X.String() + T1.Y.String() + T2.X.String() + T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
-
-	left := CalculateLMRP(aLConcat, sL, cz, cx)
-	right := CalculateRMRP(aRConcat, sR, PowerOfCY, zPowersTimesTwoVec, cz, cx)
+// padCommitments appends the commitments to the zero values the prover pads
+// an aggregate proof with, which are all the point at infinity
+func padCommitments(comms []ECPoint) []ECPoint {
+	padded := append(make([]ECPoint, 0, padCount(len(comms))), comms...)
+	for len(padded) < cap(padded) {
+		padded = append(padded, ECPoint{big.NewInt(0), big.NewInt(0)})
+	}
+	return padded
+}
 
-	thatPrime := new(big.Int).Mod( // t0 + t1*x + t2*x^2
-		new(big.Int).Add(t0, new(big.Int).Add(new(big.Int).Mul(t1, cx), new(big.Int).Mul(new(big.Int).Mul(cx, cx), t2))), EC.N)
+// BitLengths - the bit lengths that can be passed to the *Bits provers and
+// verifiers
+var BitLengths = []int{8, 16, 32, 64, 128}
 
-	that := InnerProduct(left, right) // NOTE: BP Java implementation calculates this from the t_i
+// maxBits - the largest bit length any proof may be over. A range proof over
+// more bits than the group order has would not be sound.
+const maxBits = 128
 
-	// thatPrime and that should be equal
-	if thatPrime.Cmp(that) != 0 {
-		fmt.Println("Proving -- Uh oh! Two diff ways to compute same value not working")
-		fmt.Printf("\tthatPrime = %s\n", thatPrime.String())
-		fmt.Printf("\tthat = %s \n", that.String())
+// checkBitLength returns ErrUnsupportedBitLength if n is not in BitLengths
+func checkBitLength(n int) error {
+	for _, b := range BitLengths {
+		if n == b {
+			return nil
+		}
 	}
+	return fmt.Errorf("%w: %d bits, expected one of %v", ErrUnsupportedBitLength, n, BitLengths)
+}
 
-	MRPResult.Th = that
-
-	vecRandomnessTotal := big.NewInt(0)
-	for j := 0; j < m; j++ {
-		zp := new(big.Int).Exp(cz, big.NewInt(2+int64(j)), EC.N)
-		tmp1 := new(big.Int).Mul(gammas[j], zp)
-		vecRandomnessTotal = new(big.Int).Mod(new(big.Int).Add(vecRandomnessTotal, tmp1), EC.N)
+// proofSize returns the number of generators a proof of m values of n bits
+// each runs over, checking that ec has that many
+func (ec *CryptoParams) proofSize(n, m int) (int, error) {
+	if n < 1 || n > maxBits || !isPowerOfTwo(n) {
+		return 0, fmt.Errorf("%w: %d bits", ErrUnsupportedBitLength, n)
 	}
-	//fmt.Println(vecRandomnessTotal)
-	taux1 := new(big.Int).Mod(new(big.Int).Mul(tau2, new(big.Int).Mul(cx, cx)), EC.N)
-	taux2 := new(big.Int).Mod(new(big.Int).Mul(tau1, cx), EC.N)
-	taux := new(big.Int).Mod(new(big.Int).Add(taux1, new(big.Int).Add(taux2, vecRandomnessTotal)), EC.N)
-
-	MRPResult.Tau = taux
-
-	mu := new(big.Int).Mod(new(big.Int).Add(alpha, new(big.Int).Mul(rho, cx)), EC.N)
-	MRPResult.Mu = mu
-
-	HPrime := make([]ECPoint, len(EC.BPH))
-
-	for i := range HPrime {
-		HPrime[i] = EC.BPH[i].Mult(new(big.Int).ModInverse(PowerOfCY[i], EC.N))
+	if m == 0 || !isPowerOfTwo(m) || n*m > len(ec.BPG) || n*m > len(ec.BPH) {
+		return 0, lengthError("proofSize", n, m, len(ec.BPG), len(ec.BPH))
 	}
-
-	P := TwoVectorPCommitWithGens(EC.BPG, HPrime, left, right)
-	//fmt.Println(P)
-
-	MRPResult.IPP = InnerProductProve(left, right, that, P, EC.U, EC.BPG, HPrime)
-
-	return Comms, MRPResult
+	return n * m, nil
 }
 
 /*
@@ -1254,6 +915,11 @@ MultiRangeProof Prove
 Takes in a list of values and provides an aggregate
 range proof for all the values.
 
+Any number of values can be aggregated. The proof is made over the values
+padded with zeros to the next power of two, and the commitments to that
+padding are the point at infinity, so only the commitments to the given
+values are returned and passed to MRPVerify.
+
 changes:
  all values are concatenated
  r(x) is computed differently
@@ -1263,264 +929,436 @@ changes:
 {(g, h \in G, \textbf{V} \in G^m ; \textbf{v, \gamma} \in Z_p^m) :
 	V_j = h^{\gamma_j}g^{v_j} \wedge v_j \in [0, 2^n - 1] \forall j \in [1, m]}
 */
-func MRPProveTrans(values []*big.Int, sSecret *big.Int) (MultiRangeProof, []ECPoint) {
-	// EC.V has the total number of values and bits we can support
+func (ec *CryptoParams) MRPProve(values []*big.Int) ([]ECPoint, MultiRangeProof, error) {
+	n, err := ec.bitsPerValue(len(values))
+	if err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+	return ec.mrpProveRandom(values, n)
+}
 
-	MRPResult := MultiRangeProof{}
+/*
+MRPProveBits - MultiRangeProof Prove with an explicit bit length
 
-	m := len(values)
-	bitsPerValue := EC.V / m
+Same as MRPProve, except every value is proven to be in [0, 2^n) whatever
+the number of values. n must be one of BitLengths and the proof uses the
+first n*len(values) generators of ec.
+*/
+func (ec *CryptoParams) MRPProveBits(values []*big.Int, n int) ([]ECPoint, MultiRangeProof, error) {
+	if err := checkBitLength(n); err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+	return ec.mrpProveRandom(values, n)
+}
 
-	// we concatenate the binary representation of the values
+func (ec *CryptoParams) mrpProveRandom(values []*big.Int, n int) ([]ECPoint, MultiRangeProof, error) {
+	gammas, err := ec.RandVector(len(values))
+	if err != nil {
+		return nil, MultiRangeProof{}, err
+	}
 
-	PowerOfTwos := PowerVector(bitsPerValue, big.NewInt(2))
+	MRPResult, Comms, err := ec.mrpProve(values, gammas, n)
+	return Comms, MRPResult, err
+}
 
-	Comms := make([]ECPoint, m)
-	Blinds := make([]*big.Int, m)
-	aLConcat := make([]*big.Int, EC.V)
-	aRConcat := make([]*big.Int, EC.V)
+/*
+MultiRangeProof Prove
+Same as MRPProve, except the blinding factors are derived deterministically
+from sSecret and each value so the receiver of a transaction can recreate them.
+*/
+func (ec *CryptoParams) MRPProveTrans(values []*big.Int, sSecret *big.Int) (MultiRangeProof, []ECPoint, error) {
+	n, err := ec.bitsPerValue(len(values))
+	if err != nil {
+		return MultiRangeProof{}, nil, err
+	}
+	return ec.mrpProveTrans(values, sSecret, n)
+}
 
-	for j := range values {
-		v := values[j]
-		if v.Cmp(big.NewInt(0)) == -1 {
-			panic("Value is below range! Not proving")
-		}
+// MRPProveTransBits - see MRPProveTrans and MRPProveBits
+func (ec *CryptoParams) MRPProveTransBits(values []*big.Int, sSecret *big.Int, n int) (MultiRangeProof, []ECPoint, error) {
+	if err := checkBitLength(n); err != nil {
+		return MultiRangeProof{}, nil, err
+	}
+	return ec.mrpProveTrans(values, sSecret, n)
+}
 
-		maxVal := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(bitsPerValue)), EC.N)
+func (ec *CryptoParams) mrpProveTrans(values []*big.Int, sSecret *big.Int, n int) (MultiRangeProof, []ECPoint, error) {
+	Blinds, err := transBlinds(values, sSecret)
+	if err != nil {
+		return MultiRangeProof{}, nil, err
+	}
 
-		if v.Cmp(maxVal) == 1 {
-			panic("Value is above range! Not proving.")
-		}
+	return ec.mrpProve(values, Blinds, n)
+}
 
+// transBlinds derives the blinding factor of each value from sSecret the same
+// way Commitment.Generate does
+func transBlinds(values []*big.Int, sSecret *big.Int) ([]*big.Int, error) {
+	Blinds := make([]*big.Int, len(values))
+	for j, v := range values {
+		if v == nil {
+			return nil, fmt.Errorf("%w: value %d is missing", ErrValueOutOfRange, j)
+		}
 		hash := sha256.Sum256(v.Bytes())
+		Blinds[j] = secp256k1.NonceRFC6979(sSecret, hash[:], nil, nil)
+	}
+	return Blinds, nil
+}
 
-		gamma := secp256k1.NonceRFC6979(sSecret, hash[:], nil, nil)
-		Comms[j] = EC.G.Mult(v).Add(EC.H.Mult(gamma))
-		Blinds[j] = gamma
-
-		// break up v into its bitwise representation
-		aL := reverse(StrToBigIntArray(PadLeft(fmt.Sprintf("%b", v), "0", bitsPerValue)))
-		aR := VectorAddScalar(aL, big.NewInt(-1))
+// mrpProve - the aggregate range prover behind every RPProve* and MRPProve*
+// function. gammas holds the blinding factor of each value's commitment and
+// every value is proven to fit in bitsPerValue bits.
+func (ec *CryptoParams) mrpProve(values, gammas []*big.Int, bitsPerValue int) (MultiRangeProof, []ECPoint, error) {
+	MRPResult := MultiRangeProof{Bits: bitsPerValue}
 
-		for i := range aR {
-			aLConcat[bitsPerValue*j+i] = aL[i]
-			aRConcat[bitsPerValue*j+i] = aR[i]
-		}
+	// the values are padded to a power of two with zeros under zero blinding
+	// factors, whose commitments are the point at infinity, so the verifier
+	// can put them back without being told about them
+	if len(gammas) != len(values) {
+		return MRPResult, nil, lengthError("mrpProve", len(values), len(gammas))
+	}
+	m := padCount(len(values))
+	size, err := ec.proofSize(bitsPerValue, m)
+	if err != nil {
+		return MRPResult, nil, err
 	}
 
-	//MRPResult.Comms = Comms
+	// Everything below that depends on the values or the blinding factors
+	// is a scalar and every point built from them goes through the constant
+	// time multiplications. The challenges and the generators are public
+	// and keep using the faster big.Int and jacobianPoint code.
+	Comms, vs, gs, aLConcat, aRConcat, err := ec.rangeProofBits(values, gammas, bitsPerValue, m)
+	if err != nil {
+		return MRPResult, nil, err
+	}
 
-	alpha, err := rand.Int(rand.Reader, EC.N)
-	check(err)
+	alpha, err := ec.fr.rand()
+	if err != nil {
+		return MRPResult, nil, err
+	}
 
-	A := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, aLConcat, aRConcat).Add(EC.H.Mult(alpha))
+	A := ec.ctCommit(nil, &alpha, aLConcat, aRConcat)
 	MRPResult.A = A
 
-	sL := RandVector(EC.V)
-	sR := RandVector(EC.V)
-
-	rho, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-
-	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
-	MRPResult.S = S
-
-	chal1s256 := sha256.Sum256([]byte(A.X.String() + A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
-
-	chal2s256 := sha256.Sum256([]byte(S.X.String() + S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
-
-	zPowersTimesTwoVec := make([]*big.Int, EC.V)
-	for j := 0; j < m; j++ {
-		zp := new(big.Int).Exp(cz, big.NewInt(2+int64(j)), EC.N)
-		for i := 0; i < bitsPerValue; i++ {
-			zPowersTimesTwoVec[j*bitsPerValue+i] = new(big.Int).Mod(new(big.Int).Mul(PowerOfTwos[i], zp), EC.N)
-		}
+	sL, err := ec.fr.rands(size)
+	if err != nil {
+		return MRPResult, nil, err
+	}
+	sR, err := ec.fr.rands(size)
+	if err != nil {
+		return MRPResult, nil, err
 	}
 
-
-	PowerOfCY := PowerVector(EC.V, cy)
-	// fmt.Println(PowerOfCY)
-	l0 := VectorAddScalar(aLConcat, new(big.Int).Neg(cz))
-	l1 := sL
-	r0 := VectorAdd(
-		VectorHadamard(
-			PowerOfCY,
-			VectorAddScalar(aRConcat, cz)),
-		zPowersTimesTwoVec)
-	r1 := VectorHadamard(sR, PowerOfCY)
-
-	//calculate t0
-	vz2 := big.NewInt(0)
-	z2 := new(big.Int).Mod(new(big.Int).Mul(cz, cz), EC.N)
-	PowerOfCZ := PowerVector(m, cz)
-	for j := 0; j < m; j++ {
-		vz2 = new(big.Int).Add(vz2,
-			new(big.Int).Mul(
-				PowerOfCZ[j],
-				new(big.Int).Mul(values[j], z2)))
-		vz2 = new(big.Int).Mod(vz2, EC.N)
+	rho, err := ec.fr.rand()
+	if err != nil {
+		return MRPResult, nil, err
 	}
 
-	t0 := new(big.Int).Mod(new(big.Int).Add(vz2, DeltaMRP(PowerOfCY, cz, m)), EC.N)
+	S := ec.ctCommit(nil, &rho, sL, sR)
+	MRPResult.S = S
 
-	t1 := new(big.Int).Mod(new(big.Int).Add(InnerProduct(l1, r0), InnerProduct(l0, r1)), EC.N)
-	t2 := InnerProduct(l1, r1)
+	t := ec.rangeProofTranscript(bitsPerValue, Comms)
+	t.AppendPoint("A", A)
+	t.AppendPoint("S", S)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+	polys := ec.rangeProofPolys(vs, aLConcat, aRConcat, sL, sR, cy, cz, bitsPerValue, m)
 
 	// given the t_i values, we can generate commitments to them
-	tau1, err := rand.Int(rand.Reader, EC.N)
-	check(err)
-	tau2, err := rand.Int(rand.Reader, EC.N)
-	check(err)
+	tau1, err := ec.fr.rand()
+	if err != nil {
+		return MRPResult, nil, err
+	}
+	tau2, err := ec.fr.rand()
+	if err != nil {
+		return MRPResult, nil, err
+	}
 
-	T1 := EC.G.Mult(t1).Add(EC.H.Mult(tau1)) //commitment to t1
-	T2 := EC.G.Mult(t2).Add(EC.H.Mult(tau2)) //commitment to t2
+	T1 := ec.ctCommit(&polys.t1, &tau1, nil, nil) //commitment to t1
+	T2 := ec.ctCommit(&polys.t2, &tau2, nil, nil) //commitment to t2
 
 	MRPResult.T1 = T1
 	MRPResult.T2 = T2
 
-	chal3s256 := sha256.Sum256([]byte(T1.X.String() + T1.Y.String() + T2.X.String() + T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
+	t.AppendPoint("T1", T1)
+	t.AppendPoint("T2", T2)
+	cx := t.ChallengeScalar("x")
+	sx := ec.fr.fromBig(cx)
+	var sx2 scalar
+	sx2.mul(&sx, &sx)
 
-	left := CalculateLMRP(aLConcat, sL, cz, cx)
-	right := CalculateRMRP(aRConcat, sR, PowerOfCY, zPowersTimesTwoVec, cz, cx)
-
-	thatPrime := new(big.Int).Mod( // t0 + t1*x + t2*x^2
-		new(big.Int).Add(t0, new(big.Int).Add(new(big.Int).Mul(t1, cx), new(big.Int).Mul(new(big.Int).Mul(cx, cx), t2))), EC.N)
-
-	that := InnerProduct(left, right) // NOTE: BP Java implementation calculates this from the t_i
-
-	// thatPrime and that should be equal
-	if thatPrime.Cmp(that) != 0 {
-		fmt.Println("Proving -- Uh oh! Two diff ways to compute same value not working")
-		fmt.Printf("\tthatPrime = %s\n", thatPrime.String())
-		fmt.Printf("\tthat = %s \n", that.String())
-	}
-
-	MRPResult.Th = that
-
-	vecRandomnessTotal := big.NewInt(0)
-	for j := 0; j < m; j++ {
-		zp := new(big.Int).Exp(cz, big.NewInt(2+int64(j)), EC.N)
-		tmp1 := new(big.Int).Mul(Blinds[j], zp)
-		vecRandomnessTotal = new(big.Int).Mod(new(big.Int).Add(vecRandomnessTotal, tmp1), EC.N)
+	left, right, that, err := polys.eval(&sx)
+	if err != nil {
+		return MRPResult, nil, err
 	}
-	//fmt.Println(vecRandomnessTotal)
-	taux1 := new(big.Int).Mod(new(big.Int).Mul(tau2, new(big.Int).Mul(cx, cx)), EC.N)
-	taux2 := new(big.Int).Mod(new(big.Int).Mul(tau1, cx), EC.N)
-	taux := new(big.Int).Mod(new(big.Int).Add(taux1, new(big.Int).Add(taux2, vecRandomnessTotal)), EC.N)
-
-	MRPResult.Tau = taux
 
-	mu := new(big.Int).Mod(new(big.Int).Add(alpha, new(big.Int).Mul(rho, cx)), EC.N)
-	MRPResult.Mu = mu
+	MRPResult.Th = that.big()
 
-	HPrime := make([]ECPoint, len(EC.BPH))
+	// tau_x = tau2 x^2 + tau1 x + sum_j z^(2+j) gamma_j
+	taux := scalarInnerProduct(polys.zPowers, gs)
+	var tmp scalar
+	tmp.mul(&tau2, &sx2)
+	taux.add(&taux, &tmp)
+	tmp.mul(&tau1, &sx)
+	taux.add(&taux, &tmp)
 
-	for i := range HPrime {
-		HPrime[i] = EC.BPH[i].Mult(new(big.Int).ModInverse(PowerOfCY[i], EC.N))
-	}
+	MRPResult.Tau = taux.big()
 
-	P := TwoVectorPCommitWithGens(EC.BPG, HPrime, left, right)
-	//fmt.Println(P)
+	// mu = alpha + rho x
+	var mu scalar
+	mu.mul(&rho, &sx)
+	mu.add(&mu, &alpha)
+	MRPResult.Mu = mu.big()
 
-	MRPResult.IPP = InnerProductProve(left, right, that, P, EC.U, EC.BPG, HPrime)
+	t.AppendScalar("tau_x", MRPResult.Tau)
+	t.AppendScalar("mu", MRPResult.Mu)
+	t.AppendScalar("t_hat", MRPResult.Th)
+	MRPResult.IPP = ec.rangeProofIPA(size, cy).prove(t, left, right)
 
-	return MRPResult, Comms
+	return MRPResult, Comms[:len(values)], nil
 }
 
 /*
-MultiRangeProof Verify
-Takes in a MultiRangeProof and verifies its correctness
-
+rangePolys - the vector polynomials l(X) = l0 + l1 X and r(X) = r0 + r1 X of
+an aggregate range proof and the coefficients of t(X) = <l(X), r(X)>, with
+zPowers holding z^(2+j) for every value j
 */
-func MRPVerify(mrp *MultiRangeProof, comms []ECPoint) bool {
-	m := len(comms)
-	bitsPerValue := EC.V / m
+type rangePolys struct {
+	l0, l1, r0, r1 []scalar
+	t0, t1, t2     scalar
+	zPowers        []scalar
+}
 
-	//changes:
-	// check 1 changes since it includes all commitments
-	// check 2 commitment generation is also different
+// rangeProofPolys builds the polynomials of a proof of m values of n bits
+// each from the values vs, their bits aL and aR = aL - 1, the blinding
+// vectors sL and sR and the challenges y and z
+func (ec *CryptoParams) rangeProofPolys(vs, aL, aR, sL, sR []scalar, cy, cz *big.Int, n, m int) *rangePolys {
+	size := n * m
+	sy, sz := ec.fr.fromBig(cy), ec.fr.fromBig(cz)
+
+	// z^(2+j) for every value and z^(2+j) 2^i for every bit
+	PowerOfCZ := scalarPowers(m+2, &sz)[2:]
+	two := ec.fr.fromBig(big.NewInt(2))
+	PowerOfTwos := scalarPowers(n, &two)
+	zPowersTimesTwoVec := make([]scalar, size)
+	for j := 0; j < m; j++ {
+		for i := 0; i < n; i++ {
+			zPowersTimesTwoVec[j*n+i].mul(&PowerOfTwos[i], &PowerOfCZ[j])
+		}
+	}
 
-	// verify the challenges
-	chal1s256 := sha256.Sum256([]byte(mrp.A.X.String() + mrp.A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
+	/*
+			Java code on how to calculate t1 and t2
 
-	chal2s256 := sha256.Sum256([]byte(mrp.S.X.String() + mrp.S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
+				FieldVector ys = FieldVector.from(VectorX.iterate(n, BigInteger.ONE, y::multiply),q); //powers of y
+			    FieldVector l0 = aL.add(z.negate());
+		        FieldVector l1 = sL;
+		        FieldVector twoTimesZSquared = twos.times(zSquared);
+		        FieldVector r0 = ys.hadamard(aR.add(z)).add(twoTimesZSquared);
+		        FieldVector r1 = sR.hadamard(ys);
+		        BigInteger k = ys.sum().multiply(z.subtract(zSquared)).subtract(zCubed.shiftLeft(n).subtract(zCubed));
+		        BigInteger t0 = k.add(zSquared.multiply(number));
+		        BigInteger t1 = l1.innerPoduct(r0).add(l0.innerPoduct(r1));
+		        BigInteger t2 = l1.innerPoduct(r1);
+		   		PolyCommitment<T> polyCommitment = PolyCommitment.from(base, t0, VectorX.of(t1, t2));
+	*/
+	PowerOfCY := scalarPowers(size, &sy)
+	p := &rangePolys{
+		l0:      make([]scalar, size),
+		l1:      sL,
+		r0:      make([]scalar, size),
+		r1:      make([]scalar, size),
+		zPowers: PowerOfCZ,
+	}
+	for i := 0; i < size; i++ {
+		p.l0[i].sub(&aL[i], &sz)
+		p.r0[i].add(&aR[i], &sz)
+		p.r0[i].mul(&p.r0[i], &PowerOfCY[i])
+		p.r0[i].add(&p.r0[i], &zPowersTimesTwoVec[i])
+		p.r1[i].mul(&sR[i], &PowerOfCY[i])
+	}
+
+	//calculate t0 = sum_j z^(2+j) v_j + delta(y, z)
+	p.t0 = scalarInnerProduct(PowerOfCZ, vs)
+	delta := ec.fr.fromBig(ec.DeltaMRP(ec.PowerVector(size, cy), cz, m))
+	p.t0.add(&p.t0, &delta)
+
+	p.t1 = scalarInnerProduct(p.l1, p.r0)
+	t1b := scalarInnerProduct(p.l0, p.r1)
+	p.t1.add(&p.t1, &t1b)
+	p.t2 = scalarInnerProduct(p.l1, p.r1)
+	return p
+}
 
-	chal3s256 := sha256.Sum256([]byte(mrp.T1.X.String() + mrp.T1.Y.String() + mrp.T2.X.String() + mrp.T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
+// eval returns l(x), r(x) and t(x), checking that t(x) = <l(x), r(x)>
+func (p *rangePolys) eval(sx *scalar) (left, right []scalar, that scalar, err error) {
+	// l(x) = l0 + l1 x, r(x) = r0 + r1 x
+	size := len(p.l0)
+	left = make([]scalar, size)
+	right = make([]scalar, size)
+	for i := 0; i < size; i++ {
+		left[i].mul(&p.l1[i], sx)
+		left[i].add(&left[i], &p.l0[i])
+		right[i].mul(&p.r1[i], sx)
+		right[i].add(&right[i], &p.r0[i])
+	}
 
+	// t0 + t1*x + t2*x^2 = t0 + x (t1 + x t2)
+	var thatPrime scalar
+	thatPrime.mul(&p.t2, sx)
+	thatPrime.add(&thatPrime, &p.t1)
+	thatPrime.mul(&thatPrime, sx)
+	thatPrime.add(&thatPrime, &p.t0)
 
-	// given challenges are correct, very range proof
-	PowersOfY := PowerVector(EC.V, cy)
+	that = scalarInnerProduct(left, right) // NOTE: BP Java implementation calculates this from the t_i
 
-	// t_hat * G + tau * H
-	lhs := EC.G.Mult(mrp.Th).Add(EC.H.Mult(mrp.Tau))
+	// thatPrime and that should be equal
+	if !thatPrime.equal(&that) {
+		return nil, nil, scalar{}, fmt.Errorf("%w: t(x) does not match <l(x), r(x)>", ErrUnsatisfiedConstraint)
+	}
+	return left, right, that, nil
+}
 
-	// z^2 * \bold{z}^m \bold{V} + delta(y,z) * G + x * T1 + x^2 * T2
-	CommPowers := EC.Zero()
-	PowersOfZ := PowerVector(m, cz)
-	z2 := new(big.Int).Mod(new(big.Int).Mul(cz, cz), EC.N)
+// rangeProofBits commits to each value under its blinding factor and splits
+// the values into the bit vectors aL and aR = aL - 1 of a range proof over n
+// bits each, padding with zero values and blinding factors up to m values
+func (ec *CryptoParams) rangeProofBits(values, gammas []*big.Int, n, m int) (comms []ECPoint, vs, gs, aL, aR []scalar, err error) {
+	comms = make([]ECPoint, m)
+	vs = make([]scalar, m)
+	gs = make([]scalar, m)
+	aL = make([]scalar, n*m)
+	aR = make([]scalar, n*m)
 
 	for j := 0; j < m; j++ {
-		CommPowers = CommPowers.Add(comms[j].Mult(new(big.Int).Mul(z2, PowersOfZ[j])))
-	}
+		var vl scalarLimbs
+		if j < len(values) {
+			var ok bool
+			vl, ok = secretBits(values[j], n)
+			if !ok {
+				return nil, nil, nil, nil, nil, fmt.Errorf("%w: value %d does not fit in %d bits", ErrValueOutOfRange, j, n)
+			}
+			vs[j] = ec.fr.fromBig(values[j])
+			gs[j] = ec.fr.fromBig(gammas[j])
 
-	rhs := EC.G.Mult(DeltaMRP(PowersOfY, cz, m)).Add(
-		mrp.T1.Mult(cx)).Add(
-		mrp.T2.Mult(new(big.Int).Mul(cx, cx))).Add(CommPowers)
+			comms[j] = ec.ctCommit(&vs[j], &gs[j], nil, nil)
+		} else {
+			comms[j] = ECPoint{big.NewInt(0), big.NewInt(0)}
+		}
 
-	if !lhs.Equal(rhs) {
-		fmt.Println("MRPVerify - Uh oh! Check line (63) of verification")
-		fmt.Println(rhs)
-		fmt.Println(lhs)
-		return false
+		// break up v into its bitwise representation, aR = aL - 1
+		one := ec.fr.one()
+		for i := 0; i < n; i++ {
+			bit := (vl[i/64] >> uint(i%64)) & 1
+			aL[n*j+i] = selectScalar(&one, &scalar{}, bit)
+			aR[n*j+i].sub(&aL[n*j+i], &one)
+		}
 	}
+	return comms, vs, gs, aL, aR, nil
+}
+
+/*
+MultiRangeProof Verify
+Takes in a MultiRangeProof and verifies its correctness
 
-	tmp1 := EC.Zero()
-	zneg := new(big.Int).Mod(new(big.Int).Neg(cz), EC.N)
-	for i := range EC.BPG {
-		tmp1 = tmp1.Add(EC.BPG[i].Mult(zneg))
+Returns false if the proof does not verify, and an error if the proof or the
+commitments are malformed.
+*/
+func (ec *CryptoParams) MRPVerify(mrp *MultiRangeProof, comms []ECPoint) (bool, error) {
+	if mrp == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	bitsPerValue := mrp.Bits
+	if bitsPerValue == 0 {
+		// proofs made before the bit length was recorded use the default
+		var err error
+		if bitsPerValue, err = ec.bitsPerValue(len(comms)); err != nil {
+			return false, err
+		}
 	}
+	return ec.mrpVerify(mrp, comms, bitsPerValue)
+}
 
-	PowerOfTwos := PowerVector(bitsPerValue, big.NewInt(2))
-	tmp2 := EC.Zero()
-	// generate h'
-	HPrime := make([]ECPoint, len(EC.BPH))
+/*
+MRPVerifyBits - MultiRangeProof Verify with an explicit bit length
 
-	for i := range HPrime {
-		mi := new(big.Int).ModInverse(PowersOfY[i], EC.N)
-		HPrime[i] = EC.BPH[i].Mult(mi)
+Same as MRPVerify, except the proof must show that every value fits in n bits,
+where n is one of BitLengths.
+*/
+func (ec *CryptoParams) MRPVerifyBits(mrp *MultiRangeProof, comms []ECPoint, n int) (bool, error) {
+	if mrp == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
 	}
-
-	for j := 0; j < m; j++ {
-		for i := 0; i < bitsPerValue; i++ {
-			val1 := new(big.Int).Mul(cz, PowersOfY[j*bitsPerValue+i])
-			zp := new(big.Int).Exp(cz, big.NewInt(2+int64(j)), EC.N)
-			val2 := new(big.Int).Mod(new(big.Int).Mul(zp, PowerOfTwos[i]), EC.N)
-			tmp2 = tmp2.Add(HPrime[j*bitsPerValue+i].Mult(new(big.Int).Add(val1, val2)))
-		}
+	if err := checkBitLength(n); err != nil {
+		return false, err
+	}
+	if mrp.Bits != n {
+		return false, fmt.Errorf("%w: proof is over %d bits, expected %d", ErrMalformedProof, mrp.Bits, n)
 	}
+	return ec.mrpVerify(mrp, comms, n)
+}
 
-	// without subtracting this value should equal muCH + l[i]G[i] + r[i]H'[i]
-	// we want to make sure that the innerproduct checks out, so we subtract it
-	P := mrp.A.Add(mrp.S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.H.Mult(mrp.Mu).Neg())
-	//fmt.Println(P)
+/*
+mrpVerify checks mrp against comms, with every value in bitsPerValue bits
+
+This is the verifier of section 6.2 of the Bulletproofs paper. The check
+that t_hat is committed to correctly and the inner product argument on P are
+each weighted by a random scalar and added up, and P is never computed on its
+own. Everything is then checked with one multi-exponentiation over G, H, U,
+BPG, BPH, V, T1, T2, A, S, L and R. The terms are the ones BatchVerifier
+uses, see batchTerms.
+*/
+func (ec *CryptoParams) mrpVerify(mrp *MultiRangeProof, comms []ECPoint, bitsPerValue int) (bool, error) {
+	if err := checkPoints(ec.group(), comms...); err != nil {
+		return false, err
+	}
+	comms = padCommitments(comms)
+	m := len(comms)
+	size, err := ec.proofSize(bitsPerValue, m)
+	if err != nil {
+		return false, err
+	}
+	if err := mrp.check(ec.group()); err != nil {
+		return false, err
+	}
+	if err := mrp.IPP.check(ec.group(), size); err != nil {
+		return false, err
+	}
 
-	if !InnerProductVerifyFast(mrp.Th, P, EC.U, EC.BPG, HPrime, mrp.IPP) {
-		fmt.Println("MRPVerify - Uh oh! Check line (65) of verification!")
-		return false
+	terms, err := ec.batchTerms(&batchEntry{mrp, comms, bitsPerValue})
+	if err != nil {
+		return false, err
+	}
+	if !ec.checkTerms([]batchTerms{terms}) {
+		return false, nil
 	}
 
-	return true
+	return true, nil
 }
 
-// NewECPrimeGroupKey returns the curve (field),
-// Generator 1 x&y, Generator 2 x&y, order of the generators
+// NewECPrimeGroupKey returns the secp256k1 params for vectors of length n,
+// with every generator hashed to the curve from its seed as described by
+// GeneratorSeeds. It panics if n is less than 1; NewGroupParams(Secp256k1(),
+// n) returns the error instead.
+//
+// These are not the generators it returned before RFC 9380 was used, so
+// proofs made by earlier versions do not verify with them, see
+// LegacyMRPVerify.
 func NewECPrimeGroupKey(n int) CryptoParams {
+	ec, err := NewGroupParams(Secp256k1(), n)
+	if err != nil {
+		panic("bulletproofs: NewECPrimeGroupKey: " + err.Error())
+	}
+	return ec
+}
+
+// NewLegacyECPrimeGroupKey returns the secp256k1 params NewECPrimeGroupKey
+// made before generators were hashed to the curve. Proofs serialised by those
+// versions also used older challenges, so they verify with these params only
+// through LegacyRPVerify and LegacyMRPVerify; RPVerify and MRPVerify only
+// accept proofs made with them by this version. Each generator is the first 0x02 || SHA-256(Gx + j) that parses
+// as a point, for j counting up from 0, handed out to BPG and BPH in turn and
+// then to U, G and H.
+func NewLegacyECPrimeGroupKey(n int) CryptoParams {
 	curValue := secp256k1.S256().Gx
 	s256 := sha256.New()
 	gen1Vals := make([]ECPoint, n)
@@ -1540,8 +1378,8 @@ func NewECPrimeGroupKey(n int) CryptoParams {
 			potentialXValue[i+1] = elem
 		}
 
-		gen2, err := secp256k1.ParsePubKey(potentialXValue)
-		if err == nil {
+		var gen2 ECPoint
+		if gen2.Rebuild(potentialXValue) == nil {
 			if confirmed == 2*n { // once we've generated all g and h values then assign this to u
 				u = ECPoint{gen2.X, gen2.Y}
 				//fmt.Println("Got that U value")
@@ -1564,19 +1402,45 @@ func NewECPrimeGroupKey(n int) CryptoParams {
 		j++
 	}
 
-	return CryptoParams{
-		secp256k1.S256(),
-		secp256k1.S256(),
-		gen1Vals,
-		gen2Vals,
-		secp256k1.S256().N,
-		u,
-		n,
-		cg,
-		ch}
+	ec := CryptoParams{
+		BPG: gen1Vals,
+		BPH: gen2Vals,
+		Group: Secp256k1(),
+		N:   secp256k1.S256().N,
+		U:   u,
+		V:   n,
+		G:   cg,
+		H:   ch,
+		tables: &fixedBaseTables{},
+		fr:  secp256k1Scalars}
+	ec.id = ec.ID()
+	return ec
+}
+
+// ID - a digest of the vector length and every generator, absorbed into each
+// transcript so proofs are bound to the parameters they were made with
+func (ec *CryptoParams) ID() []byte {
+	if ec.id != nil {
+		return ec.id
+	}
+	return pointsDigest([]ECPoint{{big.NewInt(int64(ec.V)), big.NewInt(0)}}, []ECPoint{ec.G, ec.H, ec.U}, ec.BPG, ec.BPH)
+}
+
+// rangeProofTranscript starts the transcript shared by the aggregate range
+// prover and verifier
+func (ec *CryptoParams) rangeProofTranscript(bitsPerValue int, comms []ECPoint) *Transcript {
+	return ec.newRangeTranscript("bulletproofs range proof", bitsPerValue, comms)
 }
 
-func init() {
-	EC = NewECPrimeGroupKey(VecLength)
-	//fmt.Println(EC)
+// newRangeTranscript starts a transcript under label for a proof that the
+// values in comms fit in bitsPerValue bits
+func (ec *CryptoParams) newRangeTranscript(label string, bitsPerValue int, comms []ECPoint) *Transcript {
+	t := ec.NewTranscript(label)
+	t.AppendMessage("generators", ec.ID())
+	t.AppendUint64("n", uint64(bitsPerValue))
+	t.AppendUint64("m", uint64(len(comms)))
+	for _, V := range comms {
+		t.AppendPoint("V", V)
+	}
+	return t
 }
@@ -2,6 +2,11 @@ package bp_go
 
 import (
 	"crypto/rand"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -11,6 +16,10 @@ import (
 	"bytes"
 	"encoding/gob"
 	"log"
+	"math"
+	"math/bits"
+	"sort"
+	"time"
 )
 
 func TestInnerProductProveLen1(t *testing.T) {
@@ -23,13 +32,22 @@ func TestInnerProductProveLen1(t *testing.T) {
 
 	b[0] = big.NewInt(-4)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -48,13 +66,22 @@ func TestInnerProductProveLen2(t *testing.T) {
 	b[0] = big.NewInt(2)
 	b[1] = big.NewInt(3)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -77,13 +104,22 @@ func TestInnerProductProveLen4(t *testing.T) {
 	b[2] = big.NewInt(1)
 	b[3] = big.NewInt(1)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -114,13 +150,22 @@ func TestInnerProductProveLen8(t *testing.T) {
 	b[6] = big.NewInt(2)
 	b[7] = big.NewInt(2)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -130,16 +175,25 @@ func TestInnerProductProveLen8(t *testing.T) {
 func TestInnerProductProveLen64Rand(t *testing.T) {
 	fmt.Println("TestInnerProductProveLen64Rand")
 	EC = NewECPrimeGroupKey(64)
-	a := RandVector(64)
-	b := RandVector(64)
+	a, _ := RandVector(64)
+	b, _ := RandVector(64)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerify(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -158,13 +212,22 @@ func TestInnerProductVerifyFastLen1(t *testing.T) {
 
 	b[0] = big.NewInt(2)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -183,13 +246,22 @@ func TestInnerProductVerifyFastLen2(t *testing.T) {
 	b[0] = big.NewInt(2)
 	b[1] = big.NewInt(3)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -212,13 +284,22 @@ func TestInnerProductVerifyFastLen4(t *testing.T) {
 	b[2] = big.NewInt(1)
 	b[3] = big.NewInt(1)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -249,13 +330,22 @@ func TestInnerProductVerifyFastLen8(t *testing.T) {
 	b[6] = big.NewInt(2)
 	b[7] = big.NewInt(2)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -265,16 +355,25 @@ func TestInnerProductVerifyFastLen8(t *testing.T) {
 func TestInnerProductVerifyFastLen64Rand(t *testing.T) {
 	fmt.Println("TestInnerProductProveLen64Rand")
 	EC = NewECPrimeGroupKey(64)
-	a := RandVector(64)
-	b := RandVector(64)
+	a, _ := RandVector(64)
+	b, _ := RandVector(64)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	P, err := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	ipp := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	ipp, err := InnerProductProve(a, b, c, P, EC.U, EC.BPG, EC.BPH)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp) {
+	if ok, err := InnerProductVerifyFast(c, P, EC.U, EC.BPG, EC.BPH, ipp); ok && err == nil {
 		fmt.Println("Inner Product Proof correct")
 	} else {
 		t.Error("Inner Product Proof incorrect")
@@ -288,7 +387,10 @@ func TestValueBreakdown(t *testing.T) {
 	yes := reverse(StrToBigIntArray(PadLeft(fmt.Sprintf("%b", v), "0", 64)))
 	vec2 := PowerVector(64, big.NewInt(2))
 
-	calc := InnerProduct(yes, vec2)
+	calc, err := InnerProduct(yes, vec2)
+	if err != nil {
+		t.Fatal(err)
+	}
 	spew.Dump(yes)
 
 	if v.Cmp(calc) != 0 {
@@ -306,12 +408,17 @@ func TestValueBreakdown(t *testing.T) {
 
 func TestValueBreakdownRand(t *testing.T) {
 	v, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(2), big.NewInt(64), EC.N))
-	check(err)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	yes := reverse(StrToBigIntArray(PadLeft(fmt.Sprintf("%b", v), "0", 64)))
 	vec2 := PowerVector(64, big.NewInt(2))
 
-	calc := InnerProduct(yes, vec2)
+	calc, err := InnerProduct(yes, vec2)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if v.Cmp(calc) != 0 {
 		t.Error("Binary Value Breakdown - Failure :(")
@@ -332,7 +439,10 @@ func TestVectorHadamard(t *testing.T) {
 	a[3] = big.NewInt(1)
 	a[4] = big.NewInt(1)
 
-	c := VectorHadamard(a, a)
+	c, err := VectorHadamard(a, a)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	success := true
 
@@ -381,10 +491,13 @@ func TestMRPVerifyTransWithReceiverConf(t *testing.T) {
 	valArr[2] = big.NewInt(4)
 	valArr[3] = big.NewInt(0)
 
-    commitments, mrp := MRPProve(valArr)
+	commitments, mrp, err := MRPProve(valArr)
+	if err != nil {
+		t.Fatal(err)
+	}
 	strMP, _ := mrp.Serialize()
 	//strconv.Atoi(strMP)
-	if MRPVerify(&mrp, commitments) {
+	if ok, err := MRPVerify(&mrp, commitments); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 		t.Error("*****Range Proof FAILURE")
@@ -420,15 +533,23 @@ func TestRPVerifyTransWithReceiverConf(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	rp := RPProveTrans(comm1.Blind, val)
-	rpBytes := rp.Bytes()
+	rp, err := RPProveTrans(comm1.Blind, val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpBytes, err := rp.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
 	fmt.Printf("Byte length is %v\n", len(rpBytes))
 	serRP, _ := rp.Serialize()
 	fmt.Printf("The length of the serialized range proof in bytes is %v\n", len([]byte(serRP)))
 	rpb := &RangeProof{}
 
-	rpb.Rebuild(serRP)
-	if RPVerifyTrans(&comm1.Comm, rpb) {
+	if err := rpb.Rebuild(serRP); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := RPVerifyTrans(&comm1.Comm, rpb); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 		t.Error("*****Range Proof FAILURE")
@@ -465,7 +586,10 @@ func TestRangeProof_Bytes(t *testing.T) {
 		if err != nil {
 			t.Error(err)
 		}
-		rp := RPProveTrans(comm1.Blind, val)
+		rp, err := RPProveTrans(comm1.Blind, val)
+		if err != nil {
+			t.Fatal(err)
+		}
 		rpBytes, _ := rp.Serialize()
 		/*if len(rp.Bytes()) != 1008 {
 			spew.Dump(rp)
@@ -498,7 +622,10 @@ func TestRangeProof_BytesFunc(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	rp := RPProveTrans(comm1.Blind, val)
+	rp, err := RPProveTrans(comm1.Blind, val)
+	if err != nil {
+		t.Fatal(err)
+	}
 	var network bytes.Buffer
 	enc := gob.NewEncoder(&network)
 	dec := gob.NewDecoder(&network)
@@ -516,7 +643,7 @@ func TestRangeProof_BytesFunc(t *testing.T) {
 	//rebuiltRp := &RangeProof{}
 	//rebuiltRp.RebuildBytes(rpBytes)
 
-	if RPVerifyTrans(&comm1.Comm, &rebRp) {
+	if ok, err := RPVerifyTrans(&comm1.Comm, &rebRp); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 	t.Error("*****Range Proof FAILURE")
@@ -539,7 +666,11 @@ func TestRangeProofMax(t *testing.T) {
 func TestRPVerify2(t *testing.T) {
 	EC = NewECPrimeGroupKey(64)
 	// Testing largest number in range
-	if RPVerify(RPProve(new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(63), EC.N), big.NewInt(1)))) {
+	rp, err := RPProve(new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(63), EC.N), big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := RPVerify(rp); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 		t.Error("*****Range Proof FAILURE")
@@ -550,7 +681,11 @@ func TestRPVerify2(t *testing.T) {
 func TestRPVerify3(t *testing.T) {
 	EC = NewECPrimeGroupKey(64)
 	// Testing the value 3
-	if RPVerify(RPProve(big.NewInt(3))) {
+	rp, err := RPProve(big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := RPVerify(rp); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 		t.Error("*****Range Proof FAILURE")
@@ -561,7 +696,11 @@ func TestRPVerify3(t *testing.T) {
 func TestRPVerify4(t *testing.T) {
 	EC = NewECPrimeGroupKey(32)
 	// Testing smallest number in range
-	if RPVerify(RPProve(big.NewInt(0))) {
+	rp, err := RPProve(big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := RPVerify(rp); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 		t.Error("*****Range Proof FAILURE")
@@ -572,10 +711,16 @@ func TestRPVerifyRand(t *testing.T) {
 	EC = NewECPrimeGroupKey(64)
 
 	ran, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(2), big.NewInt(64), EC.N))
-	check(err)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Testing the value 3
-	if RPVerify(RPProve(ran)) {
+	rp, err := RPProve(ran)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := RPVerify(rp); ok && err == nil {
 		fmt.Println("Range Proof Verification works")
 	} else {
 		t.Error("*****Range Proof FAILURE")
@@ -588,12 +733,15 @@ func TestMultiRPVerify1(t *testing.T) {
 	values := []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)}
 	EC = NewECPrimeGroupKey(64 * len(values))
 	// Testing smallest number in range
-	comms, proof := MRPProve(values)
-	proofString := fmt.Sprintf("%s", proof)
+	comms, proof, err := MRPProve(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofString := fmt.Sprintf("%v", proof)
 
 	fmt.Println(len(proofString)) // length is good measure of bytes, correct?
 
-	if MRPVerify(&proof, comms) {
+	if ok, err := MRPVerify(&proof, comms); ok && err == nil {
 		fmt.Println("Multi Range Proof Verification works")
 	} else {
 		t.Error("***** Multi Range Proof FAILURE")
@@ -604,9 +752,12 @@ func TestMultiRPVerify2(t *testing.T) {
 	values := []*big.Int{big.NewInt(0)}
 	EC = NewECPrimeGroupKey(64 * len(values))
 	// Testing smallest number in range
-	comms, proof := MRPProve(values)
+	comms, proof, err := MRPProve(values)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if MRPVerify(&proof, comms) {
+	if ok, err := MRPVerify(&proof, comms); ok && err == nil {
 		fmt.Println("Multi Range Proof Verification works")
 	} else {
 		t.Error("***** Multi Range Proof FAILURE")
@@ -617,9 +768,12 @@ func TestMultiRPVerify3(t *testing.T) {
 	values := []*big.Int{big.NewInt(0), big.NewInt(1)}
 	EC = NewECPrimeGroupKey(64 * len(values))
 	// Testing smallest number in range
-    comms, proof := MRPProve(values)
+	comms, proof, err := MRPProve(values)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-    if MRPVerify(&proof, comms) {
+    if ok, err := MRPVerify(&proof, comms); ok && err == nil {
 		fmt.Println("Multi Range Proof Verification works")
 	} else {
 		t.Error("***** Multi Range Proof FAILURE")
@@ -637,13 +791,16 @@ func TestMultiRPVerify4(t *testing.T) {
 
 		EC = NewECPrimeGroupKey(64 * len(values))
 		// Testing smallest number in range
-		comms, proof := MRPProve(values)
-		proofString := fmt.Sprintf("%s", proof)
+		comms, proof, err := MRPProve(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+		proofString := fmt.Sprintf("%v", proof)
 
 		fmt.Println(len(proofString)) // length is good measure of bytes, correct?
 
 
-        if MRPVerify(&proof, comms) {
+        if ok, err := MRPVerify(&proof, comms); ok && err == nil {
 			fmt.Println("Multi Range Proof Verification works")
 		} else {
 			t.Error("***** Multi Range Proof FAILURE")
@@ -666,7 +823,10 @@ func TestInnerProduct(t *testing.T) {
 	b[2] = big.NewInt(2)
 	b[3] = big.NewInt(2)
 
-	c := InnerProduct(a, b)
+	c, err := InnerProduct(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if c.Cmp(big.NewInt(16)) == 0 {
 		fmt.Println("Success - Innerproduct works with 2")
@@ -688,11 +848,17 @@ func BenchmarkMRPVerifySize(b *testing.B) {
 
 			EC = NewECPrimeGroupKey(64 * len(values))
 			// Testing smallest number in range
-			comms, proof := MRPProve(values)
-			proofBytes := proof.Bytes()
+			comms, proof, err := MRPProve(values)
+			if err != nil {
+				b.Fatal(err)
+			}
+			proofBytes, err := proof.Bytes()
+			if err != nil {
+				b.Fatal(err)
+			}
 			fmt.Printf("Size for %d values: %d bytes\n", j, len(proofBytes)) // length is good measure of bytes, correct?
 
-            if MRPVerify(&proof, comms) {
+            if ok, err := MRPVerify(&proof, comms); ok && err == nil {
 				fmt.Println("Multi Range Proof Verification works")
 			} else {
 				fmt.Println("***** Multi Range Proof FAILURE")
@@ -712,8 +878,10 @@ func BenchmarkMRPProve16(b *testing.B) {
 	}
 	EC = NewECPrimeGroupKey(64 * len(values))
 	var r MultiRangeProof
+	b.ReportAllocs()
+	b.ResetTimer()
 	for i := 0; i < b.N; i++{
-		_, r = MRPProve(values)
+		_, r, _ = MRPProve(values)
 	}
 
 	result = r
@@ -726,11 +894,15 @@ func BenchmarkMRPVerify16(b *testing.B) {
 		values[k] = big.NewInt(0)
 	}
 	EC = NewECPrimeGroupKey(64 * len(values))
-	comms, proof := MRPProve(values)
+	comms, proof, err := MRPProve(values)
+	if err != nil {
+		b.Fatal(err)
+	}
 
 	var r bool
+	b.ResetTimer()
 	for i := 0; i < b.N; i++{
-		r = MRPVerify(&proof, comms)
+		r, _ = MRPVerify(&proof, comms)
 	}
 	boores = r
 }
@@ -744,7 +916,7 @@ func BenchmarkMRPProve32(b *testing.B) {
 	EC = NewECPrimeGroupKey(64 * len(values))
 	var r MultiRangeProof
 	for i := 0; i < b.N; i++{
-		_, r = MRPProve(values)
+		_, r, _ = MRPProve(values)
 	}
 	result = r
 }
@@ -756,12 +928,2029 @@ func BenchmarkMRPVerify32(b *testing.B) {
 		values[k] = big.NewInt(0)
 	}
 	EC = NewECPrimeGroupKey(64 * len(values))
-    comms, proof := MRPProve(values)
+	comms, proof, err := MRPProve(values)
+	if err != nil {
+		b.Fatal(err)
+	}
 
 	var r bool
+	b.ResetTimer()
 	for i := 0; i < b.N; i++{
-		r = MRPVerify(&proof, comms)
+		r, _ = MRPVerify(&proof, comms)
 	}
 	boores = r
 }
 
+func TestSeparateParams(t *testing.T) {
+	small := NewECPrimeGroupKey(16)
+	large := NewECPrimeGroupKey(64)
+
+	done := make(chan bool, 2)
+	for _, ec := range []*CryptoParams{&small, &large} {
+		go func(ec *CryptoParams) {
+			rp, err := ec.RPProve(big.NewInt(1000))
+			if err != nil {
+				done <- false
+				return
+			}
+			ok, err := ec.RPVerify(rp)
+			done <- ok && err == nil
+		}(ec)
+	}
+
+	for i := 0; i < 2; i++ {
+		if !<-done {
+			t.Error("*****Range Proof FAILURE with separate params")
+		}
+	}
+}
+
+func TestRPProveOutOfRange(t *testing.T) {
+	ec := NewECPrimeGroupKey(8)
+
+	for _, v := range []*big.Int{big.NewInt(-1), big.NewInt(256)} {
+		if _, err := ec.RPProve(v); !errors.Is(err, ErrValueOutOfRange) {
+			t.Errorf("proving %v: expected ErrValueOutOfRange, got %v", v, err)
+		}
+	}
+
+	if _, _, err := ec.MRPProve([]*big.Int{big.NewInt(1), big.NewInt(16)}); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("expected ErrValueOutOfRange, got %v", err)
+	}
+}
+
+func TestVectorLengthMismatch(t *testing.T) {
+	a := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	b := []*big.Int{big.NewInt(1)}
+
+	if _, err := VectorAdd(a, b); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("VectorAdd: expected ErrLengthMismatch, got %v", err)
+	}
+	if _, err := InnerProduct(a, b); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("InnerProduct: expected ErrLengthMismatch, got %v", err)
+	}
+	if _, err := VectorHadamard(a, b); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("VectorHadamard: expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestRebuildMalformed(t *testing.T) {
+	EC = NewECPrimeGroupKey(64)
+	rp, err := RPProve(big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serRP, err := rp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range []string{"", "not base58 0OIl", serRP[:len(serRP)/2]} {
+		if err := new(RangeProof).Rebuild(bad); !errors.Is(err, ErrMalformedProof) && !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("expected a decode error for %q, got %v", bad, err)
+		}
+		if err := new(MultiRangeProof).Rebuild(bad); !errors.Is(err, ErrMalformedProof) && !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("expected a decode error for %q, got %v", bad, err)
+		}
+	}
+
+	// a proof with its inner product rounds removed must be rejected, not panic
+	rp.IPP.L = rp.IPP.L[1:]
+	if _, err := RPVerify(rp); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("expected ErrMalformedProof, got %v", err)
+	}
+}
+
+func TestSerializeMissingFields(t *testing.T) {
+	rp, err := RPProve(big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	partial := rp
+	partial.IPP.B = nil
+
+	serializers := map[string]func() (string, error){
+		"RangeProof":          new(RangeProof).Serialize,
+		"partial RangeProof":  partial.Serialize,
+		"MultiRangeProof":     new(MultiRangeProof).Serialize,
+		"InnerProdArg":        new(InnerProdArg).Serialize,
+		"RangeProofPlus":      new(RangeProofPlus).Serialize,
+		"MultiRangeProofPlus": new(MultiRangeProofPlus).Serialize,
+		"R1CSProof":           new(R1CSProof).Serialize,
+		"BitCommitment":       new(BitCommitment).Serialize,
+		"BitChallenge":        new(BitChallenge).Serialize,
+		"PolyCommitment":      new(PolyCommitment).Serialize,
+		"PolyChallenge":       new(PolyChallenge).Serialize,
+		"ProofShare":          new(ProofShare).Serialize,
+	}
+	for name, serialize := range serializers {
+		if _, err := serialize(); !errors.Is(err, ErrMalformedProof) {
+			t.Errorf("%s: expected ErrMalformedProof, got %v", name, err)
+		}
+	}
+
+	encoders := map[string]func() ([]byte, error){
+		"RangeProof":         new(RangeProof).Bytes,
+		"partial RangeProof": partial.Bytes,
+		"MultiRangeProof":    new(MultiRangeProof).Bytes,
+	}
+	for name, encode := range encoders {
+		if _, err := encode(); !errors.Is(err, ErrMalformedProof) {
+			t.Errorf("%s: expected ErrMalformedProof from Bytes, got %v", name, err)
+		}
+	}
+}
+
+func TestMRPSerializeRebuild(t *testing.T) {
+	EC = NewECPrimeGroupKey(64)
+	comms, mrp, err := MRPProve([]*big.Int{big.NewInt(3), big.NewInt(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serMP, err := mrp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt := &MultiRangeProof{}
+	if err := rebuilt.Rebuild(serMP); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := MRPVerify(rebuilt, comms); !ok || err != nil {
+		t.Errorf("*****Rebuilt Multi Range Proof FAILURE: %v", err)
+	}
+}
+
+
+func TestTranscriptChallenges(t *testing.T) {
+	for _, h := range []TranscriptHash{SHA256, SHA3, Keccak, BLAKE2b} {
+		t1 := NewTranscript("test", h)
+		t2 := NewTranscript("test", h)
+		t1.AppendUint64("n", 64)
+		t2.AppendUint64("n", 64)
+
+		c1 := t1.ChallengeScalar("x")
+		c2 := t2.ChallengeScalar("x")
+		if c1.Cmp(c2) != 0 || c1.Cmp(EC.N) >= 0 {
+			t.Errorf("transcript %d is not deterministic", h)
+		}
+		if t1.ChallengeScalar("x").Cmp(c1) == 0 {
+			t.Errorf("transcript %d repeated a challenge", h)
+		}
+
+		// a different label must give a different challenge
+		t3 := NewTranscript("test", h)
+		t3.AppendUint64("m", 64)
+		if t3.ChallengeScalar("x").Cmp(c1) == 0 {
+			t.Errorf("transcript %d ignored the label", h)
+		}
+	}
+}
+
+func TestMRPTranscriptHash(t *testing.T) {
+	ec := NewECPrimeGroupKey(16)
+	values := []*big.Int{big.NewInt(3), big.NewInt(200)}
+
+	for _, h := range []TranscriptHash{SHA256, SHA3, Keccak, BLAKE2b} {
+		ec.Hash = h
+		comms, proof, err := ec.MRPProve(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := ec.MRPVerify(&proof, comms); !ok || err != nil {
+			t.Errorf("*****Multi Range Proof FAILURE with transcript hash %d", h)
+		}
+
+		other := ec
+		other.Hash = (h + 1) % 4
+		if ok, _ := other.MRPVerify(&proof, comms); ok {
+			t.Errorf("proof verified with the wrong transcript hash %d", other.Hash)
+		}
+
+		// the commitments are absorbed, so swapping them must fail
+		if ok, _ := ec.MRPVerify(&proof, []ECPoint{comms[1], comms[0]}); ok {
+			t.Error("proof verified with reordered commitments")
+		}
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	ec := NewECPrimeGroupKey(16)
+	bv := ec.NewBatchVerifier()
+
+	for i := 0; i < 3; i++ {
+		rp, err := ec.RPProve(big.NewInt(int64(1000 * i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bv.AddRangeProof(rp.Comm.Comm, &rp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, values := range [][]*big.Int{
+		{big.NewInt(7), big.NewInt(255)},
+		{big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(15)},
+	} {
+		comms, mrp, err := ec.MRPProve(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bv.AddMultiRangeProof(comms, &mrp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ok, failed, err := bv.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || len(failed) != 0 {
+		t.Errorf("*****Batch Verify FAILURE, failed proofs %v", failed)
+	}
+
+	// corrupt the second and last proofs, bisection should find both
+	bv.entries[1].mrp.Th = new(big.Int).Add(bv.entries[1].mrp.Th, big.NewInt(1))
+	bv.entries[4].comms[0] = bv.entries[4].comms[1]
+	ok, failed, err = bv.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok || fmt.Sprint(failed) != "[1 4]" {
+		t.Errorf("batch should have failed proofs [1 4], got %v", failed)
+	}
+}
+
+func TestBatchVerifyMalformed(t *testing.T) {
+	ec := NewECPrimeGroupKey(16)
+	bv := ec.NewBatchVerifier()
+
+	rp, err := ec.RPProve(big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp.IPP.L = rp.IPP.L[1:]
+	if err := bv.AddRangeProof(rp.Comm.Comm, &rp); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("expected ErrMalformedProof, got %v", err)
+	}
+	if bv.Len() != 0 {
+		t.Error("malformed proof was added to the batch")
+	}
+}
+
+func TestMultiScalarMul(t *testing.T) {
+	// cover both Straus and Pippenger, zero scalars and repeated points
+	for _, n := range []int{1, 2, 5, strausThreshold - 1, strausThreshold, 300} {
+		points := make([]ECPoint, n)
+		scalars := make([]*big.Int, n)
+		expected := EC.Zero()
+		for i := range points {
+			points[i] = EC.BPG[i%len(EC.BPG)]
+			scalars[i], _ = rand.Int(rand.Reader, EC.N)
+			if i%7 == 3 {
+				scalars[i] = big.NewInt(0)
+			}
+			expected = expected.Add(points[i].Mult(scalars[i]))
+		}
+
+		res, err := MultiScalarMul(points, scalars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.X.Cmp(expected.X) != 0 || res.Y.Cmp(expected.Y) != 0 {
+			t.Errorf("MultiScalarMul of %d points is wrong", n)
+		}
+	}
+
+	// P + (-1) P is the point at infinity
+	res, _ := MultiScalarMul([]ECPoint{EC.G, EC.G}, []*big.Int{big.NewInt(1), big.NewInt(-1)})
+	if res.X.Sign() != 0 || res.Y.Sign() != 0 {
+		t.Error("P - P should be the point at infinity")
+	}
+
+	if _, err := MultiScalarMul([]ECPoint{EC.G}, nil); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func BenchmarkMultiScalarMul2048(b *testing.B) {
+	ec := NewECPrimeGroupKey(1024)
+	points := append(append([]ECPoint{}, ec.BPG...), ec.BPH...)
+	scalars, _ := ec.RandVector(len(points))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MultiScalarMul(points, scalars)
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	pMinus1 := new(big.Int).Sub(curve.P, big.NewInt(1))
+	for i := 0; i < 200; i++ {
+		x, _ := rand.Int(rand.Reader, curve.P)
+		y, _ := rand.Int(rand.Reader, curve.P)
+		if i == 0 {
+			x, y = pMinus1, pMinus1
+		}
+		var fx, fy, r fieldElement
+		fx.setBig(x)
+		fy.setBig(y)
+
+		if r.mul(&fx, &fy).big().Cmp(new(big.Int).Mod(new(big.Int).Mul(x, y), curve.P)) != 0 {
+			t.Fatalf("field mul is wrong for %x * %x", x, y)
+		}
+		if r.add(&fx, &fy).big().Cmp(new(big.Int).Mod(new(big.Int).Add(x, y), curve.P)) != 0 {
+			t.Fatalf("field add is wrong for %x + %x", x, y)
+		}
+		if r.sub(&fx, &fy).big().Cmp(new(big.Int).Mod(new(big.Int).Sub(x, y), curve.P)) != 0 {
+			t.Fatalf("field sub is wrong for %x - %x", x, y)
+		}
+		if r.inverse(&fx).big().Cmp(new(big.Int).ModInverse(x, curve.P)) != 0 {
+			t.Fatalf("field inverse is wrong for %x", x)
+		}
+	}
+}
+
+func TestFixedBaseMult(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	g, _ := ec.randScalar()
+	h, _ := ec.randScalar()
+	u, _ := ec.randScalar()
+
+	// below and above the bucket threshold, from many goroutines at once
+	sizes := []int{0, 3, fixedBucketThreshold, 64}
+	done := make(chan error, len(sizes))
+	for _, n := range sizes {
+		go func(n int) {
+			a, _ := ec.RandVector(n)
+			b, _ := ec.RandVector(n / 2)
+
+			res, err := ec.FixedBaseMult(g, h, u, a, b)
+			if err != nil {
+				done <- err
+				return
+			}
+
+			points := append(append([]ECPoint{ec.G, ec.H, ec.U}, ec.BPG[:n]...), ec.BPH[:n/2]...)
+			scalars := append(append([]*big.Int{g, h, u}, a...), b...)
+			expected, _ := MultiScalarMul(points, scalars)
+			if !res.Equal(expected) || res.Y.Cmp(expected.Y) != 0 {
+				done <- fmt.Errorf("FixedBaseMult with %d scalars is wrong", n)
+				return
+			}
+			done <- nil
+		}(n)
+	}
+	for range sizes {
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := ec.FixedBaseMult(nil, nil, nil, make([]*big.Int, 65), nil); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestECPointEqual(t *testing.T) {
+	p := EC.BPG[0]
+	if !p.Equal(p) {
+		t.Error("a point should equal itself")
+	}
+	// -P shares its X coordinate with P
+	if p.Equal(p.Neg()) {
+		t.Error("P and -P should not be equal")
+	}
+}
+
+func TestGenerateNewParamsJacobian(t *testing.T) {
+	G := EC.BPG[:8]
+	H := EC.BPH[:8]
+	x, _ := EC.randScalar()
+	xinv := new(big.Int).ModInverse(x, EC.N)
+	L, R, P := EC.BPG[8], EC.BPG[9], EC.BPG[10]
+
+	Gprime, Hprime, Pprime, err := EC.GenerateNewParams(G, H, x, L, R, P)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if !Gprime[i].Equal(G[i].Mult(xinv).Add(G[i+4].Mult(x))) {
+			t.Errorf("G'[%d] is wrong", i)
+		}
+		if !Hprime[i].Equal(H[i].Mult(x).Add(H[i+4].Mult(xinv))) {
+			t.Errorf("H'[%d] is wrong", i)
+		}
+	}
+
+	x2 := new(big.Int).Mul(x, x)
+	if !Pprime.Equal(L.Mult(x2).Add(P).Add(R.Mult(new(big.Int).ModInverse(x2, EC.N)))) {
+		t.Error("P' is wrong")
+	}
+
+	// the point at infinity survives batch normalisation
+	points := toAffineSlice([]jacobianPoint{toJacobian(G[0]), {}, toJacobian(G[1])})
+	if !points[0].Equal(G[0]) || points[1].X.Sign() != 0 || !points[2].Equal(G[1]) {
+		t.Error("toAffineSlice is wrong")
+	}
+}
+
+func TestScalarArithmetic(t *testing.T) {
+	// the order of ristretto255 and a small prime, for which values below
+	// 2^256 can be many times the order
+	l, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	small := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	for _, N := range []*big.Int{EC.N, elliptic.P256().Params().N, l, small} {
+		f := newScalarField(N)
+		nMinus1 := new(big.Int).Sub(N, big.NewInt(1))
+		for i := 0; i < 200; i++ {
+			x, _ := rand.Int(rand.Reader, N)
+			y, _ := rand.Int(rand.Reader, N)
+			if i == 0 {
+				x, y = nMinus1, nMinus1
+			}
+			sx, sy := f.fromBig(x), f.fromBig(y)
+			var r scalar
+
+			if r.mul(&sx, &sy).big().Cmp(new(big.Int).Mod(new(big.Int).Mul(x, y), N)) != 0 {
+				t.Fatalf("scalar mul is wrong for %x * %x", x, y)
+			}
+			if r.add(&sx, &sy).big().Cmp(new(big.Int).Mod(new(big.Int).Add(x, y), N)) != 0 {
+				t.Fatalf("scalar add is wrong for %x + %x", x, y)
+			}
+			if r.sub(&sx, &sy).big().Cmp(new(big.Int).Mod(new(big.Int).Sub(x, y), N)) != 0 {
+				t.Fatalf("scalar sub is wrong for %x - %x", x, y)
+			}
+			if i < 20 && r.inverse(&sx).big().Cmp(new(big.Int).ModInverse(x, N)) != 0 {
+				t.Fatalf("scalar inverse is wrong for %x", x)
+			}
+		}
+
+		v := make([]*big.Int, 9)
+		for i := range v {
+			v[i], _ = rand.Int(rand.Reader, N)
+		}
+		for i, inv := range scalarBatchInverse(f.fromBigs(v)) {
+			if inv.big().Cmp(new(big.Int).ModInverse(v[i], N)) != 0 {
+				t.Fatalf("batch inverse %d is wrong", i)
+			}
+		}
+
+		// values of 2^256 and above are reduced too
+		large := new(big.Int).Lsh(N, 3)
+		large.Add(large, big.NewInt(1))
+		if s := f.fromBig(large); s.big().Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("fromBig(8N + 1) = %x", s.big())
+		}
+		for i := 0; i < 50; i++ {
+			x, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 256))
+			if i == 0 {
+				x.Sub(x.Lsh(big.NewInt(1), 256), big.NewInt(1))
+			}
+			if s := f.fromBig(x); s.big().Cmp(new(big.Int).Mod(x, N)) != 0 {
+				t.Fatalf("fromBig(%x) = %x mod %x", x, s.big(), N)
+			}
+		}
+
+		// the zero value takes the field of the other operand
+		var zero, r scalar
+		one := f.one()
+		if r.sub(&zero, &one).big().Cmp(nMinus1) != 0 {
+			t.Error("0 - 1 is not N - 1")
+		}
+	}
+
+	one := big.NewInt(1)
+	if _, ok := secretBits(one, 8); !ok {
+		t.Error("1 should fit in 8 bits")
+	}
+	if _, ok := secretBits(new(big.Int).Lsh(one, 8), 8); ok {
+		t.Error("2^8 should not fit in 8 bits")
+	}
+	if _, ok := secretBits(new(big.Int).Lsh(one, 64), 64); ok {
+		t.Error("2^64 should not fit in 64 bits")
+	}
+}
+
+func TestConstantTimeMultiScalarMul(t *testing.T) {
+	G := EC.BPG[:4]
+	points := []ECPoint{G[0], G[1], G[0], G[0].Neg(), {big.NewInt(0), big.NewInt(0)}, G[2], G[3]}
+	scalars := make([]*big.Int, len(points))
+	for i := range scalars {
+		scalars[i], _ = EC.randScalar()
+	}
+	// equal points, opposite points, zero scalars and infinity all go through
+	// the same complete formulas
+	scalars[2] = scalars[0]
+	scalars[3] = scalars[0]
+	scalars[5] = big.NewInt(0)
+
+	expected := multiScalarMul(points, scalars)
+	res := ctMultiScalarMul(toJacobianSlice(points), EC.fr.fromBigs(scalars))
+	if got := res.affine(); !got.Equal(expected) {
+		t.Error("ctMultiScalarMul does not match multiScalarMul")
+	}
+
+	g, _ := EC.randScalar()
+	h, _ := EC.randScalar()
+	a, _ := EC.RandVector(3)
+	sg, sh := EC.fr.fromBig(g), EC.fr.fromBig(h)
+	expected = EC.fixedBaseMult(g, h, nil, a, nil)
+	if got := EC.ctCommit(&sg, &sh, EC.fr.fromBigs(a), nil); !got.Equal(expected) {
+		t.Error("ctCommit does not match fixedBaseMult")
+	}
+}
+
+// dudect - a fixed-vs-random timing test in the style of dudect (Reparaz,
+// Balasch and Verbauwhede, 2016). run is called samples times with class 0 or
+// 1 picked at random and its durations are compared with Welch's t-test, once
+// over all of them and once each after cropping the slowest measurements at a
+// few percentiles. It returns the largest |t| found.
+func dudect(samples int, prepare func(class int) func()) float64 {
+	times := [2][]float64{}
+	classes := make([]byte, samples)
+	rand.Read(classes)
+	for _, c := range classes {
+		class := int(c & 1)
+		run := prepare(class)
+		start := time.Now()
+		run()
+		times[class] = append(times[class], float64(time.Since(start)))
+	}
+
+	all := append(append([]float64{}, times[0]...), times[1]...)
+	sort.Float64s(all)
+	maxT := 0.0
+	for _, pct := range []float64{1, 0.9, 0.75, 0.5} {
+		limit := all[int(pct*float64(len(all)-1))]
+		var n, mean, m2 [2]float64
+		for c := range times {
+			for _, v := range times[c] {
+				if v > limit {
+					continue
+				}
+				n[c]++
+				d := v - mean[c]
+				mean[c] += d / n[c]
+				m2[c] += d * (v - mean[c])
+			}
+		}
+		if n[0] < 2 || n[1] < 2 {
+			continue
+		}
+		se := math.Sqrt(m2[0]/(n[0]-1)/n[0] + m2[1]/(n[1]-1)/n[1])
+		if tt := math.Abs(mean[0]-mean[1]) / se; tt > maxT {
+			maxT = tt
+		}
+	}
+	return maxT
+}
+
+// dudectThreshold - the |t| above which dudect reports a timing leak
+const dudectThreshold = 10
+
+func TestConstantTimeProver(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test skipped in short mode")
+	}
+	ec := NewECPrimeGroupKey(8)
+	ec.fixedTables()
+
+	// class 0 commits to and proves 0, class 1 a random value. A prover that
+	// skips the work for zero bits or windows shows up immediately.
+	value := func(class int) *big.Int {
+		if class == 0 {
+			return big.NewInt(0)
+		}
+		v, _ := rand.Int(rand.Reader, big.NewInt(256))
+		return v
+	}
+
+	tc := dudect(2000, func(class int) func() {
+		v := ec.fr.fromBig(value(class))
+		gamma, _ := ec.fr.rand()
+		return func() { ec.ctCommit(&v, &gamma, nil, nil) }
+	})
+	if tc > dudectThreshold {
+		t.Errorf("commitment time depends on the value, |t| = %.1f", tc)
+	}
+
+	tp := dudect(300, func(class int) func() {
+		v := value(class)
+		gamma, _ := ec.randScalar()
+		return func() {
+			if _, err := ec.RPProveTrans(gamma, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if tp > dudectThreshold {
+		t.Errorf("proof time depends on the value, |t| = %.1f", tp)
+	}
+	t.Logf("commitment |t| = %.1f, proof |t| = %.1f", tc, tp)
+}
+
+func TestBitLengths(t *testing.T) {
+	ec := NewECPrimeGroupKey(256)
+	one := big.NewInt(1)
+
+	for _, n := range BitLengths {
+		max := new(big.Int).Sub(new(big.Int).Lsh(one, uint(n)), one)
+		rp, err := ec.RPProveBits(max, n)
+		if err != nil {
+			t.Fatalf("%d bits: %v", n, err)
+		}
+		if rp.Bits != n || len(rp.IPP.L) != bits.Len(uint(n))-1 {
+			t.Errorf("%d bits: proof records %d bits and has %d rounds", n, rp.Bits, len(rp.IPP.L))
+		}
+		if ok, err := ec.RPVerifyBits(&rp.Comm.Comm, &rp, n); !ok || err != nil {
+			t.Errorf("%d bits: proof does not verify: %v", n, err)
+		}
+		if ok, _ := ec.RPVerify(rp); !ok {
+			t.Errorf("%d bits: proof does not verify with the recorded bit length", n)
+		}
+
+		// the verifier holds the prover to the bit length it asks for
+		other := 8
+		if n == 8 {
+			other = 16
+		}
+		if ok, err := ec.RPVerifyBits(&rp.Comm.Comm, &rp, other); ok || !errors.Is(err, ErrMalformedProof) {
+			t.Errorf("%d bits: proof accepted as %d bits", n, other)
+		}
+
+		if _, err := ec.RPProveBits(new(big.Int).Add(max, one), n); !errors.Is(err, ErrValueOutOfRange) {
+			t.Errorf("%d bits: expected ErrValueOutOfRange, got %v", n, err)
+		}
+	}
+
+	// the bit length survives serialisation
+	comms, mrp, err := ec.MRPProveBits([]*big.Int{big.NewInt(200), big.NewInt(3)}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := mrp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt := MultiRangeProof{}
+	if err := rebuilt.Rebuild(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.MRPVerifyBits(&rebuilt, comms, 8); !ok || err != nil {
+		t.Errorf("rebuilt proof does not verify: %v", err)
+	}
+
+	// claiming a different bit length breaks the proof
+	rebuilt.Bits = 16
+	if ok, _ := ec.MRPVerify(&rebuilt, comms); ok {
+		t.Error("proof verified with the wrong bit length")
+	}
+
+	if _, err := ec.RPProveBits(one, 12); !errors.Is(err, ErrUnsupportedBitLength) {
+		t.Errorf("expected ErrUnsupportedBitLength, got %v", err)
+	}
+	small := NewECPrimeGroupKey(64)
+	if _, _, err := small.MRPProveBits([]*big.Int{one, one}, 64); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch for too few generators, got %v", err)
+	}
+}
+
+func TestIntervalProof(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	a, b := big.NewInt(546), big.NewInt(21000000)
+
+	for _, v := range []*big.Int{a, b, big.NewInt(1000000)} {
+		comm, mrp, err := ec.IntervalProve(v, a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mrp.Bits != 32 {
+			t.Errorf("expected a 32 bit proof, got %d", mrp.Bits)
+		}
+		if ok, err := ec.IntervalVerify(comm, a, b, &mrp); !ok || err != nil {
+			t.Errorf("proof for %v does not verify: %v", v, err)
+		}
+		// the proof is bound to the bounds it was made for
+		if ok, _ := ec.IntervalVerify(comm, big.NewInt(547), b, &mrp); ok {
+			t.Errorf("proof for %v verified with a different lower bound", v)
+		}
+	}
+
+	// the commitment from Commitment.Generate works as the value commitment
+	c := Commitment{}
+	if err := ec.GenerateCommitment(&c, bobPk, big.NewInt(600), big.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	mrp, comm, err := ec.IntervalProveTrans(c.Blind, big.NewInt(600), a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !comm.Equal(c.Comm) {
+		t.Error("IntervalProveTrans did not reuse the blinding factor")
+	}
+	if ok, err := ec.IntervalVerify(c.Comm, a, b, &mrp); !ok || err != nil {
+		t.Errorf("proof does not verify against the transaction commitment: %v", err)
+	}
+
+	if _, _, err := ec.IntervalProve(big.NewInt(545), a, b); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("expected ErrValueOutOfRange, got %v", err)
+	}
+	if _, _, err := ec.IntervalProve(a, b, a); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("expected ErrInvalidInterval, got %v", err)
+	}
+}
+
+func TestMultiRPVerifyPadded(t *testing.T) {
+	ec := NewECPrimeGroupKey(512)
+	bv := ec.NewBatchVerifier()
+
+	for m := 1; m <= 5; m++ {
+		values := make([]*big.Int, m)
+		for j := range values {
+			values[j] = big.NewInt(int64(1000 * (j + 1)))
+		}
+
+		proof, comms, err := ec.MRPProveTransBits(values, big.NewInt(42), 64)
+		if err != nil {
+			t.Fatalf("%d values: %v", m, err)
+		}
+		if len(comms) != m {
+			t.Fatalf("%d values: got %d commitments", m, len(comms))
+		}
+		if ok, err := ec.MRPVerify(&proof, comms); !ok || err != nil {
+			t.Errorf("%d values: proof does not verify: %v", m, err)
+		}
+		if err := bv.AddMultiRangeProof(comms, &proof); err != nil {
+			t.Fatal(err)
+		}
+
+		// the padding is not something the prover can choose
+		if m == 3 {
+			extra := append(append([]ECPoint{}, comms...), ec.G)
+			if ok, _ := ec.MRPVerify(&proof, extra); ok {
+				t.Error("proof verified with a non-zero padding commitment")
+			}
+		}
+	}
+
+	if ok, failed, err := bv.Verify(); !ok || err != nil {
+		t.Errorf("batch of padded proofs failed: %v %v", failed, err)
+	}
+
+	// the default bit length splits the generators between the padded values
+	comms, proof, err := ec.MRPProve([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.Bits != 128 {
+		t.Errorf("expected 128 bits per value, got %d", proof.Bits)
+	}
+	if ok, err := ec.MRPVerify(&proof, comms); !ok || err != nil {
+		t.Errorf("proof does not verify: %v", err)
+	}
+}
+
+func TestInnerProductArbitraryLength(t *testing.T) {
+	ec := NewECPrimeGroupKey(8)
+	for _, n := range []int{1, 3, 5, 6, 7} {
+		G, H := ec.BPG[:n], ec.BPH[:n]
+		a, _ := ec.RandVector(n)
+		b, _ := ec.RandVector(n)
+		c, _ := ec.InnerProduct(a, b)
+		P, _ := ec.TwoVectorPCommitWithGens(G, H, a, b)
+
+		ipp, err := ec.InnerProductProve(a, b, c, P, ec.U, G, H)
+		if err != nil {
+			t.Fatalf("length %d: %v", n, err)
+		}
+		if len(ipp.L) != bits.Len(uint(n-1)) {
+			t.Errorf("length %d: expected %d rounds, got %d", n, bits.Len(uint(n-1)), len(ipp.L))
+		}
+		if ok, err := ec.InnerProductVerify(c, P, ec.U, G, H, ipp); !ok || err != nil {
+			t.Errorf("length %d: proof does not verify: %v", n, err)
+		}
+		if ok, err := ec.InnerProductVerifyFast(c, P, ec.U, G, H, ipp); !ok || err != nil {
+			t.Errorf("length %d: proof does not verify with the fast verifier: %v", n, err)
+		}
+
+		wrong := new(big.Int).Add(c, big.NewInt(1))
+		if ok, _ := ec.InnerProductVerifyFast(wrong, P, ec.U, G, H, ipp); ok {
+			t.Errorf("length %d: proof verified for the wrong inner product", n)
+		}
+	}
+}
+
+func TestIPAWithOwnTranscript(t *testing.T) {
+	ec := NewECPrimeGroupKey(8)
+	ipa, err := ec.NewIPA(ec.BPG[:6], ec.BPH[:6], ec.U)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := ec.RandVector(6)
+	b, _ := ec.RandVector(6)
+	c, _ := ec.InnerProduct(a, b)
+	P, _ := ec.TwoVectorPCommitWithGens(ec.BPG[:6], ec.BPH[:6], a, b)
+
+	// the calling protocol binds P and c into its own transcript
+	transcript := func() *Transcript {
+		tr := ec.NewTranscript("vector opening")
+		tr.AppendPoint("P", P)
+		tr.AppendScalar("c", c)
+		return tr
+	}
+
+	ipp, err := ipa.Prove(transcript(), a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ipa.Verify(transcript(), P, c, &ipp); !ok || err != nil {
+		t.Errorf("argument does not verify: %v", err)
+	}
+	if ok, _ := ipa.Verify(ipa.Transcript(P, c), P, c, &ipp); ok {
+		t.Error("argument verified against a different transcript")
+	}
+
+	ser, err := ipp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt := InnerProdArg{}
+	if err := rebuilt.Rebuild(ser); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := ipa.VerificationScalars(transcript(), c, &rebuilt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := append(append(append([]ECPoint{P, ipa.U}, rebuilt.L...), rebuilt.R...), append(ipa.G, ipa.H...)...)
+	scalars := append(append(append([]*big.Int{big.NewInt(1), sc.U}, sc.L...), sc.R...), append(sc.G, sc.H...)...)
+	if sum, _ := MultiScalarMul(points, scalars); !sum.Equal(ec.Zero()) {
+		t.Error("verification scalars of the rebuilt argument do not sum to infinity")
+	}
+}
+
+func TestMRPVerifyRejectsTampering(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	comms, mrp, err := ec.MRPProve([]*big.Int{big.NewInt(7), big.NewInt(300)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.MRPVerify(&mrp, comms); !ok || err != nil {
+		t.Fatalf("proof does not verify: %v", err)
+	}
+
+	one := big.NewInt(1)
+	tampered := map[string]func(p *MultiRangeProof){
+		"A":     func(p *MultiRangeProof) { p.A = p.A.Add(ec.G) },
+		"S":     func(p *MultiRangeProof) { p.S = p.S.Add(ec.G) },
+		"T1":    func(p *MultiRangeProof) { p.T1 = p.T1.Add(ec.G) },
+		"T2":    func(p *MultiRangeProof) { p.T2 = p.T2.Add(ec.G) },
+		"Tau":   func(p *MultiRangeProof) { p.Tau = new(big.Int).Add(p.Tau, one) },
+		"Th":    func(p *MultiRangeProof) { p.Th = new(big.Int).Add(p.Th, one) },
+		"Mu":    func(p *MultiRangeProof) { p.Mu = new(big.Int).Add(p.Mu, one) },
+		"IPP.A": func(p *MultiRangeProof) { p.IPP.A = new(big.Int).Add(p.IPP.A, one) },
+		"IPP.L": func(p *MultiRangeProof) {
+			p.IPP.L = append([]ECPoint{p.IPP.L[0].Add(ec.G)}, p.IPP.L[1:]...)
+		},
+	}
+	for name, tamper := range tampered {
+		p := mrp
+		tamper(&p)
+		if ok, err := ec.MRPVerify(&p, comms); ok || err != nil {
+			t.Errorf("proof with a changed %s: ok %v, err %v", name, ok, err)
+		}
+	}
+
+	// a commitment to a different value
+	other := append([]ECPoint{comms[0].Add(ec.G)}, comms[1:]...)
+	if ok, _ := ec.MRPVerify(&mrp, other); ok {
+		t.Error("proof verified against the wrong commitments")
+	}
+}
+
+func TestBatchInvert(t *testing.T) {
+	ec := NewECPrimeGroupKey(8)
+	v, _ := ec.RandVector(7)
+	v[3] = new(big.Int).Add(ec.N, big.NewInt(5)) // not reduced
+	inv, err := ec.BatchInvert(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range v {
+		if inv[i].Cmp(new(big.Int).ModInverse(v[i], ec.N)) != 0 {
+			t.Errorf("inverse %d is wrong", i)
+		}
+	}
+
+	v[5] = new(big.Int).Set(ec.N)
+	if _, err := ec.BatchInvert(v); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("expected ErrNotInvertible, got %v", err)
+	}
+
+	// the s vector against its definition as a product over the bits of i
+	challenges, _ := ec.RandVector(4)
+	inverses := ec.batchInvert(challenges)
+	squares := ec.vectorHadamard(challenges, challenges)
+	s, sinv := ec.ipaSVector(challenges, inverses, squares)
+	for i := range s {
+		want := big.NewInt(1)
+		for j := range challenges {
+			x := inverses[j]
+			if i>>uint(j)&1 == 1 {
+				x = challenges[j]
+			}
+			want.Mod(want.Mul(want, x), ec.N)
+		}
+		if s[i].Cmp(want) != 0 {
+			t.Errorf("s_%d is wrong", i)
+		}
+		if new(big.Int).Mod(new(big.Int).Mul(s[i], sinv[i]), ec.N).Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("s_%d^-1 is wrong", i)
+		}
+	}
+}
+
+func TestRangeProofPlus(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	rp, err := ec.RPPlusProve(big.NewInt(1 << 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.RPPlusVerify(rp); !ok || err != nil {
+		t.Fatalf("proof does not verify: %v", err)
+	}
+
+	if _, err := ec.RPPlusProve(new(big.Int).Lsh(big.NewInt(1), 64)); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("expected ErrValueOutOfRange, got %v", err)
+	}
+
+	// both kinds of proof over the same commitment
+	gamma, _ := ec.randScalar()
+	v := big.NewInt(12345)
+	rpp, err := ec.RPPlusProveTrans(gamma, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := ec.RPProveTrans(gamma, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rpp.Comm.Comm.Equal(old.Comm.Comm) {
+		t.Fatal("the two kinds of proof commit to the value differently")
+	}
+	if ok, err := ec.RPPlusVerifyTrans(&old.Comm.Comm, &rpp); !ok || err != nil {
+		t.Errorf("Bulletproofs+ proof does not verify: %v", err)
+	}
+
+	ser, err := rpp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serOld, err := old.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ser) >= len(serOld) {
+		t.Errorf("Bulletproofs+ proof is %d characters, RangeProof is %d", len(ser), len(serOld))
+	}
+	rebuilt := RangeProofPlus{Comm: rpp.Comm}
+	if err := rebuilt.Rebuild(ser); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.RPPlusVerify(rebuilt); !ok || err != nil {
+		t.Errorf("rebuilt proof does not verify: %v", err)
+	}
+	if err := rebuilt.Rebuild("0OIl"); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("expected ErrMalformedProof, got %v", err)
+	}
+}
+
+func TestMultiRangeProofPlus(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	values := []*big.Int{big.NewInt(7), big.NewInt(300), big.NewInt(65535)}
+	comms, mrp, err := ec.MRPPlusProve(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.MRPPlusVerify(&mrp, comms); !ok || err != nil {
+		t.Fatalf("proof does not verify: %v", err)
+	}
+
+	ser, err := mrp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt := &MultiRangeProofPlus{}
+	if err := rebuilt.Rebuild(ser); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.MRPPlusVerify(rebuilt, comms); !ok || err != nil {
+		t.Errorf("rebuilt proof does not verify: %v", err)
+	}
+
+	one := big.NewInt(1)
+	tampered := map[string]func(p *MultiRangeProofPlus){
+		"A":      func(p *MultiRangeProofPlus) { p.A = p.A.Add(ec.G) },
+		"WIP.A":  func(p *MultiRangeProofPlus) { p.WIP.A = p.WIP.A.Add(ec.G) },
+		"WIP.B":  func(p *MultiRangeProofPlus) { p.WIP.B = p.WIP.B.Add(ec.G) },
+		"WIP.R1": func(p *MultiRangeProofPlus) { p.WIP.R1 = new(big.Int).Add(p.WIP.R1, one) },
+		"WIP.S1": func(p *MultiRangeProofPlus) { p.WIP.S1 = new(big.Int).Add(p.WIP.S1, one) },
+		"WIP.D1": func(p *MultiRangeProofPlus) { p.WIP.D1 = new(big.Int).Add(p.WIP.D1, one) },
+		"WIP.R": func(p *MultiRangeProofPlus) {
+			p.WIP.R = append([]ECPoint{p.WIP.R[0].Add(ec.G)}, p.WIP.R[1:]...)
+		},
+	}
+	for name, tamper := range tampered {
+		p := mrp
+		tamper(&p)
+		if ok, err := ec.MRPPlusVerify(&p, comms); ok || err != nil {
+			t.Errorf("proof with a changed %s: ok %v, err %v", name, ok, err)
+		}
+	}
+
+	other := append([]ECPoint{comms[0].Add(ec.G)}, comms[1:]...)
+	if ok, _ := ec.MRPPlusVerify(&mrp, other); ok {
+		t.Error("proof verified against the wrong commitments")
+	}
+	short := mrp
+	short.WIP.L = short.WIP.L[1:]
+	if _, err := ec.MRPPlusVerify(&short, comms); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("expected ErrMalformedProof, got %v", err)
+	}
+
+	// smaller bit lengths and a single value
+	comms, mrp, err = ec.MRPPlusProveBits([]*big.Int{big.NewInt(255)}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.MRPPlusVerify(&mrp, comms); !ok || err != nil {
+		t.Errorf("8 bit proof does not verify: %v", err)
+	}
+	if ok, err := ec.MRPPlusVerifyBits(&mrp, comms, 8); !ok || err != nil {
+		t.Errorf("8 bit proof does not verify as 8 bits: %v", err)
+	}
+	if ok, err := ec.MRPPlusVerifyBits(&mrp, comms, 16); ok || !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("8 bit proof verified as 16 bits: %v %v", ok, err)
+	}
+}
+
+// With enough generators for 128 bits, a verifier that means to accept 64
+// bit values has to say so, or a proof over 128 bits gets through
+func TestRangeProofPlusBits(t *testing.T) {
+	ec := NewECPrimeGroupKey(128)
+	gamma, _ := ec.randScalar()
+	rp, err := ec.RPPlusProveTransBits(gamma, new(big.Int).Lsh(big.NewInt(1), 100), 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.RPPlusVerify(rp); !ok || err != nil {
+		t.Fatalf("128 bit proof does not verify: %v", err)
+	}
+	if ok, err := ec.RPPlusVerifyBits(&rp.Comm.Comm, &rp, 128); !ok || err != nil {
+		t.Errorf("128 bit proof does not verify as 128 bits: %v", err)
+	}
+	if ok, err := ec.RPPlusVerifyBits(&rp.Comm.Comm, &rp, 64); ok || !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("128 bit proof verified as 64 bits: %v %v", ok, err)
+	}
+	if _, err := ec.RPPlusVerifyBits(&rp.Comm.Comm, &rp, 12); !errors.Is(err, ErrUnsupportedBitLength) {
+		t.Errorf("expected ErrUnsupportedBitLength, got %v", err)
+	}
+}
+
+func BenchmarkMRPPlusProve16(b *testing.B) {
+	ec := NewECPrimeGroupKey(64 * 16)
+	values := make([]*big.Int, 16)
+	for i := range values {
+		values[i] = big.NewInt(int64(i))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ec.MRPPlusProveBits(values, 64)
+	}
+}
+
+func BenchmarkMRPPlusVerify16(b *testing.B) {
+	ec := NewECPrimeGroupKey(64 * 16)
+	values := make([]*big.Int, 16)
+	for i := range values {
+		values[i] = big.NewInt(int64(i))
+	}
+	comms, mrp, _ := ec.MRPPlusProveBits(values, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ec.MRPPlusVerify(&mrp, comms)
+	}
+}
+
+// r1csExample proves a * b = c with a < b, a and b in 8 bits, c equal to 30
+// and flag a boolean
+func r1csExample(cs ConstraintSystem, a, b, c, flag Variable) error {
+	_, _, o := cs.Multiply(a.LC(), b.LC())
+	EqualGadget(cs, o.LC(), c.LC())
+	EqualGadget(cs, c.LC(), Constant(big.NewInt(30)))
+	BooleanGadget(cs, flag.LC())
+	if err := RangeGadget(cs, a.LC(), 8); err != nil {
+		return err
+	}
+	if err := RangeGadget(cs, b.LC(), 8); err != nil {
+		return err
+	}
+	return LessThanGadget(cs, a.LC(), b.LC(), 8)
+}
+
+func TestR1CSProof(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	prove := func(values ...int64) ([]ECPoint, R1CSProof, error) {
+		p := ec.NewR1CSProver(nil)
+		var comms []ECPoint
+		var vars []Variable
+		for _, v := range values {
+			gamma, _ := ec.randScalar()
+			V, x := p.Commit(big.NewInt(v), gamma)
+			comms = append(comms, V)
+			vars = append(vars, x)
+		}
+		if err := r1csExample(p, vars[0], vars[1], vars[2], vars[3]); err != nil {
+			return nil, R1CSProof{}, err
+		}
+		proof, err := p.Prove()
+		return comms, proof, err
+	}
+	verify := func(comms []ECPoint, proof *R1CSProof) (bool, error) {
+		v := ec.NewR1CSVerifier(nil)
+		var vars []Variable
+		for _, V := range comms {
+			vars = append(vars, v.Commit(V))
+		}
+		if err := r1csExample(v, vars[0], vars[1], vars[2], vars[3]); err != nil {
+			return false, err
+		}
+		return v.Verify(proof)
+	}
+
+	comms, proof, err := prove(3, 10, 30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := verify(comms, &proof); !ok || err != nil {
+		t.Fatalf("proof does not verify: %v", err)
+	}
+
+	ser, err := proof.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt := R1CSProof{}
+	if err := rebuilt.Rebuild(ser); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := verify(comms, &rebuilt); !ok || err != nil {
+		t.Errorf("rebuilt proof does not verify: %v", err)
+	}
+
+	// the wrong commitments and a changed proof
+	swapped := []ECPoint{comms[1], comms[0], comms[2], comms[3]}
+	if ok, _ := verify(swapped, &proof); ok {
+		t.Error("proof verified against the wrong commitments")
+	}
+	tampered := proof
+	tampered.Th = new(big.Int).Add(proof.Th, big.NewInt(1))
+	if ok, _ := verify(comms, &tampered); ok {
+		t.Error("proof with a changed t hat verified")
+	}
+
+	// statements that do not hold
+	for _, values := range [][]int64{{5, 7, 30, 1}, {10, 3, 30, 1}, {3, 10, 30, 2}} {
+		if _, _, err := prove(values...); !errors.Is(err, ErrUnsatisfiedConstraint) && !errors.Is(err, ErrValueOutOfRange) {
+			t.Errorf("%v: expected an unsatisfied constraint, got %v", values, err)
+		}
+	}
+}
+
+func TestR1CSRangeProof(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	v := new(big.Int).SetUint64(1<<63 + 12345)
+	gamma, _ := ec.randScalar()
+
+	p := ec.NewR1CSProver(nil)
+	V, x := p.Commit(v, gamma)
+	if err := RangeGadget(p, x.LC(), 64); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := p.Prove()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vf := ec.NewR1CSVerifier(nil)
+	if err := RangeGadget(vf, vf.Commit(V).LC(), 64); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := vf.Verify(&proof); !ok || err != nil {
+		t.Errorf("proof does not verify: %v", err)
+	}
+
+	// the verifier asks for fewer bits
+	vf = ec.NewR1CSVerifier(nil)
+	RangeGadget(vf, vf.Commit(V).LC(), 32)
+	if _, err := vf.Verify(&proof); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("expected ErrMalformedProof, got %v", err)
+	}
+
+	p = ec.NewR1CSProver(nil)
+	_, x = p.Commit(v, gamma)
+	if err := RangeGadget(p, x.LC(), 32); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("expected ErrValueOutOfRange, got %v", err)
+	}
+	if _, _, _, err := p.AllocateMultiplier(nil, big.NewInt(1)); !errors.Is(err, ErrMissingAssignment) {
+		t.Errorf("expected ErrMissingAssignment, got %v", err)
+	}
+}
+
+func TestShuffleProof(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	values := []*big.Int{big.NewInt(10), big.NewInt(20), big.NewInt(30), big.NewInt(20), big.NewInt(5)}
+	blinds, _ := ec.RandVector(len(values))
+	inputs := make([]ECPoint, len(values))
+	for i := range values {
+		inputs[i] = ec.G.Mult(values[i]).Add(ec.H.Mult(blinds[i]))
+	}
+
+	perm := []int{2, 4, 0, 3, 1}
+	proof, outputs, newBlinds, err := ec.ShuffleProve(values, blinds, perm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, j := range perm {
+		if !outputs[i].Equal(ec.G.Mult(values[j]).Add(ec.H.Mult(newBlinds[i]))) {
+			t.Fatalf("output %d does not open to input %d", i, j)
+		}
+	}
+	if ok, err := ec.ShuffleVerify(inputs, outputs, &proof); !ok || err != nil {
+		t.Fatalf("proof does not verify: %v", err)
+	}
+
+	ser, err := proof.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt := ShuffleProof{}
+	if err := rebuilt.Rebuild(ser); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.ShuffleVerify(inputs, outputs, &rebuilt); !ok || err != nil {
+		t.Errorf("rebuilt proof does not verify: %v", err)
+	}
+
+	// an output that is not a shuffled input
+	changed := append([]ECPoint{outputs[0].Add(ec.G)}, outputs[1:]...)
+	if ok, _ := ec.ShuffleVerify(inputs, changed, &proof); ok {
+		t.Error("proof verified with a changed output")
+	}
+	if _, err := ec.ShuffleVerify(inputs, outputs[1:], &proof); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	if _, _, _, err := ec.ShuffleProve(values, blinds, []int{0, 1, 1, 3, 4}); !errors.Is(err, ErrInvalidPermutation) {
+		t.Errorf("expected ErrInvalidPermutation, got %v", err)
+	}
+
+	// a single value
+	proof, outputs, _, err = ec.ShuffleProve(values[:1], blinds[:1], []int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.ShuffleVerify(inputs[:1], outputs, &proof); !ok || err != nil {
+		t.Errorf("single value proof does not verify: %v", err)
+	}
+}
+
+// runMPC runs the aggregation protocol for values over n bits, passing every
+// message through Serialize and Rebuild, and lets tamper change the shares
+func runMPC(ec *CryptoParams, values []*big.Int, n int, tamper func([]ProofShare)) ([]ECPoint, MultiRangeProof, error) {
+	m := len(values)
+	dealer, err := ec.NewDealer(n, m)
+	if err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+
+	parties := make([]*PartyAwaitingBitChallenge, m)
+	bcs := make([]BitCommitment, m)
+	comms := make([]ECPoint, m)
+	for j, v := range values {
+		gamma, _ := ec.randScalar()
+		p, err := ec.NewParty(v, gamma, n)
+		if err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+		parties[j], bcs[j], err = p.AssignPosition(j)
+		if err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+		ser, _ := bcs[j].Serialize()
+		bcs[j] = BitCommitment{}
+		if err := bcs[j].Rebuild(ser); err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+		comms[j] = bcs[j].V
+	}
+
+	dealerPoly, bitChallenge, err := dealer.ReceiveBitCommitments(bcs)
+	if err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+	ser, _ := bitChallenge.Serialize()
+	bitChallenge = BitChallenge{}
+	if err := bitChallenge.Rebuild(ser); err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+
+	polyParties := make([]*PartyAwaitingPolyChallenge, m)
+	pcs := make([]PolyCommitment, m)
+	for j := range parties {
+		polyParties[j], pcs[j], err = parties[j].ApplyChallenge(bitChallenge)
+		if err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+		ser, _ := pcs[j].Serialize()
+		pcs[j] = PolyCommitment{}
+		if err := pcs[j].Rebuild(ser); err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+	}
+
+	dealerShares, polyChallenge, err := dealerPoly.ReceivePolyCommitments(pcs)
+	if err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+	ser, _ = polyChallenge.Serialize()
+	polyChallenge = PolyChallenge{}
+	if err := polyChallenge.Rebuild(ser); err != nil {
+		return nil, MultiRangeProof{}, err
+	}
+
+	shares := make([]ProofShare, m)
+	for j := range polyParties {
+		shares[j], err = polyParties[j].ApplyChallenge(polyChallenge)
+		if err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+		ser, _ := shares[j].Serialize()
+		shares[j] = ProofShare{}
+		if err := shares[j].Rebuild(ser); err != nil {
+			return nil, MultiRangeProof{}, err
+		}
+	}
+	if tamper != nil {
+		tamper(shares)
+	}
+
+	mrp, err := dealerShares.ReceiveShares(shares)
+	return comms, mrp, err
+}
+
+func TestMPCAggregation(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	values := []*big.Int{big.NewInt(7), big.NewInt(65535), big.NewInt(300)}
+	comms, mrp, err := runMPC(&ec, values, 16, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ec.MRPVerify(&mrp, comms); !ok || err != nil {
+		t.Fatalf("aggregated proof does not verify: %v", err)
+	}
+
+	// parties 0 and 2 send shares that do not match their commitments
+	_, _, err = runMPC(&ec, values, 16, func(shares []ProofShare) {
+		shares[0].L[3] = new(big.Int).Add(shares[0].L[3], big.NewInt(1))
+		shares[2].Tau = new(big.Int).Add(shares[2].Tau, big.NewInt(1))
+	})
+	var pe *PartyError
+	if !errors.As(err, &pe) || !errors.Is(err, ErrMisbehavingParty) {
+		t.Fatalf("expected a PartyError, got %v", err)
+	}
+	if len(pe.Parties) != 2 || pe.Parties[0] != 0 || pe.Parties[1] != 2 {
+		t.Errorf("blamed parties %v, expected [0 2]", pe.Parties)
+	}
+
+	// a value out of range and a dealer sending a zero challenge
+	if _, err := ec.NewParty(big.NewInt(65536), big.NewInt(1), 16); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("expected ErrValueOutOfRange, got %v", err)
+	}
+	p, _ := ec.NewParty(big.NewInt(1), big.NewInt(1), 16)
+	waiting, _, err := p.AssignPosition(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := waiting.ApplyChallenge(BitChallenge{big.NewInt(1), big.NewInt(0)}); !errors.Is(err, ErrMaliciousDealer) {
+		t.Errorf("expected ErrMaliciousDealer, got %v", err)
+	}
+
+	// a dealer asking for a second answer to either challenge, which would
+	// reveal the party's bits and blinding factors
+	polyWaiting, _, err := waiting.ApplyChallenge(BitChallenge{big.NewInt(3), big.NewInt(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := waiting.ApplyChallenge(BitChallenge{big.NewInt(7), big.NewInt(11)}); !errors.Is(err, ErrMaliciousDealer) {
+		t.Errorf("second bit challenge: expected ErrMaliciousDealer, got %v", err)
+	}
+	if _, err := polyWaiting.ApplyChallenge(PolyChallenge{big.NewInt(13)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := polyWaiting.ApplyChallenge(PolyChallenge{big.NewInt(17)}); !errors.Is(err, ErrMaliciousDealer) {
+		t.Errorf("second poly challenge: expected ErrMaliciousDealer, got %v", err)
+	}
+}
+
+func TestMPCDealerSingleUse(t *testing.T) {
+	ec := NewECPrimeGroupKey(64)
+	dealer, err := ec.NewDealer(16, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parties := make([]*PartyAwaitingBitChallenge, 3)
+	bcs := make([]BitCommitment, 3)
+	for j := range parties {
+		p, _ := ec.NewParty(big.NewInt(int64(j)), big.NewInt(1), 16)
+		if parties[j], bcs[j], err = p.AssignPosition(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dealerPoly, bitChallenge, err := dealer.ReceiveBitCommitments(bcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dealer.ReceiveBitCommitments(bcs); !errors.Is(err, ErrStateReused) {
+		t.Errorf("second bit commitments: expected ErrStateReused, got %v", err)
+	}
+
+	pcs := make([]PolyCommitment, 3)
+	for j := range parties {
+		if _, pcs[j], err = parties[j].ApplyChallenge(bitChallenge); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := dealerPoly.ReceivePolyCommitments(pcs); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dealerPoly.ReceivePolyCommitments(pcs); !errors.Is(err, ErrStateReused) {
+		t.Errorf("second poly commitments: expected ErrStateReused, got %v", err)
+	}
+}
+
+// TestSecp256k1ZkpH checks H against secp256k1_generator_h_internal in
+// src/modules/generator/main_impl.h of secp256k1-zkp, which stores X then Y
+func TestSecp256k1ZkpH(t *testing.T) {
+	want := mustHex(t, "50929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0" +
+		"31d3c6863973926e049e637cb1b5f40a36dac28af1766968c30c2313f3a38904")
+	H := Secp256k1ZkpH()
+	got := append(H.X.FillBytes(make([]byte, 32)), H.Y.FillBytes(make([]byte, 32))...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("H is %x, want %x", got, want)
+	}
+	if !Secp256k1().IsOnCurve(H) {
+		t.Error("H is not on secp256k1")
+	}
+}
+
+func TestGroupBackends(t *testing.T) {
+	for _, g := range []Group{Secp256k1(), P256(), Ristretto255()} {
+		ec, err := NewGroupParams(g, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range append([]ECPoint{ec.G, ec.H, ec.U}, ec.BPG[:3]...) {
+			if !g.IsOnCurve(p) {
+				t.Fatalf("%s: generator is not in the group", g.Name())
+			}
+			if q, err := g.Decode(g.Encode(p)); err != nil || !q.Equal(p) {
+				t.Fatalf("%s: generator does not survive Encode and Decode: %v", g.Name(), err)
+			}
+		}
+
+		rp, err := ec.RPProve(big.NewInt(1234))
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if ok, err := ec.RPVerify(rp); !ok || err != nil {
+			t.Errorf("%s: range proof does not verify: %v", g.Name(), err)
+		}
+		rp.Th = new(big.Int).Add(rp.Th, big.NewInt(1))
+		if ok, _ := ec.RPVerify(rp); ok {
+			t.Errorf("%s: tampered range proof verified", g.Name())
+		}
+
+		comms, mrp, err := ec.MRPProveBits([]*big.Int{big.NewInt(7), big.NewInt(300), big.NewInt(0)}, 16)
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if ok, err := ec.MRPVerifyBits(&mrp, comms, 16); !ok || err != nil {
+			t.Errorf("%s: aggregate range proof does not verify: %v", g.Name(), err)
+		}
+		if ok, _ := ec.MRPVerifyBits(&mrp, []ECPoint{comms[1], comms[0], comms[2]}, 16); ok {
+			t.Errorf("%s: aggregate range proof verified against swapped commitments", g.Name())
+		}
+		encoded, err := mrp.SerializeWithParams(&ec)
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		var rebuilt MultiRangeProof
+		if err := rebuilt.RebuildWithParams(&ec, encoded); err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if ok, err := ec.MRPVerifyBits(&rebuilt, comms, 16); !ok || err != nil {
+			t.Errorf("%s: rebuilt aggregate range proof does not verify: %v", g.Name(), err)
+		}
+		raw, err := mrp.BytesWithParams(&ec)
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if A := g.Encode(mrp.A); !bytes.HasPrefix(raw, A) || !bytes.Contains(raw, g.Encode(mrp.IPP.L[0])) {
+			t.Errorf("%s: Bytes does not encode the points for the group", g.Name())
+		}
+
+		plus, err := ec.RPPlusProve(big.NewInt(99))
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if ok, err := ec.RPPlusVerify(plus); !ok || err != nil {
+			t.Errorf("%s: bulletproofs+ range proof does not verify: %v", g.Name(), err)
+		}
+		encoded, err = plus.SerializeWithParams(&ec)
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		rebuiltPlus := RangeProofPlus{Comm: plus.Comm}
+		if err := rebuiltPlus.RebuildWithParams(&ec, encoded); err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if ok, err := ec.RPPlusVerify(rebuiltPlus); !ok || err != nil {
+			t.Errorf("%s: rebuilt bulletproofs+ range proof does not verify: %v", g.Name(), err)
+		}
+
+		a, _ := ec.RandVector(5)
+		b, _ := ec.RandVector(5)
+		c, _ := ec.InnerProduct(a, b)
+		P, _ := ec.TwoVectorPCommitWithGens(ec.BPG[:5], ec.BPH[:5], a, b)
+		ipp, err := ec.InnerProductProve(a, b, c, P, ec.U, ec.BPG[:5], ec.BPH[:5])
+		if err != nil {
+			t.Fatalf("%s: %v", g.Name(), err)
+		}
+		if ok, err := ec.InnerProductVerify(c, P, ec.U, ec.BPG[:5], ec.BPH[:5], ipp); !ok || err != nil {
+			t.Errorf("%s: inner product argument does not verify: %v", g.Name(), err)
+		}
+		if ok, err := ec.InnerProductVerifyFast(c, P, ec.U, ec.BPG[:5], ec.BPH[:5], ipp); !ok || err != nil {
+			t.Errorf("%s: inner product argument does not verify with the fast verifier: %v", g.Name(), err)
+		}
+	}
+
+	// the SEC1 encoding of the secp256k1 base point, and its negation
+	base := ECPoint{curve.Gx, curve.Gy}
+	enc := Secp256k1().Encode(base)
+	if got := fmt.Sprintf("%x", enc); got != "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" {
+		t.Errorf("secp256k1 base point encodes as %s", got)
+	}
+	enc[0] = 3
+	if neg, err := Secp256k1().Decode(enc); err != nil || !neg.Equal(base.Neg()) {
+		t.Errorf("03 || Gx does not decode to -G: %v", err)
+	}
+	enc[0] = 4
+	if _, err := Secp256k1().Decode(enc); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("bad prefix: got %v, want ErrInvalidPoint", err)
+	}
+
+	// a point of one group is not accepted by a verifier over the other
+	p256, _ := NewGroupParams(P256(), 64)
+	rp, _ := EC.RPProve(big.NewInt(5))
+	if _, err := p256.RPVerify(rp); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("secp256k1 commitment checked over P-256: got %v, want ErrInvalidPoint", err)
+	}
+}
+
+// The known answers are the P256_XMD:SHA-256_SSWU_RO_ vectors of appendix
+// J.1.1, the secp256k1_XMD:SHA-256_SSWU_RO_ vectors of appendix J.8.1 and the
+// expand_message_xmd vector of appendix K.1 of RFC 9380
+func TestHashToCurve(t *testing.T) {
+	uniform := expandMessageXMD(sha256.New, nil, []byte("QUUX-V01-CS02-with-expander-SHA256-128"), 32)
+	if got := fmt.Sprintf("%x", uniform); got != "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235" {
+		t.Errorf("expand_message_xmd gave %s", got)
+	}
+
+	vectors := []struct {
+		suite     *h2cSuite
+		dst       string
+		msg, x, y string
+	}{
+		{secp256k1Suite, HashToCurveSuite, "", "c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346", "64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067"},
+		{secp256k1Suite, HashToCurveSuite, "abc", "3377e01eab42db296b512293120c6cee72b6ecf9f9205760bd9ff11fb3cb2c4b", "7f95890f33efebd1044d382a01b1bee0900fb6116f94688d487c6c7b9c8371f6"},
+		{p256Suite, P256HashToCurveSuite, "", "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+		{p256Suite, P256HashToCurveSuite, "abc", "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+	}
+	for _, v := range vectors {
+		p := v.suite.hashToCurve([]byte(v.msg), []byte("QUUX-V01-CS02-with-"+v.dst))
+		if x, y := fmt.Sprintf("%064x", p.X), fmt.Sprintf("%064x", p.Y); x != v.x || y != v.y {
+			t.Errorf("%s: hash_to_curve(%q) = (%s, %s)", v.dst, v.msg, x, y)
+		}
+	}
+}
+
+func TestCheckGenerators(t *testing.T) {
+	seeds := GeneratorSeeds(4)
+	if len(seeds) != 11 || seeds[5].Role != "BPH" || seeds[5].Index != 1 || seeds[10].Role != "H" {
+		t.Fatalf("unexpected seeds %v", seeds)
+	}
+	if !bytes.Equal(seeds[5].Seed, append([]byte("bulletproofs BPH"), 0, 0, 0, 1)) {
+		t.Errorf("BPH[1] has seed %q", seeds[5].Seed)
+	}
+
+	if err := CheckGenerators(); err != nil {
+		t.Errorf("package generators: %v", err)
+	}
+	ec, err := NewGroupParams(P256(), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ec.CheckGenerators(); err != nil {
+		t.Errorf("P-256 generators: %v", err)
+	}
+
+	ec = NewECPrimeGroupKey(8)
+	ec.BPH[3] = ec.BPH[3].Add(ec.G)
+	if err := ec.CheckGenerators(); !errors.Is(err, ErrInvalidGenerators) {
+		t.Errorf("tampered BPH[3] gave %v", err)
+	}
+	legacy := NewLegacyECPrimeGroupKey(8)
+	if err := legacy.CheckGenerators(); !errors.Is(err, ErrInvalidGenerators) {
+		t.Errorf("legacy generators gave %v", err)
+	}
+
+	// a proof serialised with the legacy generators still verifies with them,
+	// and only with them
+	rp, err := legacy.RPProve(big.NewInt(77))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ser, err := rp.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rebuilt RangeProof
+	if err := rebuilt.Rebuild(ser); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := legacy.RPVerifyTrans(&rp.Comm.Comm, &rebuilt); !ok || err != nil {
+		t.Errorf("range proof over legacy generators does not verify: %v", err)
+	}
+	current := NewECPrimeGroupKey(8)
+	if ok, _ := current.RPVerifyTrans(&rp.Comm.Comm, &rebuilt); ok {
+		t.Error("range proof over legacy generators verified with the current ones")
+	}
+
+	if _, err := NewGroupParams(Secp256k1(), 0); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("NewGroupParams with n = 0: expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+// Proofs serialised by the code before the Fiat-Shamir transcript: a range
+// proof of 1234567 over NewLegacyECPrimeGroupKey(64), and an aggregate proof
+// of 77 and 40000 over NewLegacyECPrimeGroupKey(32)
+var (
+	legacyRangeProof = "2NACrVgDp2G6sqjb8dokYgJgr5FnX6crWkRCckMF2KWTKQw6jizoi4wJzK3DXrE1kfdhvaVCPTM9UfqFiEVE26siepiy38wZ" +
+		"jYkjJU7RaBi4bMqTQaSzABiMQZkaqHmXzJEv97H5zkMgsh1qw5EE9HtAayMRcxVbRqV57DHu4oS83zJ4SLCc1dBs3RW3pnrk" +
+		"EbTtdfg63WsnDYFFiUvmopJ5Jw76dyF8u4FgKpzxmnPNZJcDyzd7tPQ2rsCabwiVoqB6N1aHez3QczQSuMLBFqLVKuedZtV8" +
+		"q1JDn1mfNWV8a58X8Sxq5BNvAuwy6pKg1Z7Vrd35RZBX9D6cgzQ4zGWz7zfjo2M6rmRafSHkx7RXGUZip5LNwnZN5qeke4s4" +
+		"fdFmbq9XQTdDYva9NEso5r1BNsb8nLj4yCR6VMKQEcK6d6KVYnPW1CvSt2kuJRcnkhjKDSEsh5cc9aoZ56LfnQcS3FGmcwMd" +
+		"iWUE2PCUrCW5Yra7qoP8wTfcqAjQbtaSRxWzX2uR76pYECuHH6jYECHmDYnZchqaGEnjf5q11WziewvytmomaBEPrfM9rBDm" +
+		"57uXhjRTSavv4HuJ2CQmax9PiS7M5TeCwPyd6FYqQsivmcaPvyG89b9S6qfA9qTAp44XLt71abQMRbJZrbQYxCoRTt1nPm2W" +
+		"KtHbFv67vVHSto2SAgpqbp1LaXufQtC5o1WUbNjghs5wXRSwfN26TQsH6eTVoVDsN2zAykmtSqrGRRTVGgqGazVWi6F66M8T" +
+		"67vgFuCZSUiMgXruB6R36z6Pm1CE7vAQ2BHsNnXtv3dc36pmUdtxErKVanVEGR23QMzsqtBcdvUArjHNioddUfaXV51m1Uuv" +
+		"y7jUVQJtAK3ya4HCdGn6zR1g1KYtHVXJJnpt33QMgVyNB6L9sUyZu7ijNcMC2ULYN1FEtZkfeSxmAU3hj7c1wVmMaHAwwLnY" +
+		"WDQwzk3w535Dqrp4Ftu21hz9JAWEa7tKhXsxPvQNmJzJ8JhdqRfHAKhYg3e2z6epzNEavRsM5gkWgx2b36ZtY"
+	legacyRangeComm  = ECPoint{hexInt("afb5b47a4527eea104973f4679b920f7ebcf0b98a69ed7ae9a386b88ac96c568"), hexInt("e8ba1dbefa53a34a0cdf3e553ead3b11d34cd68607a68bcff52affc1f3a04172")}
+
+	legacyMultiRangeProof = "25NQCowAeo7c7byviXj1gnTv4UWrmbhEK83DMLJVZHzTj5eFjrDmFFfNU6B6HPRLVGE1AANucCrJRdSNXZCwGfuj6UPA9zuy" +
+		"Q1M92ipusnqWShPSgF8qh5vdJzJUMGN3bbxvJfrJa1XByWv68meWc4uqkTqHc45EqCfky3vyhnTmz6BsJHrdthV5dKBABTxG" +
+		"iKdd7VbsjDXWz6cLMSJokhHvPducjkexwzyA2pWV1WBqEGzmtnMcVKsaUDEKQkciib7whMeQEynieFF2w3vn9CidJNLbLB47" +
+		"Ugknc3KL2x2urkZwB9UXr5Nqm8fbWiBj1xjXNfgqjYmaM9kh9ymytyUGqR2zoHjHznEMZUUHhFoF1T4BaRvs2VgecCgXZaND" +
+		"zRQZc4tH2a8PE2Mrz5GP5dWKaA7pSA5rxhYZWi4iKiREZ44VtTfY8GiXNg7zrRQ2RfiJ7cXEBTEY6pEsJBSsUh5uY9vXWnMg" +
+		"cmUM7CpyqWJF8p2v78Q5YabxwmeVfXVL1VjveJh33PE8LzFuBpi1GpGzyQMdc9dDpr6JnDu4yMDPReQmo6ngTmi5FfgYZmgR" +
+		"gbTiDLbS8T8Do73WEw1aJa5UHd6roa4o228VX4x98y9hAM1XuNqDQ93THZnnMVp9ko7cfAiMxXkCcmioxHDe4N9T31UgMZLH" +
+		"7uU6iDjajT6qrTaVzwcaGGnfEDCKajK9qqcZVeYKid4j1v2QYdG4WuQGvp87gnB7rVog9tj1vFe3bVCMXH8QUBeCeSJPPcKE" +
+		"rUj8K7twUWBvkeVzBuNPNAqcpYi6i1tgKL1XNDxYhnXpj73QiScD97tCmdKSrcTxK1JNDc8Pcue3eguhmaGWEhyWg5giX4Wn" +
+		"FKTjyum2keXnEwGomzFFArhDdArX6uDNXj1qn6JN6gnESAAEgjnjGGmcVLVhZGVhR4rWmgjNzDKb7LPz"
+	legacyMultiRangeComms = []ECPoint{
+		{hexInt("aa03a0ab27f8f97deb4df3eb0ae4c2a81dff1c13d230c7b59ddd712aaba23b21"), hexInt("f37ffc2a435851d9c9c9fc64aaf2674aeb8bb75fbfdc7b700c1583519ce682a5")},
+		{hexInt("73738a1c99407886f8b9335bb2aeb5f9a015898e2f7554bbf8ee383b2e96462c"), hexInt("829a3fcd941d2327b37034b5b093b1078467e0fe11dbf7c9b283354d2e6aefde")},
+	}
+)
+
+func TestLegacyProofs(t *testing.T) {
+	legacy := NewLegacyECPrimeGroupKey(64)
+	var rp RangeProof
+	if err := rp.Rebuild(legacyRangeProof); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := legacy.LegacyRPVerify(&legacyRangeComm, &rp); !ok || err != nil {
+		t.Errorf("legacy range proof does not verify: %v", err)
+	}
+	if ok, _ := legacy.RPVerifyTrans(&legacyRangeComm, &rp); ok {
+		t.Error("legacy range proof verified with the transcript")
+	}
+	if ok, _ := EC.LegacyRPVerify(&legacyRangeComm, &rp); ok {
+		t.Error("legacy range proof verified with the current generators")
+	}
+	other := legacyMultiRangeComms[0]
+	if ok, _ := legacy.LegacyRPVerify(&other, &rp); ok {
+		t.Error("legacy range proof verified against another commitment")
+	}
+	rp.Mu = new(big.Int).Add(rp.Mu, big.NewInt(1))
+	if ok, _ := legacy.LegacyRPVerify(&legacyRangeComm, &rp); ok {
+		t.Error("tampered legacy range proof verified")
+	}
+
+	legacy = NewLegacyECPrimeGroupKey(32)
+	var mrp MultiRangeProof
+	if err := mrp.Rebuild(legacyMultiRangeProof); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := legacy.LegacyMRPVerify(&mrp, legacyMultiRangeComms); !ok || err != nil {
+		t.Errorf("legacy aggregate range proof does not verify: %v", err)
+	}
+	swapped := []ECPoint{legacyMultiRangeComms[1], legacyMultiRangeComms[0]}
+	if ok, _ := legacy.LegacyMRPVerify(&mrp, swapped); ok {
+		t.Error("legacy aggregate range proof verified against swapped commitments")
+	}
+	if ok, _ := legacy.LegacyMRPVerify(&mrp, legacyMultiRangeComms[:1]); ok {
+		t.Error("legacy aggregate range proof verified against one commitment")
+	}
+}
+
+func TestRistretto255(t *testing.T) {
+	if got := fmt.Sprintf("%x", expandMessageXMD(sha512.New, nil, []byte("QUUX-V01-CS02-with-expander-SHA512-256"), 32)); got != "6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba" {
+		t.Errorf("expand_message_xmd with SHA-512 gave %s", got)
+	}
+
+	g := Ristretto255()
+	B, err := g.Decode(mustHex(t, "e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 9496 appendix A.1: the multiples 0 B to 15 B
+	multiples := []string{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76",
+		"6a493210f7499cd17fecb510ae0cea23a110e8d5b901f8acadd3095c73a3b919",
+		"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+		"da80862773358b466ffadfe0b3293ab3d9fd53c5ea6c955358f568322daf6a57",
+		"e882b131016b52c1d3337080187cf768423efccbb517bb495ab812c4160ff44e",
+		"f64746d3c92b13050ed8d80236a7f0007c3b3f962f5ba793d19a601ebb1df403",
+		"44f53520926ec81fbd5a387845beb7df85a96a24ece18738bdcfa6a7822a176d",
+		"903293d8f2287ebe10e2374dc1a53e0bc887e592699f02d077d5263cdd55601c",
+		"02622ace8f7303a31cafc63f8fc48fdc16e1c8c8d234b2f0d6685282a9076031",
+		"20706fd788b2720a1ed2a5dad4952b01f413bcf0e7564de8cdc816689e2db95f",
+		"bce83f8ba5dd2fa572864c24ba1810f9522bc6004afe95877ac73241cafdab42",
+		"e4549ee16b9aa03099ca208c67adafcafa4c3f3e4e5303de6026e3ca8ff84460",
+		"aa52e000df2e16f55fb1032fc33bc42742dad6bd5a8fc0be0167436c5948501f",
+		"46376b80f409b29dc2b5f6f0c52591990896e5716f41477cd30085ab7f10301e",
+		"e0c418f7c8d9c4cdd7395b93ea124f3ad99021bb681dfc3302a9d99a2e53e64e",
+	}
+	sum := ECPoint{big.NewInt(0), big.NewInt(0)}
+	for i, want := range multiples {
+		p := g.ScalarMult(B, big.NewInt(int64(i)).Bytes())
+		if got := hex.EncodeToString(g.Encode(p)); got != want {
+			t.Errorf("%d B = %s", i, got)
+		}
+		if !sum.Equal(p) {
+			t.Errorf("adding B %d times does not give %d B", i, i)
+		}
+		if q, err := g.Decode(mustHex(t, want)); err != nil || !q.Equal(p) {
+			t.Errorf("%d B does not decode: %v", i, err)
+		}
+		if g.IsOnCurve(p) != (i != 0) {
+			t.Errorf("IsOnCurve(%d B) = %v", i, g.IsOnCurve(p))
+		}
+		sum = g.Add(sum, B)
+	}
+	minus := g.ScalarMult(B, new(big.Int).Sub(g.Order(), big.NewInt(5)).Bytes())
+	if !minus.Equal(g.Neg(g.ScalarMult(B, []byte{5}))) {
+		t.Error("(l - 5) B is not -(5 B)")
+	}
+	five, _ := g.Decode(mustHex(t, multiples[5]))
+	msm := g.MultiScalarMul([]ECPoint{B, five}, []*big.Int{big.NewInt(2), new(big.Int).Add(g.Order(), big.NewInt(1))})
+	if got := hex.EncodeToString(g.Encode(msm)); got != multiples[7] {
+		t.Errorf("2 B + (l + 1) 5 B = %s", got)
+	}
+
+	// RFC 9496 appendix A.2: encodings that must be rejected
+	bad := []string{
+		// non-canonical field encodings
+		"00ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"f3ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// negative field elements
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"01ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"ed57ffd8c914fb201471d1c3d245ce3c746fcbe63a3679d51b6a516ebebe0e20",
+		"c34c4e1826e5d403b78e246e88aa051c36ccf0aafebffe137d148a2bf9104562",
+		"c940e5a4404157cfb1628b108db051a8d439e1a421394ec4ebccb9ec92a8ac78",
+		"47cfc5497c53dc8e61c91d17fd626ffb1c49e2bca94eed052281b510b1117a24",
+		"f1c6165d33367351b0da8f6e4511010c68174a03b6581212c71c0e1d026c3c72",
+		"87260f7a2f12495118360f02c26a470f450dadf34a413d21042b43b9d93e1309",
+		// non-square x^2
+		"26948d35ca62e643e26a83177332e6b6afeb9d08e4268b650f1f5bbd8d81d371",
+		"4eac077a713c57b4f4397629a4145982c661f48044dd3f96427d40b147d9742f",
+		"de6a7b00deadc788eb6b6c8d20c0ae96c2f2019078fa604fee5b87d6e989ad7b",
+		"bcab477be20861e01e4a0e295284146a510150d9817763caf1a6f4b422d67042",
+		"2a292df7e32cababbd9de088d1d1abec9fc0440f637ed2fba145094dc14bea08",
+		"f4a9e534fc0d216c44b218fa0c42d99635a0127ee2e53c712f70609649fdff22",
+		"8268436f8c4126196cf64b3c7ddbda90746a378625f9813dd9b8457077256731",
+		"2810e5cbc2cc4d4eece54f61c6f69758e289aa7ab440b3cbeaa21995c2f4232b",
+		// negative x t
+		"3eb858e78f5a7254d8c9731174a94f76755fd3941c0ac93735c07ba14579630e",
+		"a45fdc55c76448c049a1ab33f17023edfb2be3581e9c7aade8a6125215e04220",
+		"d483fe813c6ba647ebbfd3ec41adca1c6130c2beeee9d9bf065c8d151c5f396e",
+		"8a2e1d30050198c65a54483123960ccc38aef6848e1ec8f5f780e8523769ba32",
+		"32888462f8b486c68ad7dd9610be5192bbeaf3b443951ac1a8118419d9fa097b",
+		"227142501b9d4355ccba290404bde41575b037693cef1f438c47f8fbf35d1165",
+		"5c37cc491da847cfeb9281d407efc41e15144c876e0170b499a96a22ed31e01e",
+		"445425117cb8c90edcbc7c1cc0e74f747f2c1efa5630a967c64f287792a48a4b",
+		// y = 0
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	}
+	for _, enc := range bad {
+		if _, err := g.Decode(mustHex(t, enc)); !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Decode(%s) gave %v", enc, err)
+		}
+	}
+
+	// RFC 9496 appendix A.3: the element derivation function on the
+	// SHA-512 digest of each line
+	lines := []string{
+		"Ristretto is traditionally a short shot of espresso coffee",
+		"made with the normal amount of ground coffee but extracted with",
+		"about half the amount of water in the same amount of time",
+		"by using a finer grind.",
+		"This produces a concentrated shot of coffee per volume.",
+		"Just pulling a normal shot short will produce a weaker shot",
+		"and is not a Ristretto as some believe.",
+	}
+	elements := []string{
+		"3066f82a1a747d45120d1740f14358531a8f04bbffe6a819f86dfe50f44a0a46",
+		"f26e5b6f7d362d2d2a94c5d0e7602cb4773c95a2e5c31a64f133189fa76ed61b",
+		"006ccd2a9e6867e6a2c5cea83d3302cc9de128dd2a9a57dd8ee7b9d7ffe02826",
+		"f8f0c87cf237953c5890aec3998169005dae3eca1fbb04548c635953c817f92a",
+		"ae81e7dedf20a497e10c304a765c1767a42d6e06029758d2d7e8ef7cc4c41179",
+		"e2705652ff9f5e44d3e841bf1c251cf7dddb77d140870d1ab2ed64f1a9ce8628",
+		"80bd07262511cdde4863f8a7434cef696750681cb9510eea557088f76d9e5065",
+	}
+	for i, line := range lines {
+		digest := sha512.Sum512([]byte(line))
+		if got := hex.EncodeToString(g.Encode(ristrettoFromUniformBytes(digest[:]))); got != elements[i] {
+			t.Errorf("from_uniform_bytes(SHA-512(%q)) = %s", line, got)
+		}
+	}
+}
+
+func TestMerlinTranscript(t *testing.T) {
+	// the test vectors of the Merlin crate
+	tr := NewMerlinTranscript("test protocol")
+	tr.AppendMessage("some label", []byte("some data"))
+	if got := fmt.Sprintf("%x", tr.ChallengeBytes("challenge", 32)); got != "d5a21972d0d5fe320c0d263fac7fffb8145aa640af6e9bca177c03c7efcf0615" {
+		t.Errorf("simple transcript gave %s", got)
+	}
+
+	tr = NewMerlinTranscript("test protocol")
+	tr.AppendMessage("step1", []byte("some data"))
+	data := bytes.Repeat([]byte{99}, 1024)
+	var challenge []byte
+	for i := 0; i < 32; i++ {
+		challenge = tr.ChallengeBytes("challenge", 32)
+		tr.AppendMessage("bigdata", data)
+		tr.AppendMessage("challengedata", challenge)
+	}
+	if got := fmt.Sprintf("%x", challenge); got != "a8c933f54fae76e3f9bea93648c1308e7dfa2152dd51674ff3ca438351cf003c" {
+		t.Errorf("complex transcript gave %s", got)
+	}
+
+	// challenges are 64 bytes read little endian and reduced mod l
+	a, b := NewMerlinTranscript("scalars"), NewMerlinTranscript("scalars")
+	wide := a.ChallengeBytes("c", 64)
+	want := new(big.Int).Mod(leInt(wide), Ristretto255().Order())
+	if got := b.ChallengeScalar("c"); got.Cmp(want) != 0 {
+		t.Errorf("ChallengeScalar = %x, want %x", got, want)
+	}
+}
+
+func TestDalekRangeProof(t *testing.T) {
+	ec, err := NewDalekParams(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := ec.Group
+	if got := hex.EncodeToString(g.Encode(ec.H)); got != "8c9240b456a9e6dc65c377a1048d745f94a08cdb7f44cbcd7b46f34048871134" {
+		t.Errorf("B_blinding = %s", got)
+	}
+	if !ec.G.Equal(ec.U) || hex.EncodeToString(g.Encode(ec.G)) != "e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76" {
+		t.Error("B is not the ristretto255 base point")
+	}
+
+	// each party's generators are a prefix of its chain
+	agg, err := NewDalekParams(8, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < 4; j++ {
+		if !agg.BPG[8*j].Equal(dalekGenerators("G", j, 1)[0]) || !agg.BPH[8*j+7].Equal(dalekGenerators("H", j, 8)[7]) {
+			t.Errorf("generators of party %d are out of place", j)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		if !agg.BPG[i].Equal(ec.BPG[i]) || !agg.BPH[i].Equal(ec.BPH[i]) {
+			t.Errorf("generator %d of party 0 depends on n", i)
+		}
+	}
+
+	v, gamma := big.NewInt(1037578891), big.NewInt(12345)
+	proof, comms, err := ec.DalekProve(NewMerlinTranscript("doctest example"), []*big.Int{v}, []*big.Int{gamma}, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := g.Add(g.ScalarMult(ec.G, v.Bytes()), g.ScalarMult(ec.H, gamma.Bytes())); !comms[0].Equal(want) {
+		t.Error("commitment is not v B + gamma B_blinding")
+	}
+	encoded := proof.Bytes()
+	if len(encoded) != 672 {
+		t.Errorf("64 bit proof is %d bytes, dalek's are 672", len(encoded))
+	}
+	var rebuilt DalekRangeProof
+	if err := rebuilt.Rebuild(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rebuilt.Bytes(), encoded) {
+		t.Error("proof does not survive Bytes and Rebuild")
+	}
+	if ok, err := ec.DalekVerify(NewMerlinTranscript("doctest example"), &rebuilt, comms, 64); !ok || err != nil {
+		t.Errorf("proof does not verify: %v", err)
+	}
+	if ok, _ := ec.DalekVerify(NewMerlinTranscript("another example"), &rebuilt, comms, 64); ok {
+		t.Error("proof verified on another transcript")
+	}
+	rebuilt.Tx = new(big.Int).Add(rebuilt.Tx, big.NewInt(1))
+	if ok, _ := ec.DalekVerify(NewMerlinTranscript("doctest example"), &rebuilt, comms, 64); ok {
+		t.Error("tampered proof verified")
+	}
+	if _, err := ec.DalekVerify(NewMerlinTranscript("doctest example"), &proof, comms, 32); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("verifying over 32 bits with 64 bit params gave %v", err)
+	}
+
+	values := []*big.Int{big.NewInt(0), big.NewInt(255), big.NewInt(7), big.NewInt(128)}
+	blindings, err := agg.RandVector(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, comms, err = agg.DalekProve(NewMerlinTranscript("aggregate"), values, blindings, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Bytes()) != 32*(9+2*5) {
+		t.Errorf("aggregate proof is %d bytes", len(proof.Bytes()))
+	}
+	if ok, err := agg.DalekVerify(NewMerlinTranscript("aggregate"), &proof, comms, 8); !ok || err != nil {
+		t.Errorf("aggregate proof does not verify: %v", err)
+	}
+	if ok, _ := agg.DalekVerify(NewMerlinTranscript("aggregate"), &proof, []ECPoint{comms[1], comms[0], comms[2], comms[3]}, 8); ok {
+		t.Error("aggregate proof verified against swapped commitments")
+	}
+	values[1] = big.NewInt(256)
+	if _, _, err := agg.DalekProve(NewMerlinTranscript("aggregate"), values, blindings, 8); !errors.Is(err, ErrValueOutOfRange) {
+		t.Errorf("proving 256 in 8 bits gave %v", err)
+	}
+
+	if _, _, err := EC.DalekProve(NewMerlinTranscript("secp256k1"), []*big.Int{v}, []*big.Int{gamma}, 64); !errors.Is(err, ErrUnsupportedGroup) {
+		t.Errorf("proving over secp256k1 gave %v", err)
+	}
+	if _, err := NewDalekParams(128, 1); !errors.Is(err, ErrUnsupportedBitLength) {
+		t.Errorf("NewDalekParams(128, 1) gave %v", err)
+	}
+	for _, n := range []int{671, 640, 32 * 75} {
+		if err := rebuilt.Rebuild(make([]byte, n)); !errors.Is(err, ErrMalformedProof) {
+			t.Errorf("Rebuild of %d bytes gave %v", n, err)
+		}
+	}
+	unreduced := append([]byte{}, encoded...)
+	copy(unreduced[4*32:], bytes.Repeat([]byte{0xff}, 32))
+	if err := rebuilt.Rebuild(unreduced); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("Rebuild with an unreduced scalar gave %v", err)
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
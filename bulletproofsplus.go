@@ -0,0 +1,706 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/decred/base58"
+	"github.com/golang/protobuf/proto"
+	"github.com/peterdouglas/bp-go/pb"
+)
+
+/*
+Bulletproofs+
+
+The range proofs of Chung, Han, Ju, Kim and Seo (https://eprint.iacr.org/2020/735).
+They prove the same statement as RangeProof and MultiRangeProof about the same
+commitments V = vG + gammaH, with the same BPG and BPH, so a node can verify
+both kinds side by side. They replace the inner product argument with a
+weighted one,
+
+	<a, b>_y = sum_i a_i b_i y^(i+1)
+
+which lets the single commitment A to aL and aR = aL - 1 take the place of A,
+S, T1, T2, tau_x, t hat and mu. A proof is one point and two scalars
+smaller than a RangeProof over the same bits.
+
+With N = nm bits and d_i = z^(2(j+1)) 2^(i mod n) for the value j that bit i
+belongs to, the verifier works out
+
+	Â = A - z <1, BPG> + <d o y^(N-i) + z, BPH> + zeta G + sum_j z^(2(j+1)) y^(N+1) V_j
+	zeta = (z - z^2) sum_(i=1..N) y^i - z y^(N+1) (2^n - 1) sum_j z^(2(j+1))
+
+which commits to aL - z and aR + d o y^(N-i) + z, with their weighted inner
+product on G exactly when every value is in range, under a blinding factor
+only the prover knows. The weighted inner product argument proves that.
+*/
+
+// WeightedInnerProdArg - the weighted inner product argument that ends a
+// Bulletproofs+ proof
+type WeightedInnerProdArg struct {
+	L []ECPoint // one per round, in the order they were sent
+	R []ECPoint
+
+	// A, B, r', s' and delta' of the last round
+	A  ECPoint
+	B  ECPoint
+	R1 *big.Int
+	S1 *big.Int
+	D1 *big.Int
+}
+
+// RangeProofPlus - a Bulletproofs+ proof that the value in Comm fits in Bits bits
+type RangeProofPlus struct {
+	Comm Commitment
+	Bits int
+	A    ECPoint
+	WIP  WeightedInnerProdArg
+}
+
+// MultiRangeProofPlus - a Bulletproofs+ proof that every value in a list of
+// commitments fits in Bits bits
+type MultiRangeProofPlus struct {
+	Bits int
+	A    ECPoint
+	WIP  WeightedInnerProdArg
+}
+
+// check validates the shape of an argument over n generators and that its
+// points are in g
+func (w *WeightedInnerProdArg) check(g Group, n int) error {
+	if w.R1 == nil || w.S1 == nil || w.D1 == nil {
+		return fmt.Errorf("%w: weighted inner product argument is missing r', s' or delta'", ErrMalformedProof)
+	}
+	if len(w.L) != len(w.R) || n != 1<<uint(len(w.L)) {
+		return fmt.Errorf("%w: weighted inner product argument has %d L and %d R values for %d generators",
+			ErrMalformedProof, len(w.L), len(w.R), n)
+	}
+	if err := checkPoints(g, w.A, w.B); err != nil {
+		return err
+	}
+	if err := checkPoints(g, w.L...); err != nil {
+		return err
+	}
+	return checkPoints(g, w.R...)
+}
+
+func (rp *RangeProofPlus) multi() *MultiRangeProofPlus {
+	return &MultiRangeProofPlus{Bits: rp.Bits, A: rp.A, WIP: rp.WIP}
+}
+
+/*
+mrpPlusProve proves that each of values, committed to under gammas, fits in n
+bits. Like mrpProve it pads the values to a power of two and everything that
+depends on a secret is a scalar that goes through the constant time code.
+*/
+func (ec *CryptoParams) mrpPlusProve(values, gammas []*big.Int, n int) (MultiRangeProofPlus, []ECPoint, error) {
+	proof := MultiRangeProofPlus{Bits: n}
+	if len(gammas) != len(values) {
+		return proof, nil, lengthError("mrpPlusProve", len(values), len(gammas))
+	}
+	m := padCount(len(values))
+	size, err := ec.proofSize(n, m)
+	if err != nil {
+		return proof, nil, err
+	}
+
+	comms, _, gs, aL, aR, err := ec.rangeProofBits(values, gammas, n, m)
+	if err != nil {
+		return proof, nil, err
+	}
+
+	alpha, err := ec.fr.rand()
+	if err != nil {
+		return proof, nil, err
+	}
+	proof.A = ec.ctCommit(nil, &alpha, aL, aR)
+
+	t := ec.newRangeTranscript("bulletproofs+ range proof", n, comms)
+	t.AppendPoint("A", proof.A)
+	sy := ec.fr.fromBig(t.ChallengeScalar("y"))
+	sz := ec.fr.fromBig(t.ChallengeScalar("z"))
+
+	// aL - z, aR + d o y^(N-i) + z and alpha + sum_j z^(2(j+1)) y^(N+1) gamma_j
+	PowerOfCY := scalarPowers(size+2, &sy)
+	two := ec.fr.fromBig(big.NewInt(2))
+	PowerOfTwos := scalarPowers(n, &two)
+	var z2, zj, tmp scalar
+	z2.mul(&sz, &sz)
+	zj = z2
+	for j := 0; j < m; j++ {
+		for i := 0; i < n; i++ {
+			k := j*n + i
+			aL[k].sub(&aL[k], &sz)
+			tmp.mul(&zj, &PowerOfTwos[i])
+			tmp.mul(&tmp, &PowerOfCY[size-k])
+			aR[k].add(&aR[k], &tmp)
+			aR[k].add(&aR[k], &sz)
+		}
+		tmp.mul(&zj, &PowerOfCY[size+1])
+		tmp.mul(&tmp, &gs[j])
+		alpha.add(&alpha, &tmp)
+		zj.mul(&zj, &z2)
+	}
+
+	gens := ec.ctGens(ec.BPG[:size], ec.BPH[:size], ec.G, ec.H)
+	proof.WIP, err = ec.wipProve(t, gens, aL, aR, alpha, PowerOfCY)
+	if err != nil {
+		return proof, nil, err
+	}
+
+	return proof, comms[:len(values)], nil
+}
+
+/*
+wipProve runs the weighted inner product argument for
+
+	P = <a, G> + <b, H> + <a, b>_y ec.G + alpha ec.H
+
+given the powers of y from y^0 up to at least y^(len(a)/2). gens holds G and
+H with ec.G and ec.H as its extra points, and its generators, a and b are
+overwritten.
+
+Each round halves the vectors with a challenge e:
+
+	L = <y^-n' a_lo, G_hi> + <b_hi, H_lo> + cL ec.G + dL ec.H
+	R = <y^n' a_hi, G_lo> + <b_lo, H_hi> + cR ec.G + dR ec.H
+	a' = e a_lo + y^n' e^-1 a_hi, b' = e^-1 b_lo + e b_hi
+	G' = e^-1 G_lo + e y^-n' G_hi, H' = e H_lo + e^-1 H_hi
+
+where cL = <a_lo, b_hi>_y and cR = <y^n' a_hi, b_lo>_y, and alpha picks up
+e^2 dL + e^-2 dR. Once a single element is left the prover shows it knows
+it with a Schnorr style proof of A = r G + s H + (r y b + s y a) ec.G +
+delta ec.H and B = r y s ec.G + eta ec.H. The generators are kept as a
+public factor times a point, as in innerProductProveSub.
+*/
+func (ec *CryptoParams) wipProve(t *Transcript, gens ctGens, a, b []scalar, alpha scalar, PowerOfCY []scalar) (WeightedInnerProdArg, error) {
+	wip := WeightedInnerProdArg{}
+	n := len(a)
+
+	gs := make([]scalar, n)
+	hs := make([]scalar, n)
+	for i := range gs {
+		gs[i] = ec.fr.one()
+		hs[i] = ec.fr.one()
+	}
+
+	// scratch space for the scalars of L and R, reused every round
+	sg := make([]scalar, n/2)
+	sh := make([]scalar, n/2)
+	var tmp scalar
+	for ; n > 1; n /= 2 {
+		nprime := n / 2
+		yn := PowerOfCY[nprime]
+		var yninv scalar
+		yninv.inverse(&yn)
+
+		dL, err := ec.fr.rand()
+		if err != nil {
+			return wip, err
+		}
+		dR, err := ec.fr.rand()
+		if err != nil {
+			return wip, err
+		}
+
+		var cL, cR scalar
+		for i := 0; i < nprime; i++ {
+			tmp.mul(&a[i], &b[nprime+i])
+			tmp.mul(&tmp, &PowerOfCY[i+1])
+			cL.add(&cL, &tmp)
+			tmp.mul(&a[nprime+i], &b[i])
+			tmp.mul(&tmp, &PowerOfCY[i+1])
+			cR.add(&cR, &tmp)
+		}
+		cR.mul(&cR, &yn)
+
+		for i := 0; i < nprime; i++ {
+			tmp.mul(&a[i], &yninv)
+			sg[i].mul(&tmp, &gs[nprime+i])
+			sh[i].mul(&b[nprime+i], &hs[i])
+		}
+		L := gens.commit(nprime, sg[:nprime], 0, sh[:nprime], []scalar{cL, dL})
+
+		for i := 0; i < nprime; i++ {
+			tmp.mul(&a[nprime+i], &yn)
+			sg[i].mul(&tmp, &gs[i])
+			sh[i].mul(&b[i], &hs[nprime+i])
+		}
+		R := gens.commit(0, sg[:nprime], nprime, sh[:nprime], []scalar{cR, dR})
+
+		wip.L = append(wip.L, L)
+		wip.R = append(wip.R, R)
+		t.AppendPoint("L", L)
+		t.AppendPoint("R", R)
+		e := t.ChallengeScalar("e")
+		se := ec.fr.fromBig(e)
+		seinv := ec.fr.fromBig(new(big.Int).ModInverse(e, ec.N))
+		var se2, seinv2 scalar
+		se2.mul(&se, &se)
+		seinv2.mul(&seinv, &seinv)
+
+		for i := 0; i < nprime; i++ {
+			a[i].mul(&a[i], &se)
+			tmp.mul(&a[nprime+i], &yn)
+			tmp.mul(&tmp, &seinv)
+			a[i].add(&a[i], &tmp)
+			b[i].mul(&b[i], &seinv)
+			tmp.mul(&b[nprime+i], &se)
+			b[i].add(&b[i], &tmp)
+		}
+
+		tmp.mul(&dL, &se2)
+		alpha.add(&alpha, &tmp)
+		tmp.mul(&dR, &seinv2)
+		alpha.add(&alpha, &tmp)
+
+		tmp.mul(&se2, &yninv)
+		gens.fold(n, gs, hs, &seinv, &tmp, &se, &seinv2)
+	}
+
+	rs, err := ec.fr.rands(4)
+	if err != nil {
+		return wip, err
+	}
+	r, s, delta, eta := rs[0], rs[1], rs[2], rs[3]
+	y := PowerOfCY[1]
+
+	// A = r G + s H + (r y b + s y a) ec.G + delta ec.H
+	var c, tmp2 scalar
+	tmp.mul(&r, &b[0])
+	tmp2.mul(&s, &a[0])
+	c.add(&tmp, &tmp2)
+	c.mul(&c, &y)
+	tmp.mul(&r, &gs[0])
+	tmp2.mul(&s, &hs[0])
+	wip.A = gens.commit(0, []scalar{tmp}, 0, []scalar{tmp2}, []scalar{c, delta})
+
+	// B = r y s ec.G + eta ec.H
+	c.mul(&r, &s)
+	c.mul(&c, &y)
+	wip.B = gens.commit(0, nil, 0, nil, []scalar{c, eta})
+
+	t.AppendPoint("A'", wip.A)
+	t.AppendPoint("B", wip.B)
+	se := ec.fr.fromBig(t.ChallengeScalar("e"))
+
+	// r' = r + a e, s' = s + b e, delta' = eta + delta e + alpha e^2
+	tmp.mul(&a[0], &se)
+	r.add(&r, &tmp)
+	tmp.mul(&b[0], &se)
+	s.add(&s, &tmp)
+	tmp.mul(&alpha, &se)
+	tmp.add(&tmp, &delta)
+	tmp.mul(&tmp, &se)
+	eta.add(&eta, &tmp)
+
+	wip.R1, wip.S1, wip.D1 = r.big(), s.big(), eta.big()
+	return wip, nil
+}
+
+/*
+mrpPlusVerify checks proof against the padded commitments comms with a single
+multi-exponentiation. Substituting Â into the check the argument ends with
+
+	e^2 Â + e^2 sum_t (e_t^2 L_t + e_t^-2 R_t) + e A' + B
+		= r' e G' + s' e H' + r' s' y ec.G + delta' ec.H
+
+where G' and H' are the folded generators, s_i y^-i BPG_i and s_(N-1-i) BPH_i
+summed over i, with s_i the product over the rounds of e_t or e_t^-1 as the
+bit of i that round reads is set or not.
+*/
+func (ec *CryptoParams) mrpPlusVerify(proof *MultiRangeProofPlus, comms []ECPoint) (bool, error) {
+	if proof == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	if err := checkPoints(ec.group(), comms...); err != nil {
+		return false, err
+	}
+	n := proof.Bits
+	comms = padCommitments(comms)
+	m := len(comms)
+	size, err := ec.proofSize(n, m)
+	if err != nil {
+		return false, err
+	}
+	if err := checkPoints(ec.group(), proof.A); err != nil {
+		return false, err
+	}
+	wip := &proof.WIP
+	if err := wip.check(ec.group(), size); err != nil {
+		return false, err
+	}
+
+	t := ec.newRangeTranscript("bulletproofs+ range proof", n, comms)
+	t.AppendPoint("A", proof.A)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+
+	// the challenge of round t is stored at k-1-t, the bit of i it reads
+	k := len(wip.L)
+	challenges := make([]*big.Int, k)
+	for r := 0; r < k; r++ {
+		t.AppendPoint("L", wip.L[r])
+		t.AppendPoint("R", wip.R[r])
+		challenges[k-1-r] = t.ChallengeScalar("e")
+	}
+	t.AppendPoint("A'", wip.A)
+	t.AppendPoint("B", wip.B)
+	ce := t.ChallengeScalar("e")
+
+	mul := func(a ...*big.Int) *big.Int {
+		res := big.NewInt(1)
+		for _, v := range a {
+			res.Mod(res.Mul(res, v), ec.N)
+		}
+		return res
+	}
+	neg := func(a *big.Int) *big.Int {
+		return new(big.Int).Mod(new(big.Int).Neg(a), ec.N)
+	}
+
+	inverses := ec.batchInvert(append(challenges, cy))
+	yinv := inverses[k]
+	inverses = inverses[:k]
+	squares := ec.vectorHadamard(challenges, challenges)
+	invSquares := ec.vectorHadamard(inverses, inverses)
+	sScalars, invsScalars := ec.ipaSVector(challenges, inverses, squares)
+
+	PowersOfY := ec.PowerVector(size+2, cy)
+	PowersOfYInv := ec.PowerVector(size, yinv)
+	PowerOfTwos := ec.PowerVector(n, big.NewInt(2))
+	e2 := mul(ce, ce)
+	z2 := mul(cz, cz)
+	yN1 := PowersOfY[size+1]
+
+	points := make([]ECPoint, 0, 2*size+2*k+m+5)
+	scalars := make([]*big.Int, 0, 2*size+2*k+m+5)
+	points = append(points, proof.A, wip.A, wip.B)
+	scalars = append(scalars, e2, ce, big.NewInt(1))
+	for r := 0; r < k; r++ {
+		points = append(points, wip.L[r], wip.R[r])
+		scalars = append(scalars, mul(e2, squares[k-1-r]), mul(e2, invSquares[k-1-r]))
+	}
+
+	// z^(2(j+1)) for each value, with their sum for zeta
+	PowersOfZ := make([]*big.Int, m)
+	sumZ := big.NewInt(0)
+	for j := 0; j < m; j++ {
+		if j == 0 {
+			PowersOfZ[j] = z2
+		} else {
+			PowersOfZ[j] = mul(PowersOfZ[j-1], z2)
+		}
+		sumZ.Add(sumZ, PowersOfZ[j])
+		points = append(points, comms[j])
+		scalars = append(scalars, mul(e2, yN1, PowersOfZ[j]))
+	}
+
+	sumY := big.NewInt(0)
+	for i := 1; i <= size; i++ {
+		sumY.Add(sumY, PowersOfY[i])
+	}
+	twoN := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n)), big.NewInt(1))
+	zeta := new(big.Int).Sub(mul(new(big.Int).Sub(cz, z2), sumY), mul(cz, yN1, twoN, sumZ))
+
+	points = append(points, ec.G, ec.H)
+	scalars = append(scalars,
+		new(big.Int).Sub(mul(e2, zeta), mul(wip.R1, wip.S1, cy)),
+		neg(wip.D1))
+
+	negE2Z := neg(mul(e2, cz))
+	r1e := mul(wip.R1, ce)
+	s1e := mul(wip.S1, ce)
+	for i := 0; i < size; i++ {
+		points = append(points, ec.BPG[i])
+		scalars = append(scalars, new(big.Int).Sub(negE2Z, mul(r1e, PowersOfYInv[i], sScalars[i])))
+	}
+	for i := 0; i < size; i++ {
+		d := mul(PowersOfZ[i/n], PowerOfTwos[i%n], PowersOfY[size-i])
+		points = append(points, ec.BPH[i])
+		scalars = append(scalars, new(big.Int).Sub(mul(e2, d.Add(d, cz)), mul(s1e, invsScalars[i])))
+	}
+
+	sum := ec.msm(points, scalars)
+	if sum.X.Sign() != 0 || sum.Y.Sign() != 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+/*
+RPPlusProve - Bulletproofs+ Range Proof Prove
+
+Commits to v with a random blinding factor and proves it fits in ec.V bits.
+*/
+func (ec *CryptoParams) RPPlusProve(v *big.Int) (RangeProofPlus, error) {
+	gamma, err := ec.randScalar()
+	if err != nil {
+		return RangeProofPlus{}, err
+	}
+	return ec.RPPlusProveTrans(gamma, v)
+}
+
+// RPPlusProveTrans - RPPlusProve with the blinding factor gamma, for example
+// one made by Commitment.Generate
+func (ec *CryptoParams) RPPlusProveTrans(gamma *big.Int, v *big.Int) (RangeProofPlus, error) {
+	return ec.RPPlusProveTransBits(gamma, v, ec.V)
+}
+
+// RPPlusProveTransBits - RPPlusProveTrans over n bits instead of ec.V
+func (ec *CryptoParams) RPPlusProveTransBits(gamma *big.Int, v *big.Int, n int) (RangeProofPlus, error) {
+	if err := checkBitLength(n); err != nil {
+		return RangeProofPlus{}, err
+	}
+	mrp, comms, err := ec.mrpPlusProve([]*big.Int{v}, []*big.Int{gamma}, n)
+	if err != nil {
+		return RangeProofPlus{}, err
+	}
+	return RangeProofPlus{Comm: Commitment{Comm: comms[0]}, Bits: mrp.Bits, A: mrp.A, WIP: mrp.WIP}, nil
+}
+
+// RPPlusVerify - checks rp against the commitment it carries
+func (ec *CryptoParams) RPPlusVerify(rp RangeProofPlus) (bool, error) {
+	return ec.RPPlusVerifyTrans(&rp.Comm.Comm, &rp)
+}
+
+// RPPlusVerifyTrans - checks rp against the commitment comm
+func (ec *CryptoParams) RPPlusVerifyTrans(comm *ECPoint, rp *RangeProofPlus) (bool, error) {
+	if comm == nil || rp == nil {
+		return false, fmt.Errorf("%w: missing commitment or proof", ErrMalformedProof)
+	}
+	return ec.mrpPlusVerify(rp.multi(), []ECPoint{*comm})
+}
+
+// RPPlusVerifyBits verifies that rp shows the value committed to in comm is
+// in [0, 2^n)
+func (ec *CryptoParams) RPPlusVerifyBits(comm *ECPoint, rp *RangeProofPlus, n int) (bool, error) {
+	if comm == nil || rp == nil {
+		return false, fmt.Errorf("%w: missing commitment or proof", ErrMalformedProof)
+	}
+	return ec.MRPPlusVerifyBits(rp.multi(), []ECPoint{*comm}, n)
+}
+
+/*
+MRPPlusProve - Bulletproofs+ MultiRangeProof Prove
+
+Commits to each value with a random blinding factor and proves they all fit
+in ec.V / m bits, where m is the number of values padded to a power of two,
+like MRPProve.
+*/
+func (ec *CryptoParams) MRPPlusProve(values []*big.Int) ([]ECPoint, MultiRangeProofPlus, error) {
+	n, err := ec.bitsPerValue(len(values))
+	if err != nil {
+		return nil, MultiRangeProofPlus{}, err
+	}
+	return ec.MRPPlusProveBits(values, n)
+}
+
+// MRPPlusProveBits - MRPPlusProve over n bits per value
+func (ec *CryptoParams) MRPPlusProveBits(values []*big.Int, n int) ([]ECPoint, MultiRangeProofPlus, error) {
+	if err := checkBitLength(n); err != nil {
+		return nil, MultiRangeProofPlus{}, err
+	}
+	gammas, err := ec.RandVector(len(values))
+	if err != nil {
+		return nil, MultiRangeProofPlus{}, err
+	}
+	mrp, comms, err := ec.mrpPlusProve(values, gammas, n)
+	return comms, mrp, err
+}
+
+// MRPPlusProveTrans - MRPPlusProve with the blinding factors derived from
+// sSecret the way MRPProveTrans does
+func (ec *CryptoParams) MRPPlusProveTrans(values []*big.Int, sSecret *big.Int) (MultiRangeProofPlus, []ECPoint, error) {
+	n, err := ec.bitsPerValue(len(values))
+	if err != nil {
+		return MultiRangeProofPlus{}, nil, err
+	}
+	Blinds, err := transBlinds(values, sSecret)
+	if err != nil {
+		return MultiRangeProofPlus{}, nil, err
+	}
+	return ec.mrpPlusProve(values, Blinds, n)
+}
+
+/*
+MRPPlusVerify - Bulletproofs+ MultiRangeProof Verify
+
+Checks mrp against the commitments it was made for. Returns false if the proof
+does not verify, and an error if the proof or the commitments are malformed.
+*/
+func (ec *CryptoParams) MRPPlusVerify(mrp *MultiRangeProofPlus, comms []ECPoint) (bool, error) {
+	return ec.mrpPlusVerify(mrp, comms)
+}
+
+/*
+MRPPlusVerifyBits - Bulletproofs+ MultiRangeProof Verify with an explicit bit
+length
+
+Same as MRPPlusVerify, except the proof must show that every value fits in n
+bits, where n is one of BitLengths.
+*/
+func (ec *CryptoParams) MRPPlusVerifyBits(mrp *MultiRangeProofPlus, comms []ECPoint, n int) (bool, error) {
+	if mrp == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	if err := checkBitLength(n); err != nil {
+		return false, err
+	}
+	if mrp.Bits != n {
+		return false, fmt.Errorf("%w: proof is over %d bits, expected %d", ErrMalformedProof, mrp.Bits, n)
+	}
+	return ec.mrpPlusVerify(mrp, comms)
+}
+
+// pb returns the protobuf form of the argument, reporting missing fields to pw
+func (w *WeightedInnerProdArg) pb(pw *pbWriter) *pb.WeightedInnerProductProof {
+	pbWIP := &pb.WeightedInnerProductProof{
+		A:  pw.point("A", w.A),
+		B:  pw.point("B", w.B),
+		R1: pw.scalar("r'", w.R1),
+		S1: pw.scalar("s'", w.S1),
+		D1: pw.scalar("delta'", w.D1),
+	}
+	if len(w.L) != len(w.R) {
+		pw.fail(fmt.Sprintf("%d L values and %d R values", len(w.L), len(w.R)))
+		return pbWIP
+	}
+	for i := range w.L {
+		pbWIP.L = append(pbWIP.L, pw.point("L", w.L[i]))
+		pbWIP.R = append(pbWIP.R, pw.point("R", w.R[i]))
+	}
+	return pbWIP
+}
+
+// rebuildWIP decodes a protobuf weighted inner product proof
+func rebuildWIP(g Group, pbWIP *pb.WeightedInnerProductProof) (WeightedInnerProdArg, error) {
+	w := WeightedInnerProdArg{}
+	if pbWIP == nil {
+		return w, fmt.Errorf("%w: missing weighted inner product proof", ErrMalformedProof)
+	}
+	if len(pbWIP.L) != len(pbWIP.R) {
+		return w, fmt.Errorf("%w: %d L values and %d R values", ErrMalformedProof, len(pbWIP.L), len(pbWIP.R))
+	}
+
+	w.L = make([]ECPoint, len(pbWIP.L))
+	w.R = make([]ECPoint, len(pbWIP.R))
+	for i := range pbWIP.L {
+		if err := rebuildPoint(g, &w.L[i], pbWIP.L[i]); err != nil {
+			return w, err
+		}
+		if err := rebuildPoint(g, &w.R[i], pbWIP.R[i]); err != nil {
+			return w, err
+		}
+	}
+	if err := rebuildPoint(g, &w.A, pbWIP.A); err != nil {
+		return w, err
+	}
+	if err := rebuildPoint(g, &w.B, pbWIP.B); err != nil {
+		return w, err
+	}
+
+	w.R1 = new(big.Int).SetBytes(pbWIP.R1)
+	w.S1 = new(big.Int).SetBytes(pbWIP.S1)
+	w.D1 = new(big.Int).SetBytes(pbWIP.D1)
+	return w, nil
+}
+
+// serializePlus encodes the parts shared by both kinds of Bulletproofs+ proof,
+// with the points encoded for the group g
+func serializePlus(g Group, bits int, A ECPoint, wip *WeightedInnerProdArg) (string, error) {
+	w := &pbWriter{g: g}
+	pbrp := &pb.RangeProofPlus{
+		A:    w.point("A", A),
+		WIP:  wip.pb(w),
+		Bits: uint32(bits),
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+
+	serial, err := proto.Marshal(pbrp)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(serial), nil
+}
+
+// rebuildPlus decodes what serializePlus encodes
+func rebuildPlus(g Group, encoded string) (MultiRangeProofPlus, error) {
+	mrp := MultiRangeProofPlus{}
+	bRp := base58.Decode(encoded)
+	if len(bRp) == 0 {
+		return mrp, fmt.Errorf("%w: empty or invalid base58", ErrMalformedProof)
+	}
+
+	pbRp := &pb.RangeProofPlus{}
+	if err := proto.Unmarshal(bRp, pbRp); err != nil {
+		return mrp, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+
+	if err := rebuildPoint(g, &mrp.A, pbRp.A); err != nil {
+		return mrp, err
+	}
+	wip, err := rebuildWIP(g, pbRp.WIP)
+	if err != nil {
+		return mrp, err
+	}
+	mrp.WIP = wip
+	mrp.Bits = int(pbRp.Bits)
+	return mrp, nil
+}
+
+// Serialize - encodes the proof as base58 protobuf, without the commitment,
+// with the points encoded for the group of EC
+func (rp *RangeProofPlus) Serialize() (string, error) {
+	return rp.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (rp *RangeProofPlus) SerializeWithParams(ec *CryptoParams) (string, error) {
+	return serializePlus(ec.group(), rp.Bits, rp.A, &rp.WIP)
+}
+
+// Rebuild - decodes a proof made by Serialize, leaving Comm as it was
+func (rp *RangeProofPlus) Rebuild(encodedRP string) error {
+	return rp.RebuildWithParams(&EC, encodedRP)
+}
+
+// RebuildWithParams - decodes a proof made by SerializeWithParams(ec),
+// leaving Comm as it was
+func (rp *RangeProofPlus) RebuildWithParams(ec *CryptoParams, encodedRP string) error {
+	mrp, err := rebuildPlus(ec.group(), encodedRP)
+	if err != nil {
+		return err
+	}
+	rp.Bits, rp.A, rp.WIP = mrp.Bits, mrp.A, mrp.WIP
+	return nil
+}
+
+// Serialize - encodes the proof as base58 protobuf, with the points encoded
+// for the group of EC
+func (mp *MultiRangeProofPlus) Serialize() (string, error) {
+	return mp.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (mp *MultiRangeProofPlus) SerializeWithParams(ec *CryptoParams) (string, error) {
+	return serializePlus(ec.group(), mp.Bits, mp.A, &mp.WIP)
+}
+
+// Rebuild - decodes a proof made by Serialize
+func (mp *MultiRangeProofPlus) Rebuild(encodedMP string) error {
+	return mp.RebuildWithParams(&EC, encodedMP)
+}
+
+// RebuildWithParams - decodes a proof made by SerializeWithParams(ec)
+func (mp *MultiRangeProofPlus) RebuildWithParams(ec *CryptoParams, encodedMP string) error {
+	mrp, err := rebuildPlus(ec.group(), encodedMP)
+	if err != nil {
+		return err
+	}
+	*mp = mrp
+	return nil
+}
@@ -0,0 +1,283 @@
+package bp_go
+
+import "math/big"
+
+/*
+ctPoint - a secp256k1 point for arithmetic on secret scalars
+
+(X, Y, Z) stands for the affine point (X/Z, Y/Z) and (0, 1, 0) is the point at
+infinity. Addition and doubling use the complete formulas of Renes, Costello
+and Batina (eprint 2015/1060, algorithms 7 and 9 for a = 0), which have no
+special cases: adding a point to itself, to its negation or to infinity runs
+exactly the same field operations as any other addition. Together with table
+lookups that read every entry, this gives multiplications whose running time
+does not depend on the scalars.
+
+The jacobianPoint code is faster and is still used wherever every input is
+public, which is everything the verifier does.
+*/
+type ctPoint struct {
+	x, y, z fieldElement
+}
+
+// curveB3 - 3 * b for y^2 = x^3 + 7
+var curveB3 = fieldElement{21, 0, 0, 0}
+
+var ctIdentity = ctPoint{y: fieldOne}
+
+// ctFromJacobian converts a public point
+func ctFromJacobian(p *jacobianPoint) ctPoint {
+	if p.isInfinity() {
+		return ctIdentity
+	}
+	// (X/Z^2, Y/Z^3) = (X Z / Z^3, Y / Z^3)
+	var r ctPoint
+	var z2 fieldElement
+	z2.square(&p.z)
+	r.x.mul(&p.x, &p.z)
+	r.y = p.y
+	r.z.mul(&z2, &p.z)
+	return r
+}
+
+// affine converts p to an ECPoint, with infinity as (0, 0). The result is
+// about to be published, so it may branch on it.
+func (p *ctPoint) affine() ECPoint {
+	if p.z.isZero() {
+		return ECPoint{new(big.Int), new(big.Int)}
+	}
+	var zinv, x, y fieldElement
+	zinv.inverse(&p.z)
+	x.mul(&p.x, &zinv)
+	y.mul(&p.y, &zinv)
+	return ECPoint{x.big(), y.big()}
+}
+
+// add sets r to p + q
+func (r *ctPoint) add(p, q *ctPoint) *ctPoint {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fieldElement
+	t0.mul(&p.x, &q.x)
+	t1.mul(&p.y, &q.y)
+	t2.mul(&p.z, &q.z)
+	t3.add(&p.x, &p.y)
+	t4.add(&q.x, &q.y)
+	t3.mul(&t3, &t4)
+	t4.add(&t0, &t1)
+	t3.sub(&t3, &t4)
+	t4.add(&p.y, &p.z)
+	x3.add(&q.y, &q.z)
+	t4.mul(&t4, &x3)
+	x3.add(&t1, &t2)
+	t4.sub(&t4, &x3)
+	x3.add(&p.x, &p.z)
+	y3.add(&q.x, &q.z)
+	x3.mul(&x3, &y3)
+	y3.add(&t0, &t2)
+	y3.sub(&x3, &y3)
+	x3.add(&t0, &t0)
+	t0.add(&x3, &t0)
+	t2.mul(&curveB3, &t2)
+	z3.add(&t1, &t2)
+	t1.sub(&t1, &t2)
+	y3.mul(&curveB3, &y3)
+	x3.mul(&t4, &y3)
+	t2.mul(&t3, &t1)
+	x3.sub(&t2, &x3)
+	y3.mul(&y3, &t0)
+	t1.mul(&t1, &z3)
+	y3.add(&t1, &y3)
+	t0.mul(&t0, &t3)
+	z3.mul(&z3, &t4)
+	z3.add(&z3, &t0)
+	r.x, r.y, r.z = x3, y3, z3
+	return r
+}
+
+// double sets r to 2p
+func (r *ctPoint) double(p *ctPoint) *ctPoint {
+	var t0, t1, t2, x3, y3, z3 fieldElement
+	t0.square(&p.y)
+	z3.add(&t0, &t0)
+	z3.add(&z3, &z3)
+	z3.add(&z3, &z3)
+	t1.mul(&p.y, &p.z)
+	t2.square(&p.z)
+	t2.mul(&curveB3, &t2)
+	x3.mul(&t2, &z3)
+	y3.add(&t0, &t2)
+	z3.mul(&t1, &z3)
+	t1.add(&t2, &t2)
+	t2.add(&t1, &t2)
+	t0.sub(&t0, &t2)
+	y3.mul(&t0, &y3)
+	y3.add(&x3, &y3)
+	t1.mul(&p.x, &p.y)
+	x3.mul(&t0, &t1)
+	x3.add(&x3, &x3)
+	r.x, r.y, r.z = x3, y3, z3
+	return r
+}
+
+// cmov sets r to p if bit is 1 and leaves it alone if bit is 0
+func (r *ctPoint) cmov(p *ctPoint, bit uint64) {
+	mask := -bit
+	for i := 0; i < 4; i++ {
+		r.x[i] = (p.x[i] & mask) | (r.x[i] &^ mask)
+		r.y[i] = (p.y[i] & mask) | (r.y[i] &^ mask)
+		r.z[i] = (p.z[i] & mask) | (r.z[i] &^ mask)
+	}
+}
+
+// ctEqual returns 1 if a == b and 0 otherwise
+func ctEqual(a, b uint64) uint64 {
+	x := a ^ b
+	return ((x | -x) >> 63) ^ 1
+}
+
+// ctLookup returns table[d], reading every entry of the table
+func ctLookup(table *[16]ctPoint, d uint) ctPoint {
+	var r ctPoint
+	for i := range table {
+		r.cmov(&table[i], ctEqual(uint64(i), uint64(d)))
+	}
+	return r
+}
+
+// ctCombLookup returns d times the base of a row of a comb table, or infinity
+// for d = 0, reading every entry of the row
+func ctCombLookup(row []jacobianPoint, d uint) ctPoint {
+	r := ctIdentity
+	for i := range row {
+		p := ctPoint{row[i].x, row[i].y, fieldOne}
+		r.cmov(&p, ctEqual(uint64(i+1), uint64(d)))
+	}
+	return r
+}
+
+/*
+ctMultiScalarMul returns the sum of scalars[i] * points[i] in time that
+depends only on the number of points.
+
+It is Straus' method with 4 bit windows, like straus, except that digit 0 is
+looked up and added like any other: the table of each point starts with
+infinity, and the complete formulas make adding it a full addition.
+*/
+func ctMultiScalarMul(points []jacobianPoint, scalars []scalar) ctPoint {
+	return ctStraus(points, scalars, make([][16]ctPoint, len(points)), make([]scalarLimbs, len(points)))
+}
+
+// ctStraus is ctMultiScalarMul with scratch space for the tables and limbs of
+// at least len(points) points, so callers that run it repeatedly can reuse it
+func ctStraus(points []jacobianPoint, scalars []scalar, tables [][16]ctPoint, limbs []scalarLimbs) ctPoint {
+	for i := range points {
+		tables[i][0] = ctIdentity
+		tables[i][1] = ctFromJacobian(&points[i])
+		for d := 2; d < 16; d++ {
+			tables[i][d].add(&tables[i][d-1], &tables[i][1])
+		}
+		limbs[i] = scalars[i].limbs()
+	}
+
+	acc := ctIdentity
+	for pos := 252; pos >= 0; pos -= 4 {
+		for k := 0; k < 4; k++ {
+			acc.double(&acc)
+		}
+		for i := range points {
+			p := ctLookup(&tables[i], limbs[i].window(uint(pos), 4))
+			acc.add(&acc, &p)
+		}
+	}
+	return acc
+}
+
+// jacobianGens - ctGens for secp256k1
+type jacobianGens struct {
+	G, H, X []jacobianPoint
+
+	// scratch space for commit, sized for every point at once
+	points  []jacobianPoint
+	scalars []scalar
+	tables  [][16]ctPoint
+	limbs   []scalarLimbs
+}
+
+func newJacobianGens(G, H, X []ECPoint) *jacobianGens {
+	n := len(G) + len(H) + len(X)
+	return &jacobianGens{
+		G:       toJacobianSlice(G),
+		H:       toJacobianSlice(H),
+		X:       toJacobianSlice(X),
+		points:  make([]jacobianPoint, 0, n),
+		scalars: make([]scalar, 0, n),
+		tables:  make([][16]ctPoint, n),
+		limbs:   make([]scalarLimbs, n),
+	}
+}
+
+func (jg *jacobianGens) commit(g int, sg []scalar, h int, sh []scalar, sx []scalar) ECPoint {
+	jg.points = append(append(append(jg.points[:0], jg.G[g:g+len(sg)]...), jg.H[h:h+len(sh)]...), jg.X[:len(sx)]...)
+	jg.scalars = append(append(append(jg.scalars[:0], sg...), sh...), sx...)
+	r := ctStraus(jg.points, jg.scalars, jg.tables, jg.limbs)
+	return r.affine()
+}
+
+func (jg *jacobianGens) fold(n int, gs, hs []scalar, gOuter, gInner, hOuter, hInner *scalar) {
+	foldScaled(jg.G[:n], gs[:n], gOuter, gInner)
+	foldScaled(jg.H[:n], hs[:n], hOuter, hInner)
+}
+
+/*
+ctCommit returns g*G + h*H + <a, BPG> + <b, BPH> in time that depends only on
+which of g and h are given and on the lengths of a and b.
+
+G and H use their comb tables with a lookup that reads the whole row, and the
+vector generators go through ctMultiScalarMul. Groups without proverOps add
+up one Group.ScalarMult per generator instead.
+*/
+func (ec *CryptoParams) ctCommit(g, h *scalar, a, b []scalar) ECPoint {
+	return ec.ctCommitAt(g, h, 0, a, b)
+}
+
+// ctCommitAt is ctCommit with the vector generators starting at BPG[offset]
+// and BPH[offset]
+func (ec *CryptoParams) ctCommitAt(g, h *scalar, offset int, a, b []scalar) ECPoint {
+	return ec.ops().ctCommitAt(ec, g, h, offset, a, b)
+}
+
+func (secp256k1Group) ctCommitAt(ec *CryptoParams, g, h *scalar, offset int, a, b []scalar) ECPoint {
+	acc := ctIdentity
+
+	t := ec.fixedTables()
+	var points []jacobianPoint
+	var scalars []scalar
+	for i, s := range []*scalar{g, h} {
+		if s == nil {
+			continue
+		}
+		if t == nil {
+			points = append(points, toJacobian([]ECPoint{ec.G, ec.H}[i]))
+			scalars = append(scalars, *s)
+			continue
+		}
+		table := [][]jacobianPoint{t.g, t.h}[i]
+		sl := s.limbs()
+		for k := 0; k < combWindows; k++ {
+			p := ctCombLookup(table[k*15:(k+1)*15], sl.window(uint(k*combWindow), combWindow))
+			acc.add(&acc, &p)
+		}
+	}
+
+	for i := range a {
+		points = append(points, toJacobian(ec.BPG[offset+i]))
+	}
+	for i := range b {
+		points = append(points, toJacobian(ec.BPH[offset+i]))
+	}
+	scalars = append(append(scalars, a...), b...)
+	if len(points) > 0 {
+		sum := ctMultiScalarMul(points, scalars)
+		acc.add(&acc, &sum)
+	}
+	return acc.affine()
+}
@@ -0,0 +1,411 @@
+package bp_go
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/gtank/ristretto255"
+	"golang.org/x/crypto/sha3"
+)
+
+/*
+Range proofs in the format of the dalek bulletproofs crate
+(https://github.com/dalek-cryptography/bulletproofs), version 1 and up.
+
+dalek runs the aggregate range proof of this package over ristretto255 with
+its own generators, transcript and byte format:
+
+  - the Pedersen generators are B, the ristretto255 base point, for values
+    and B_blinding, from_uniform_bytes(SHA3-512(B)), for blinding factors
+  - party j of m has its own n vector generators, read 64 bytes at a time
+    out of SHAKE256("GeneratorsChain" || "G" || LE32(j)) and
+    SHAKE256("GeneratorsChain" || "H" || LE32(j)), and BPG and BPH are the
+    parties' generators one after the other
+  - the inner product argument is over w B, where w is its challenge
+  - the proof runs on a MerlinTranscript with dalek's labels
+  - a proof is A, S, T_1, T_2, t_x, t_x_blinding, e_blinding, then L and R
+    of every round from the first on, then a and b, each in 32 bytes
+
+So the arithmetic is the same as mrpProve's, with NewDalekParams supplying
+the generators and DalekProve and DalekVerify the transcript.
+
+None of this has been checked against proofs made by the crate, since none
+are available here: the tests only round-trip proofs this package made.
+*/
+
+// DalekBitLengths - the bit lengths dalek proves values over
+var DalekBitLengths = []int{8, 16, 32, 64}
+
+// NewDalekParams returns ristretto255 params with the generators the dalek
+// bulletproofs crate uses for proofs of m values of n bits each. n must be
+// one of DalekBitLengths and m a power of two.
+func NewDalekParams(n, m int) (CryptoParams, error) {
+	if err := checkDalekBitLength(n); err != nil {
+		return CryptoParams{}, err
+	}
+	if !isPowerOfTwo(m) {
+		return CryptoParams{}, lengthError("NewDalekParams", n, m)
+	}
+
+	g := Ristretto255()
+	B := ristrettoPoint(ristretto255.NewElement().Base())
+	blinding := sha3.Sum512(g.Encode(B))
+	ec := CryptoParams{
+		Group: g,
+		BPG:   make([]ECPoint, 0, n*m),
+		BPH:   make([]ECPoint, 0, n*m),
+		N:     g.Order(),
+		U:     B,
+		V:     n * m,
+		G:     B,
+		H:     ristrettoFromUniformBytes(blinding[:]),
+		fr:    newScalarField(g.Order())}
+	for j := 0; j < m; j++ {
+		ec.BPG = append(ec.BPG, dalekGenerators("G", j, n)...)
+		ec.BPH = append(ec.BPH, dalekGenerators("H", j, n)...)
+	}
+	ec.tables = ec.ops().newTables()
+	ec.id = ec.ID()
+	return ec, nil
+}
+
+// dalekGenerators returns the first n points of dalek's GeneratorsChain for
+// the generators named by label of party j
+func dalekGenerators(label string, j, n int) []ECPoint {
+	var idx [4]byte
+	binary.LittleEndian.PutUint32(idx[:], uint32(j))
+	shake := sha3.NewShake256()
+	shake.Write([]byte("GeneratorsChain"))
+	shake.Write([]byte(label))
+	shake.Write(idx[:])
+
+	points := make([]ECPoint, n)
+	var uniform [64]byte
+	for i := range points {
+		shake.Read(uniform[:])
+		points[i] = ristrettoFromUniformBytes(uniform[:])
+	}
+	return points
+}
+
+func checkDalekBitLength(n int) error {
+	for _, b := range DalekBitLengths {
+		if n == b {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d bits, expected one of %v", ErrUnsupportedBitLength, n, DalekBitLengths)
+}
+
+// checkDalekParams returns an error unless ec is over ristretto255 and has
+// generators for exactly m values of n bits each
+func (ec *CryptoParams) checkDalekParams(fn string, n, m int) error {
+	if _, ok := ec.group().(ristrettoGroup); !ok {
+		return fmt.Errorf("%w: %s needs ristretto255 params, see NewDalekParams", ErrUnsupportedGroup, fn)
+	}
+	if err := checkDalekBitLength(n); err != nil {
+		return err
+	}
+	if !isPowerOfTwo(m) || len(ec.BPG) != n*m || len(ec.BPH) != n*m {
+		return lengthError(fn, n, m, len(ec.BPG), len(ec.BPH))
+	}
+	return nil
+}
+
+/*
+DalekRangeProof - an aggregate range proof in the format of the dalek
+bulletproofs crate, see NewDalekParams
+
+Tx is t(x), TxBlinding its blinding factor tau_x and EBlinding the blinding
+factor mu of A + x S. IPP is stored like every InnerProdArg of the package,
+with the first round last.
+
+The byte format of Bytes and Rebuild follows the crate's source but is
+unverified against proofs the crate serialised.
+*/
+type DalekRangeProof struct {
+	A, S, T1, T2              ECPoint
+	Tx, TxBlinding, EBlinding *big.Int
+	IPP                       InnerProdArg
+}
+
+// dalekRangeTranscript appends the statement of a proof of n bits for each
+// of comms to t, as both dalek's prover and verifier start out
+func dalekRangeTranscript(t *MerlinTranscript, n int, comms []ECPoint) {
+	t.AppendMessage("dom-sep", []byte("rangeproof v1"))
+	t.AppendUint64("n", uint64(n))
+	t.AppendUint64("m", uint64(len(comms)))
+	for _, V := range comms {
+		t.AppendPoint("V", V)
+	}
+}
+
+/*
+DalekProve - proves that each of values, committed to under blindings, fits
+in n bits, the way the dalek crate's RangeProof::prove_multiple does
+
+t is the caller's transcript, which the verifier has to start in the same
+state. ec must come from NewDalekParams(n, len(values)). Returns the proof
+and the commitment v B + gamma B_blinding to each value.
+
+Unverified: no proof it makes has been checked by the crate's verifier.
+*/
+func (ec *CryptoParams) DalekProve(t *MerlinTranscript, values, blindings []*big.Int, n int) (DalekRangeProof, []ECPoint, error) {
+	m := len(values)
+	if err := ec.checkDalekParams("DalekProve", n, m); err != nil {
+		return DalekRangeProof{}, nil, err
+	}
+	if len(blindings) != m {
+		return DalekRangeProof{}, nil, lengthError("DalekProve", m, len(blindings))
+	}
+	size := n * m
+
+	comms, vs, gs, aL, aR, err := ec.rangeProofBits(values, blindings, n, m)
+	if err != nil {
+		return DalekRangeProof{}, nil, err
+	}
+	dalekRangeTranscript(t, n, comms)
+
+	secrets, err := ec.fr.rands(4)
+	if err != nil {
+		return DalekRangeProof{}, nil, err
+	}
+	alpha, rho, tau1, tau2 := secrets[0], secrets[1], secrets[2], secrets[3]
+	sL, err := ec.fr.rands(size)
+	if err != nil {
+		return DalekRangeProof{}, nil, err
+	}
+	sR, err := ec.fr.rands(size)
+	if err != nil {
+		return DalekRangeProof{}, nil, err
+	}
+
+	var proof DalekRangeProof
+	proof.A = ec.ctCommit(nil, &alpha, aL, aR)
+	proof.S = ec.ctCommit(nil, &rho, sL, sR)
+	t.AppendPoint("A", proof.A)
+	t.AppendPoint("S", proof.S)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+	polys := ec.rangeProofPolys(vs, aL, aR, sL, sR, cy, cz, n, m)
+
+	proof.T1 = ec.ctCommit(&polys.t1, &tau1, nil, nil)
+	proof.T2 = ec.ctCommit(&polys.t2, &tau2, nil, nil)
+	t.AppendPoint("T_1", proof.T1)
+	t.AppendPoint("T_2", proof.T2)
+	sx := ec.fr.fromBig(t.ChallengeScalar("x"))
+
+	left, right, tx, err := polys.eval(&sx)
+	if err != nil {
+		return DalekRangeProof{}, nil, err
+	}
+
+	// tau_x = (tau2 x + tau1) x + sum_j z^(2+j) gamma_j, mu = alpha + rho x
+	var taux, mu scalar
+	taux.mul(&tau2, &sx)
+	taux.add(&taux, &tau1)
+	taux.mul(&taux, &sx)
+	zg := scalarInnerProduct(polys.zPowers, gs)
+	taux.add(&taux, &zg)
+	mu.mul(&rho, &sx)
+	mu.add(&mu, &alpha)
+	proof.Tx, proof.TxBlinding, proof.EBlinding = tx.big(), taux.big(), mu.big()
+
+	t.AppendScalar("t_x", proof.Tx)
+	t.AppendScalar("t_x_blinding", proof.TxBlinding)
+	t.AppendScalar("e_blinding", proof.EBlinding)
+	w := t.ChallengeScalar("w")
+
+	t.AppendMessage("dom-sep", []byte("ipp v1"))
+	t.AppendUint64("n", uint64(size))
+	rounds := bits.Len(uint(size)) - 1
+	proof.IPP = ec.innerProductProveSub(t,
+		InnerProdArg{make([]ECPoint, rounds), make([]ECPoint, rounds), big.NewInt(0), big.NewInt(0)},
+		ec.ctGens(ec.BPG, ec.BPH, ec.mult(ec.U, w)),
+		ec.PowerVector(size, new(big.Int).ModInverse(cy, ec.N)), left, right)
+
+	return proof, comms, nil
+}
+
+/*
+DalekVerify - checks that proof shows every value committed to in comms fits
+in n bits, the way the dalek crate's RangeProof::verify_multiple does
+
+t must be in the same state the prover's was in, and ec must come from
+NewDalekParams(n, len(comms)). Like dalek, it checks everything with one
+multi-scalar multiplication, the range check weighted by a random c:
+
+	A + x S + c x T_1 + c x^2 T_2 + sum_j (u_j^2 L_j + u_j^-2 R_j)
+	  - (e_blinding + c t_x_blinding) B_blinding
+	  + (w (t_x - ab) + c (delta(y, z) - t_x)) B
+	  + sum_i ((-z - a s_i) G_i + (z + y^-i (z^(2+j) 2^(i mod n) - b s_i^-1)) H_i)
+	  + sum_j c z^(2+j) V_j
+
+Returns false if the proof does not verify, and an error if it or the
+commitments are malformed.
+
+Unverified: it has not been run on a proof made by the crate.
+*/
+func (ec *CryptoParams) DalekVerify(t *MerlinTranscript, proof *DalekRangeProof, comms []ECPoint, n int) (bool, error) {
+	if proof == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	m := len(comms)
+	if err := ec.checkDalekParams("DalekVerify", n, m); err != nil {
+		return false, err
+	}
+	size := n * m
+	// dalek takes the identity as a commitment, but nowhere else
+	for i, V := range comms {
+		if V.X == nil || V.Y == nil || (!ec.group().IsOnCurve(V) && !V.Equal(ec.Zero())) {
+			return false, fmt.Errorf("%w: commitment %d is not in ristretto255", ErrInvalidPoint, i)
+		}
+	}
+	if proof.Tx == nil || proof.TxBlinding == nil || proof.EBlinding == nil {
+		return false, fmt.Errorf("%w: missing t_x, t_x_blinding or e_blinding", ErrMalformedProof)
+	}
+	if err := checkPoints(ec.group(), proof.A, proof.S, proof.T1, proof.T2); err != nil {
+		return false, err
+	}
+	if err := proof.IPP.check(ec.group(), size); err != nil {
+		return false, err
+	}
+	N := ec.N
+
+	dalekRangeTranscript(t, n, comms)
+	t.AppendPoint("A", proof.A)
+	t.AppendPoint("S", proof.S)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+	t.AppendPoint("T_1", proof.T1)
+	t.AppendPoint("T_2", proof.T2)
+	cx := t.ChallengeScalar("x")
+	t.AppendScalar("t_x", proof.Tx)
+	t.AppendScalar("t_x_blinding", proof.TxBlinding)
+	t.AppendScalar("e_blinding", proof.EBlinding)
+	w := t.ChallengeScalar("w")
+
+	t.AppendMessage("dom-sep", []byte("ipp v1"))
+	t.AppendUint64("n", uint64(size))
+	ipp := &proof.IPP
+	challenges := make([]*big.Int, len(ipp.L))
+	for j := len(challenges) - 1; j >= 0; j-- {
+		challenges[j] = t.ipaRound(ipp.L[j], ipp.R[j])
+	}
+	inverses := ec.batchInvert(challenges)
+	squares := make([]*big.Int, len(challenges))
+	invSquares := make([]*big.Int, len(challenges))
+	for j := range challenges {
+		squares[j] = new(big.Int).Mod(new(big.Int).Mul(challenges[j], challenges[j]), N)
+		invSquares[j] = new(big.Int).Mod(new(big.Int).Mul(inverses[j], inverses[j]), N)
+	}
+	s, sInv := ec.ipaSVector(challenges, inverses, squares)
+
+	c, err := rand.Int(rand.Reader, N)
+	if err != nil {
+		return false, err
+	}
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, N) }
+	cx2 := mod(new(big.Int).Mul(cx, cx))
+	z2 := mod(new(big.Int).Mul(cz, cz))
+	ab := mod(new(big.Int).Mul(ipp.A, ipp.B))
+
+	// e_blinding + c t_x_blinding on B_blinding, negated
+	h := mod(new(big.Int).Mul(c, proof.TxBlinding))
+	h = mod(h.Neg(h.Add(h, proof.EBlinding)))
+
+	// w (t_x - ab) + c (delta - t_x) on B
+	delta := ec.DeltaMRP(ec.PowerVector(size, cy), cz, m)
+	g := mod(new(big.Int).Mul(w, new(big.Int).Sub(proof.Tx, ab)))
+	g = mod(g.Add(g, new(big.Int).Mul(c, new(big.Int).Sub(delta, proof.Tx))))
+
+	bpg := make([]*big.Int, size)
+	bph := make([]*big.Int, size)
+	yInv := ec.PowerVector(size, new(big.Int).ModInverse(cy, N))
+	zj := new(big.Int).Set(z2)
+	vScalars := make([]*big.Int, m)
+	for j := 0; j < m; j++ {
+		vScalars[j] = mod(new(big.Int).Mul(c, zj))
+		// zTwo is z^(2+j) 2^(i mod n)
+		zTwo := new(big.Int).Set(zj)
+		for i := j * n; i < (j+1)*n; i++ {
+			bpg[i] = mod(new(big.Int).Neg(new(big.Int).Add(cz, new(big.Int).Mul(ipp.A, s[i]))))
+			r := new(big.Int).Sub(zTwo, new(big.Int).Mul(ipp.B, sInv[i]))
+			r.Mul(r, yInv[i])
+			bph[i] = mod(r.Add(r, cz))
+			zTwo = mod(zTwo.Lsh(zTwo, 1))
+		}
+		zj = mod(zj.Mul(zj, cz))
+	}
+
+	points := []ECPoint{proof.A, proof.S, proof.T1, proof.T2}
+	scalars := []*big.Int{big.NewInt(1), cx, mod(new(big.Int).Mul(c, cx)), mod(new(big.Int).Mul(c, cx2))}
+	points = append(append(append(points, ipp.L...), ipp.R...), comms...)
+	scalars = append(append(append(scalars, squares...), invSquares...), vScalars...)
+
+	// U is B as well, and checkTerms adds up its scalar separately
+	terms := batchTerms{g: g, h: h, u: big.NewInt(0), bpg: bpg, bph: bph, points: points, scalars: scalars}
+	return ec.checkTerms([]batchTerms{terms}), nil
+}
+
+// Bytes returns proof in dalek's byte format, 32 (9 + 2 log2(nm)) bytes long
+func (proof *DalekRangeProof) Bytes() []byte {
+	g := ristrettoGroup{}
+	rounds := len(proof.IPP.L)
+	buf := make([]byte, 0, 32*(9+2*rounds))
+	for _, p := range []ECPoint{proof.A, proof.S, proof.T1, proof.T2} {
+		buf = append(buf, g.Encode(p)...)
+	}
+	for _, x := range []*big.Int{proof.Tx, proof.TxBlinding, proof.EBlinding} {
+		buf = append(buf, scalarLE(x, ristrettoOrder)...)
+	}
+	for j := rounds - 1; j >= 0; j-- {
+		buf = append(buf, g.Encode(proof.IPP.L[j])...)
+		buf = append(buf, g.Encode(proof.IPP.R[j])...)
+	}
+	buf = append(buf, scalarLE(proof.IPP.A, ristrettoOrder)...)
+	return append(buf, scalarLE(proof.IPP.B, ristrettoOrder)...)
+}
+
+// Rebuild parses a proof in dalek's byte format, rejecting it the way dalek's
+// RangeProof::from_bytes does and also if a point does not decode
+func (proof *DalekRangeProof) Rebuild(buf []byte) error {
+	num := len(buf) / 32
+	if len(buf)%32 != 0 || num < 9 || (num-9)%2 != 0 || (num-9)/2 >= 32 {
+		return fmt.Errorf("%w: %d bytes is not the length of a dalek range proof", ErrMalformedProof, len(buf))
+	}
+	g := ristrettoGroup{}
+	points := make([]ECPoint, 0, num-5)
+	scalars := make([]*big.Int, 0, 5)
+	for i := 0; i < num; i++ {
+		chunk := buf[32*i : 32*(i+1)]
+		if i < 4 || i >= 7 && i < num-2 {
+			p, err := g.Decode(chunk)
+			if err != nil {
+				return err
+			}
+			points = append(points, p)
+			continue
+		}
+		x := leInt(chunk)
+		if x.Cmp(ristrettoOrder) >= 0 {
+			return fmt.Errorf("%w: scalar %d is not reduced", ErrMalformedProof, len(scalars))
+		}
+		scalars = append(scalars, x)
+	}
+
+	rounds := (num - 9) / 2
+	*proof = DalekRangeProof{
+		A: points[0], S: points[1], T1: points[2], T2: points[3],
+		Tx: scalars[0], TxBlinding: scalars[1], EBlinding: scalars[2],
+		IPP: InnerProdArg{make([]ECPoint, rounds), make([]ECPoint, rounds), scalars[3], scalars[4]},
+	}
+	for r := 0; r < rounds; r++ {
+		proof.IPP.L[rounds-1-r] = points[4+2*r]
+		proof.IPP.R[rounds-1-r] = points[5+2*r]
+	}
+	return nil
+}
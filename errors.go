@@ -0,0 +1,60 @@
+package bp_go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the provers, verifiers and decoders. They are usually
+// wrapped with more detail, so compare them with errors.Is.
+var (
+	// ErrValueOutOfRange - the value is negative or does not fit in the bit length of the proof
+	ErrValueOutOfRange = errors.New("bulletproofs: value out of range")
+	// ErrMalformedProof - a proof is missing fields or could not be decoded
+	ErrMalformedProof = errors.New("bulletproofs: malformed proof")
+	// ErrInvalidPoint - a point could not be parsed or is not on the curve
+	ErrInvalidPoint = errors.New("bulletproofs: invalid point")
+	// ErrLengthMismatch - vectors or generator sets have incompatible lengths
+	ErrLengthMismatch = errors.New("bulletproofs: length mismatch")
+	// ErrUnsupportedBitLength - a range proof was asked for over a bit length it cannot use
+	ErrUnsupportedBitLength = errors.New("bulletproofs: unsupported bit length")
+	// ErrNotInvertible - a scalar that has to be inverted is zero mod the group order
+	ErrNotInvertible = errors.New("bulletproofs: scalar is not invertible")
+	// ErrInvalidInterval - the bounds of an interval proof are negative, reversed or too far apart
+	ErrInvalidInterval = errors.New("bulletproofs: invalid interval")
+	// ErrUnsatisfiedConstraint - the assignment given to a constraint system does not satisfy it
+	ErrUnsatisfiedConstraint = errors.New("bulletproofs: constraint not satisfied")
+	// ErrMissingAssignment - a prover was asked to allocate a variable without a value for it
+	ErrMissingAssignment = errors.New("bulletproofs: missing assignment")
+	// ErrInvalidPermutation - a shuffle was given something other than a permutation of its inputs
+	ErrInvalidPermutation = errors.New("bulletproofs: invalid permutation")
+	// ErrMisbehavingParty - a party in an aggregated proof sent a message that does not check out, see PartyError
+	ErrMisbehavingParty = errors.New("bulletproofs: misbehaving party")
+	// ErrMaliciousDealer - a dealer sent a challenge that would leak a party's secrets
+	ErrMaliciousDealer = errors.New("bulletproofs: malicious dealer")
+	// ErrStateReused - a step of the aggregation protocol was run twice on the same state
+	ErrStateReused = errors.New("bulletproofs: protocol state already used")
+	// ErrUnsupportedGroup - a group's order does not fit the package's scalar arithmetic
+	ErrUnsupportedGroup = errors.New("bulletproofs: unsupported group")
+	// ErrInvalidGenerators - a generator is not the point its seed hashes to, see CryptoParams.CheckGenerators
+	ErrInvalidGenerators = errors.New("bulletproofs: invalid generators")
+)
+
+// lengthError reports the lengths that did not line up in the function fn
+func lengthError(fn string, lengths ...int) error {
+	return fmt.Errorf("%w: %s got lengths %v", ErrLengthMismatch, fn, lengths)
+}
+
+// PartyError - the positions of the parties whose messages a Dealer rejected.
+// It matches ErrMisbehavingParty under errors.Is.
+type PartyError struct {
+	Parties []int
+}
+
+func (e *PartyError) Error() string {
+	return fmt.Sprintf("%v: parties %v", ErrMisbehavingParty, e.Parties)
+}
+
+func (e *PartyError) Unwrap() error {
+	return ErrMisbehavingParty
+}
@@ -0,0 +1,205 @@
+package bp_go
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+/*
+fieldElement - an element of the secp256k1 base field
+
+The value is held in four little endian 64 bit limbs and is always fully
+reduced mod p = 2^256 - 2^32 - 977. Because 2^256 = 2^32 + 977 mod p, a 512
+bit product is reduced by folding its high half back in multiplied by that
+33 bit constant, so no division is ever needed.
+*/
+type fieldElement [4]uint64
+
+// fieldC - 2^256 mod p
+const fieldC = 0x1000003D1
+
+var fieldP = fieldElement{0xFFFFFFFEFFFFFC2F, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}
+
+var fieldOne = fieldElement{1, 0, 0, 0}
+
+// setBig sets z to x mod p
+func (z *fieldElement) setBig(x *big.Int) *fieldElement {
+	var buf [32]byte
+	new(big.Int).Mod(x, curve.P).FillBytes(buf[:])
+	for i := 0; i < 4; i++ {
+		z[i] = beUint64(buf[32-8*(i+1):])
+	}
+	return z
+}
+
+// big returns z as a big.Int
+func (z *fieldElement) big() *big.Int {
+	var buf [32]byte
+	for i := 0; i < 4; i++ {
+		putBeUint64(buf[32-8*(i+1):], z[i])
+	}
+	return new(big.Int).SetBytes(buf[:])
+}
+
+func beUint64(b []byte) uint64 {
+	return uint64(b[7]) | uint64(b[6])<<8 | uint64(b[5])<<16 | uint64(b[4])<<24 |
+		uint64(b[3])<<32 | uint64(b[2])<<40 | uint64(b[1])<<48 | uint64(b[0])<<56
+}
+
+func putBeUint64(b []byte, v uint64) {
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+// isZero reports whether z is 0
+func (z *fieldElement) isZero() bool {
+	return z[0]|z[1]|z[2]|z[3] == 0
+}
+
+// equal reports whether z and x are the same element
+func (z *fieldElement) equal(x *fieldElement) bool {
+	return (z[0]^x[0])|(z[1]^x[1])|(z[2]^x[2])|(z[3]^x[3]) == 0
+}
+
+// reduce sets z to carry*2^256 + t mod p, given that value is below 2p
+func (z *fieldElement) reduce(t *fieldElement, carry uint64) *fieldElement {
+	var s fieldElement
+	var b uint64
+	s[0], b = bits.Sub64(t[0], fieldP[0], 0)
+	s[1], b = bits.Sub64(t[1], fieldP[1], b)
+	s[2], b = bits.Sub64(t[2], fieldP[2], b)
+	s[3], b = bits.Sub64(t[3], fieldP[3], b)
+
+	// keep t only if there was no carry out and t < p
+	keep := -(b &^ carry)
+	z[0] = (t[0] & keep) | (s[0] &^ keep)
+	z[1] = (t[1] & keep) | (s[1] &^ keep)
+	z[2] = (t[2] & keep) | (s[2] &^ keep)
+	z[3] = (t[3] & keep) | (s[3] &^ keep)
+	return z
+}
+
+// add sets z to x + y
+func (z *fieldElement) add(x, y *fieldElement) *fieldElement {
+	var t fieldElement
+	var c uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+	return z.reduce(&t, c)
+}
+
+// sub sets z to x - y
+func (z *fieldElement) sub(x, y *fieldElement) *fieldElement {
+	var t fieldElement
+	var b uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+
+	// add p back if we borrowed
+	mask := -b
+	var c uint64
+	z[0], c = bits.Add64(t[0], fieldP[0]&mask, 0)
+	z[1], c = bits.Add64(t[1], fieldP[1]&mask, c)
+	z[2], c = bits.Add64(t[2], fieldP[2]&mask, c)
+	z[3], _ = bits.Add64(t[3], fieldP[3]&mask, c)
+	return z
+}
+
+// neg sets z to -x
+func (z *fieldElement) neg(x *fieldElement) *fieldElement {
+	var zero fieldElement
+	return z.sub(&zero, x)
+}
+
+// double sets z to 2x
+func (z *fieldElement) double(x *fieldElement) *fieldElement {
+	return z.add(x, x)
+}
+
+// mul sets z to x * y
+func (z *fieldElement) mul(x, y *fieldElement) *fieldElement {
+	x0, x1, x2, x3 := x[0], x[1], x[2], x[3]
+	y0, y1, y2, y3 := y[0], y[1], y[2], y[3]
+
+	// schoolbook product t0..t7, one row of x at a time
+	c, t0 := madd(x0, y0, 0, 0)
+	c, t1 := madd(x0, y1, 0, c)
+	c, t2 := madd(x0, y2, 0, c)
+	t4, t3 := madd(x0, y3, 0, c)
+
+	c, t1 = madd(x1, y0, t1, 0)
+	c, t2 = madd(x1, y1, t2, c)
+	c, t3 = madd(x1, y2, t3, c)
+	t5, t4 := madd(x1, y3, t4, c)
+
+	c, t2 = madd(x2, y0, t2, 0)
+	c, t3 = madd(x2, y1, t3, c)
+	c, t4 = madd(x2, y2, t4, c)
+	t6, t5 := madd(x2, y3, t5, c)
+
+	c, t3 = madd(x3, y0, t3, 0)
+	c, t4 = madd(x3, y1, t4, c)
+	c, t5 = madd(x3, y2, t5, c)
+	t7, t6 := madd(x3, y3, t6, c)
+
+	return z.fold(t0, t1, t2, t3, t4, t5, t6, t7)
+}
+
+// fold sets z to the 512 bit value t0..t7 mod p
+func (z *fieldElement) fold(t0, t1, t2, t3, t4, t5, t6, t7 uint64) *fieldElement {
+	// r = t0..t3 + t4..t7 * 2^256 mod p
+	c, r0 := madd(t4, fieldC, t0, 0)
+	c, r1 := madd(t5, fieldC, t1, c)
+	c, r2 := madd(t6, fieldC, t2, c)
+	c, r3 := madd(t7, fieldC, t3, c)
+
+	// c is at most 34 bits, fold it once more
+	hi, lo := bits.Mul64(c, fieldC)
+	var s fieldElement
+	var carry uint64
+	s[0], carry = bits.Add64(r0, lo, 0)
+	s[1], carry = bits.Add64(r1, hi, carry)
+	s[2], carry = bits.Add64(r2, 0, carry)
+	s[3], carry = bits.Add64(r3, 0, carry)
+	return z.reduce(&s, carry)
+}
+
+// madd returns a*b + c + d as a 128 bit hi, lo pair, which cannot overflow
+func madd(a, b, c, d uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
+}
+
+// square sets z to x^2
+func (z *fieldElement) square(x *fieldElement) *fieldElement {
+	return z.mul(x, x)
+}
+
+// inverse sets z to x^-1 by raising it to p-2. The inverse of 0 is 0.
+func (z *fieldElement) inverse(x *fieldElement) *fieldElement {
+	// p-2 is 0xFF..FE FFFFFC2D, only the low limb has zero bits
+	exp := fieldElement{fieldP[0] - 2, fieldP[1], fieldP[2], fieldP[3]}
+	r := fieldOne
+	base := *x
+	for i := 3; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			r.square(&r)
+			if (exp[i]>>uint(j))&1 == 1 {
+				r.mul(&r, &base)
+			}
+		}
+	}
+	*z = r
+	return z
+}
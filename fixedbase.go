@@ -0,0 +1,192 @@
+package bp_go
+
+import (
+	"math/big"
+	"sync"
+)
+
+const (
+	// combWindow - bits per window of the G, H and U tables
+	combWindow = 4
+	// combWindows - windows needed to cover a 256 bit scalar with combWindow bits
+	combWindows = 256 / combWindow
+	// fixedWindow - bits per window of the BPG and BPH tables
+	fixedWindow = 10
+	// fixedWindows - windows needed to cover a 256 bit scalar with fixedWindow bits
+	fixedWindows = (256 + fixedWindow - 1) / fixedWindow
+	// fixedBucketThreshold - below this many vector scalars the BPG and BPH
+	// tables cost more than a variable base multiplication
+	fixedBucketThreshold = 32
+)
+
+/*
+fixedBaseTables - precomputed multiples of the generators of a CryptoParams
+
+G, H and U get a full table of d * 16^k * P for every 4 bit window k and
+digit d, so multiplying one of them by a scalar takes 64 additions and no
+doublings. BPG and BPH are far more numerous, so each only stores
+2^(10k) * P for every 10 bit window k. A sum over many of them is then one
+pass of Pippenger's bucket method in which every window shares the same
+buckets, again with no doublings.
+
+The tables are built once, the first time they are needed, and are only read
+after that, so one CryptoParams can be used from many goroutines.
+*/
+type fixedBaseTables struct {
+	once     sync.Once
+	g, h, u  []jacobianPoint // combWindows rows of 15 multiples
+	bpg, bph []jacobianPoint // fixedWindows entries per generator
+}
+
+// fixedTables returns the tables of ec, building them on first use. It returns
+// nil for params that have none, which includes every group without proverOps.
+func (ec *CryptoParams) fixedTables() *fixedBaseTables {
+	if ec.tables == nil {
+		return nil
+	}
+	ec.tables.once.Do(func() {
+		ec.tables.build(ec)
+	})
+	return ec.tables
+}
+
+func (t *fixedBaseTables) build(ec *CryptoParams) {
+	t.g = combTable(ec.G)
+	t.h = combTable(ec.H)
+	t.u = combTable(ec.U)
+	t.bpg = strideTable(ec.BPG)
+	t.bph = strideTable(ec.BPH)
+}
+
+// combTable returns d * 16^k * p at index k*15 + d-1, with Z = 1
+func combTable(p ECPoint) []jacobianPoint {
+	table := make([]jacobianPoint, combWindows*15)
+	base := toJacobian(p)
+	for k := 0; k < combWindows; k++ {
+		row := table[k*15 : (k+1)*15]
+		row[0] = base
+		for d := 1; d < 15; d++ {
+			row[d].add(&row[d-1], &base)
+		}
+		for j := 0; j < combWindow; j++ {
+			base.double(&base)
+		}
+	}
+	normalizeBatch(table)
+	return table
+}
+
+// strideTable returns 2^(fixedWindow*k) * points[i] at index i*fixedWindows + k,
+// with Z = 1
+func strideTable(points []ECPoint) []jacobianPoint {
+	table := make([]jacobianPoint, len(points)*fixedWindows)
+	for i := range points {
+		row := table[i*fixedWindows : (i+1)*fixedWindows]
+		row[0] = toJacobian(points[i])
+		for k := 1; k < fixedWindows; k++ {
+			row[k] = row[k-1]
+			for j := 0; j < fixedWindow; j++ {
+				row[k].double(&row[k])
+			}
+		}
+	}
+	normalizeBatch(table)
+	return table
+}
+
+/*
+FixedBaseMult - multiplication by the fixed generators
+
+Returns g*G + h*H + u*U + <a, BPG> + <b, BPH> using tables precomputed for the
+generators of ec. Any of g, h and u may be nil to leave that generator out,
+and a and b may be shorter than BPG and BPH, in which case only a prefix of
+the generators is used.
+*/
+func (ec *CryptoParams) FixedBaseMult(g, h, u *big.Int, a, b []*big.Int) (ECPoint, error) {
+	if len(a) > len(ec.BPG) || len(b) > len(ec.BPH) {
+		return ECPoint{}, lengthError("FixedBaseMult", len(a), len(b), len(ec.BPG), len(ec.BPH))
+	}
+	for _, v := range a {
+		if v == nil {
+			return ECPoint{}, lengthError("FixedBaseMult", len(a), len(b))
+		}
+	}
+	for _, v := range b {
+		if v == nil {
+			return ECPoint{}, lengthError("FixedBaseMult", len(a), len(b))
+		}
+	}
+	return ec.fixedBaseMult(g, h, u, a, b), nil
+}
+
+func (ec *CryptoParams) fixedBaseMult(g, h, u *big.Int, a, b []*big.Int) ECPoint {
+	t := ec.fixedTables()
+	if t == nil {
+		points := append(append([]ECPoint{}, ec.BPG[:len(a)]...), ec.BPH[:len(b)]...)
+		scalars := append(append([]*big.Int{}, a...), b...)
+		for i, s := range []*big.Int{g, h, u} {
+			if s != nil {
+				points = append(points, []ECPoint{ec.G, ec.H, ec.U}[i])
+				scalars = append(scalars, s)
+			}
+		}
+		return ec.msm(points, scalars)
+	}
+
+	var acc jacobianPoint
+	for i, s := range []*big.Int{g, h, u} {
+		if s == nil {
+			continue
+		}
+		table := [][]jacobianPoint{t.g, t.h, t.u}[i]
+		sl := toScalarLimbs(s)
+		for k := 0; k < combWindows; k++ {
+			if d := sl.window(uint(k*combWindow), combWindow); d != 0 {
+				acc.addAffine(&acc, &table[k*15+int(d)-1])
+			}
+		}
+	}
+
+	if len(a)+len(b) == 0 {
+		return acc.affine()
+	}
+	if len(a)+len(b) < fixedBucketThreshold {
+		jp := make([]jacobianPoint, 0, len(a)+len(b))
+		ss := make([]scalarLimbs, 0, len(a)+len(b))
+		for i := range a {
+			jp = append(jp, t.bpg[i*fixedWindows])
+			ss = append(ss, toScalarLimbs(a[i]))
+		}
+		for i := range b {
+			jp = append(jp, t.bph[i*fixedWindows])
+			ss = append(ss, toScalarLimbs(b[i]))
+		}
+		sum := msmJacobian(jp, ss)
+		acc.add(&acc, &sum)
+		return acc.affine()
+	}
+
+	buckets := make([]jacobianPoint, 1<<fixedWindow-1)
+	fill := func(scalars []*big.Int, table []jacobianPoint) {
+		for i, s := range scalars {
+			sl := toScalarLimbs(s)
+			row := table[i*fixedWindows : (i+1)*fixedWindows]
+			for k := range row {
+				if d := sl.window(uint(k*fixedWindow), fixedWindow); d != 0 {
+					buckets[d-1].addAffine(&buckets[d-1], &row[k])
+				}
+			}
+		}
+	}
+	fill(a, t.bpg)
+	fill(b, t.bph)
+
+	// sum_d d * bucket_d, as a running sum from the top bucket down
+	var running, sum jacobianPoint
+	for d := len(buckets) - 1; d >= 0; d-- {
+		running.add(&running, &buckets[d])
+		sum.add(&sum, &running)
+	}
+	acc.add(&acc, &sum)
+	return acc.affine()
+}
@@ -0,0 +1,68 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Gadgets - constraints for common statements, written against
+// ConstraintSystem so the prover and the verifier run the same code
+
+// BooleanGadget - constrains lc to be 0 or 1
+func BooleanGadget(cs ConstraintSystem, lc LinearCombination) {
+	_, _, o := cs.Multiply(lc, Constant(big.NewInt(1)).Sub(lc))
+	cs.Constrain(o.LC())
+}
+
+// EqualGadget - constrains a and b to be equal
+func EqualGadget(cs ConstraintSystem, a, b LinearCombination) {
+	cs.Constrain(a.Sub(b))
+}
+
+/*
+RangeGadget - constrains lc to fit in n bits
+
+Each bit b_i takes one gate, with inputs 1 - b_i and b_i and output 0, and
+the bits weighted by 2^i must add up to lc. This is the range proof of
+section 4 of the paper written as a circuit, using n gates per value.
+*/
+func RangeGadget(cs ConstraintSystem, lc LinearCombination, n int) error {
+	if n < 1 || n > maxBits {
+		return fmt.Errorf("%w: %d bits", ErrUnsupportedBitLength, n)
+	}
+	value := cs.Evaluate(lc)
+	if value != nil && value.BitLen() > n {
+		return fmt.Errorf("%w: value does not fit in %d bits", ErrValueOutOfRange, n)
+	}
+
+	one := big.NewInt(1)
+	sum := LinearCombination{}
+	exp := big.NewInt(1)
+	for i := 0; i < n; i++ {
+		var left, right *big.Int
+		if value != nil {
+			right = big.NewInt(int64(value.Bit(i)))
+			left = new(big.Int).Sub(one, right)
+		}
+		a, b, o, err := cs.AllocateMultiplier(left, right)
+		if err != nil {
+			return err
+		}
+		cs.Constrain(o.LC())
+		cs.Constrain(a.LC().Add(b.LC()).Sub(Constant(one)))
+		sum = sum.Add(b.LC().Scale(exp))
+		exp = new(big.Int).Lsh(exp, 1)
+	}
+	cs.Constrain(lc.Sub(sum))
+	return nil
+}
+
+/*
+LessThanGadget - constrains a < b
+
+It shows b - a - 1 fits in n bits, which only means a < b when a and b are
+already known to fit in n bits, for example from RangeGadget.
+*/
+func LessThanGadget(cs ConstraintSystem, a, b LinearCombination, n int) error {
+	return RangeGadget(cs, b.Sub(a).Sub(Constant(big.NewInt(1))), n)
+}
@@ -0,0 +1,299 @@
+package bp_go
+
+import (
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1"
+)
+
+// EC - An instance of CryptoParams used by the package level functions below.
+// Code that needs more than one set of generators should create its own
+// CryptoParams with NewECPrimeGroupKey and call the methods on it instead.
+var EC CryptoParams
+
+// VecLength - the length of the vector
+var VecLength = 64
+
+func init() {
+	EC = NewECPrimeGroupKey(VecLength)
+}
+
+// CheckGenerators - see CryptoParams.CheckGenerators
+func CheckGenerators() error {
+	return EC.CheckGenerators()
+}
+
+// GenerateNewParams - see CryptoParams.GenerateNewParams
+func GenerateNewParams(G, H []ECPoint, x *big.Int, L, R, P ECPoint) ([]ECPoint, []ECPoint, ECPoint, error) {
+	return EC.GenerateNewParams(G, H, x, L, R, P)
+}
+
+// InnerProduct - see CryptoParams.InnerProduct
+func InnerProduct(a []*big.Int, b []*big.Int) (*big.Int, error) {
+	return EC.InnerProduct(a, b)
+}
+
+// VectorAdd - see CryptoParams.VectorAdd
+func VectorAdd(v []*big.Int, w []*big.Int) ([]*big.Int, error) {
+	return EC.VectorAdd(v, w)
+}
+
+// VectorHadamard - see CryptoParams.VectorHadamard
+func VectorHadamard(v, w []*big.Int) ([]*big.Int, error) {
+	return EC.VectorHadamard(v, w)
+}
+
+// VectorAddScalar - see CryptoParams.VectorAddScalar
+func VectorAddScalar(v []*big.Int, s *big.Int) []*big.Int {
+	return EC.VectorAddScalar(v, s)
+}
+
+// ScalarVectorMul - see CryptoParams.ScalarVectorMul
+func ScalarVectorMul(v []*big.Int, s *big.Int) []*big.Int {
+	return EC.ScalarVectorMul(v, s)
+}
+
+// BatchInvert - see CryptoParams.BatchInvert
+func BatchInvert(v []*big.Int) ([]*big.Int, error) {
+	return EC.BatchInvert(v)
+}
+
+// InnerProductProveSub - see CryptoParams.InnerProductProveSub
+func InnerProductProveSub(t *Transcript, proof InnerProdArg, G, H []ECPoint, a []*big.Int, b []*big.Int, u ECPoint, P ECPoint) (InnerProdArg, error) {
+	return EC.InnerProductProveSub(t, proof, G, H, a, b, u, P)
+}
+
+// InnerProductProve - see CryptoParams.InnerProductProve
+func InnerProductProve(a []*big.Int, b []*big.Int, c *big.Int, P, U ECPoint, G, H []ECPoint) (InnerProdArg, error) {
+	return EC.InnerProductProve(a, b, c, P, U, G, H)
+}
+
+// InnerProductVerify - see CryptoParams.InnerProductVerify
+func InnerProductVerify(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) (bool, error) {
+	return EC.InnerProductVerify(c, P, U, G, H, ipp)
+}
+
+// InnerProductVerifyFast - see CryptoParams.InnerProductVerifyFast
+func InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) (bool, error) {
+	return EC.InnerProductVerifyFast(c, P, U, G, H, ipp)
+}
+
+// PowerVector - see CryptoParams.PowerVector
+func PowerVector(l int, base *big.Int) []*big.Int {
+	return EC.PowerVector(l, base)
+}
+
+// RandVector - see CryptoParams.RandVector
+func RandVector(l int) ([]*big.Int, error) {
+	return EC.RandVector(l)
+}
+
+// VectorSum - see CryptoParams.VectorSum
+func VectorSum(y []*big.Int) *big.Int {
+	return EC.VectorSum(y)
+}
+
+// Delta - see CryptoParams.Delta
+func Delta(y []*big.Int, z *big.Int) *big.Int {
+	return EC.Delta(y, z)
+}
+
+// CalculateL - see CryptoParams.CalculateL
+func CalculateL(aL, sL []*big.Int, z, x *big.Int) ([]*big.Int, error) {
+	return EC.CalculateL(aL, sL, z, x)
+}
+
+// CalculateR - see CryptoParams.CalculateR
+func CalculateR(aR, sR, y, po2 []*big.Int, z, x *big.Int) ([]*big.Int, error) {
+	return EC.CalculateR(aR, sR, y, po2, z, x)
+}
+
+// RPProve - see CryptoParams.RPProve
+func RPProve(v *big.Int) (RangeProof, error) {
+	return EC.RPProve(v)
+}
+
+// RPProveTrans - see CryptoParams.RPProveTrans
+func RPProveTrans(gamma *big.Int, v *big.Int) (RangeProof, error) {
+	return EC.RPProveTrans(gamma, v)
+}
+
+// RPProveBits - see CryptoParams.RPProveBits
+func RPProveBits(v *big.Int, n int) (RangeProof, error) {
+	return EC.RPProveBits(v, n)
+}
+
+// RPProveTransBits - see CryptoParams.RPProveTransBits
+func RPProveTransBits(gamma *big.Int, v *big.Int, n int) (RangeProof, error) {
+	return EC.RPProveTransBits(gamma, v, n)
+}
+
+// RPVerify - see CryptoParams.RPVerify
+func RPVerify(rp RangeProof) (bool, error) {
+	return EC.RPVerify(rp)
+}
+
+// RPVerifyTrans - see CryptoParams.RPVerifyTrans
+func RPVerifyTrans(comm *ECPoint, rp *RangeProof) (bool, error) {
+	return EC.RPVerifyTrans(comm, rp)
+}
+
+// RPVerifyBits - see CryptoParams.RPVerifyBits
+func RPVerifyBits(comm *ECPoint, rp *RangeProof, n int) (bool, error) {
+	return EC.RPVerifyBits(comm, rp, n)
+}
+
+// CalculateLMRP - see CryptoParams.CalculateLMRP
+func CalculateLMRP(aL, sL []*big.Int, z, x *big.Int) ([]*big.Int, error) {
+	return EC.CalculateLMRP(aL, sL, z, x)
+}
+
+// CalculateRMRP - see CryptoParams.CalculateRMRP
+func CalculateRMRP(aR, sR, y, zTimesTwo []*big.Int, z, x *big.Int) ([]*big.Int, error) {
+	return EC.CalculateRMRP(aR, sR, y, zTimesTwo, z, x)
+}
+
+// DeltaMRP - see CryptoParams.DeltaMRP
+func DeltaMRP(y []*big.Int, z *big.Int, m int) *big.Int {
+	return EC.DeltaMRP(y, z, m)
+}
+
+// MRPProve - see CryptoParams.MRPProve
+func MRPProve(values []*big.Int) ([]ECPoint, MultiRangeProof, error) {
+	return EC.MRPProve(values)
+}
+
+// MRPProveTrans - see CryptoParams.MRPProveTrans
+func MRPProveTrans(values []*big.Int, sSecret *big.Int) (MultiRangeProof, []ECPoint, error) {
+	return EC.MRPProveTrans(values, sSecret)
+}
+
+// MRPProveBits - see CryptoParams.MRPProveBits
+func MRPProveBits(values []*big.Int, n int) ([]ECPoint, MultiRangeProof, error) {
+	return EC.MRPProveBits(values, n)
+}
+
+// MRPProveTransBits - see CryptoParams.MRPProveTransBits
+func MRPProveTransBits(values []*big.Int, sSecret *big.Int, n int) (MultiRangeProof, []ECPoint, error) {
+	return EC.MRPProveTransBits(values, sSecret, n)
+}
+
+// MRPVerify - see CryptoParams.MRPVerify
+func MRPVerify(mrp *MultiRangeProof, comms []ECPoint) (bool, error) {
+	return EC.MRPVerify(mrp, comms)
+}
+
+// MRPVerifyBits - see CryptoParams.MRPVerifyBits
+func MRPVerifyBits(mrp *MultiRangeProof, comms []ECPoint, n int) (bool, error) {
+	return EC.MRPVerifyBits(mrp, comms, n)
+}
+
+// RPPlusProve - see CryptoParams.RPPlusProve
+func RPPlusProve(v *big.Int) (RangeProofPlus, error) {
+	return EC.RPPlusProve(v)
+}
+
+// RPPlusProveTrans - see CryptoParams.RPPlusProveTrans
+func RPPlusProveTrans(gamma *big.Int, v *big.Int) (RangeProofPlus, error) {
+	return EC.RPPlusProveTrans(gamma, v)
+}
+
+// RPPlusProveTransBits - see CryptoParams.RPPlusProveTransBits
+func RPPlusProveTransBits(gamma *big.Int, v *big.Int, n int) (RangeProofPlus, error) {
+	return EC.RPPlusProveTransBits(gamma, v, n)
+}
+
+// RPPlusVerify - see CryptoParams.RPPlusVerify
+func RPPlusVerify(rp RangeProofPlus) (bool, error) {
+	return EC.RPPlusVerify(rp)
+}
+
+// RPPlusVerifyTrans - see CryptoParams.RPPlusVerifyTrans
+func RPPlusVerifyTrans(comm *ECPoint, rp *RangeProofPlus) (bool, error) {
+	return EC.RPPlusVerifyTrans(comm, rp)
+}
+
+// RPPlusVerifyBits - see CryptoParams.RPPlusVerifyBits
+func RPPlusVerifyBits(comm *ECPoint, rp *RangeProofPlus, n int) (bool, error) {
+	return EC.RPPlusVerifyBits(comm, rp, n)
+}
+
+// MRPPlusProve - see CryptoParams.MRPPlusProve
+func MRPPlusProve(values []*big.Int) ([]ECPoint, MultiRangeProofPlus, error) {
+	return EC.MRPPlusProve(values)
+}
+
+// MRPPlusProveBits - see CryptoParams.MRPPlusProveBits
+func MRPPlusProveBits(values []*big.Int, n int) ([]ECPoint, MultiRangeProofPlus, error) {
+	return EC.MRPPlusProveBits(values, n)
+}
+
+// MRPPlusProveTrans - see CryptoParams.MRPPlusProveTrans
+func MRPPlusProveTrans(values []*big.Int, sSecret *big.Int) (MultiRangeProofPlus, []ECPoint, error) {
+	return EC.MRPPlusProveTrans(values, sSecret)
+}
+
+// MRPPlusVerify - see CryptoParams.MRPPlusVerify
+func MRPPlusVerify(mrp *MultiRangeProofPlus, comms []ECPoint) (bool, error) {
+	return EC.MRPPlusVerify(mrp, comms)
+}
+
+// MRPPlusVerifyBits - see CryptoParams.MRPPlusVerifyBits
+func MRPPlusVerifyBits(mrp *MultiRangeProofPlus, comms []ECPoint, n int) (bool, error) {
+	return EC.MRPPlusVerifyBits(mrp, comms, n)
+}
+
+// IntervalProve - see CryptoParams.IntervalProve
+func IntervalProve(v, a, b *big.Int) (ECPoint, MultiRangeProof, error) {
+	return EC.IntervalProve(v, a, b)
+}
+
+// IntervalProveTrans - see CryptoParams.IntervalProveTrans
+func IntervalProveTrans(gamma, v, a, b *big.Int) (MultiRangeProof, ECPoint, error) {
+	return EC.IntervalProveTrans(gamma, v, a, b)
+}
+
+// IntervalVerify - see CryptoParams.IntervalVerify
+func IntervalVerify(comm ECPoint, a, b *big.Int, mrp *MultiRangeProof) (bool, error) {
+	return EC.IntervalVerify(comm, a, b, mrp)
+}
+
+// VectorPCommit - see CryptoParams.VectorPCommit
+func VectorPCommit(value []*big.Int) (ECPoint, []*big.Int, error) {
+	return EC.VectorPCommit(value)
+}
+
+// TwoVectorPCommit - see CryptoParams.TwoVectorPCommit
+func TwoVectorPCommit(a []*big.Int, b []*big.Int) (ECPoint, error) {
+	return EC.TwoVectorPCommit(a, b)
+}
+
+// TwoVectorPCommitWithGens - see CryptoParams.TwoVectorPCommitWithGens
+func TwoVectorPCommitWithGens(G, H []ECPoint, a, b []*big.Int) (ECPoint, error) {
+	return EC.TwoVectorPCommitWithGens(G, H, a, b)
+}
+
+// VectorPCommitTrans - see CryptoParams.VectorPCommitTrans
+func VectorPCommitTrans(pubkey *secp256k1.PublicKey, value []*big.Int, sSecret *big.Int) (ECPoint, []*big.Int, [][]byte, error) {
+	return EC.VectorPCommitTrans(pubkey, value, sSecret)
+}
+
+// NewBatchVerifier - see CryptoParams.NewBatchVerifier
+func NewBatchVerifier() *BatchVerifier {
+	return EC.NewBatchVerifier()
+}
+
+// ShuffleProve - see CryptoParams.ShuffleProve
+func ShuffleProve(values, blinds []*big.Int, perm []int) (ShuffleProof, []ECPoint, []*big.Int, error) {
+	return EC.ShuffleProve(values, blinds, perm)
+}
+
+// ShuffleProveTrans - see CryptoParams.ShuffleProveTrans
+func ShuffleProveTrans(values, blinds []*big.Int, perm []int, newBlinds []*big.Int) (ShuffleProof, []ECPoint, error) {
+	return EC.ShuffleProveTrans(values, blinds, perm, newBlinds)
+}
+
+// ShuffleVerify - see CryptoParams.ShuffleVerify
+func ShuffleVerify(inputs, outputs []ECPoint, proof *ShuffleProof) (bool, error) {
+	return EC.ShuffleVerify(inputs, outputs, proof)
+}
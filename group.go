@@ -0,0 +1,501 @@
+package bp_go
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+/*
+Group - a prime order group the proofs can be made over
+
+The range proofs, the inner product argument and the Pedersen commitments do
+all their point arithmetic through the Group of their CryptoParams, and all
+their scalar arithmetic mod its order. Points are ECPoints in affine
+coordinates with (0, 0) standing for the identity, as in crypto/elliptic,
+except over ristretto255, whose elements have no single pair of coordinates,
+see ristrettoGroup.
+
+secp256k1 is the default and keeps the package's own field and point
+arithmetic, with its precomputed tables, by also implementing proverOps. Any
+other group gets genericOps, which only uses the methods below. Proofs are
+serialised with Encode and rebuilt with Decode of the group of the
+CryptoParams passed to SerializeWithParams and RebuildWithParams; the
+encoding does not name the group, so both sides have to agree on it.
+*/
+type Group interface {
+	// Name - a short name for the group, such as "secp256k1"
+	Name() string
+	// Order - the prime order of the group, which is the modulus of its scalars
+	Order() *big.Int
+	// Add returns p + q
+	Add(p, q ECPoint) ECPoint
+	// Neg returns -p
+	Neg(p ECPoint) ECPoint
+	// ScalarMult returns k p for the big endian scalar k. Provers multiply
+	// by their secrets with it, so it has to run in constant time in k.
+	ScalarMult(p ECPoint, k []byte) ECPoint
+	// MultiScalarMul returns the sum of scalars[i] * points[i] for public
+	// scalars, which may be in any range
+	MultiScalarMul(points []ECPoint, scalars []*big.Int) ECPoint
+	// IsOnCurve reports whether p is a point of the group other than the
+	// identity
+	IsOnCurve(p ECPoint) bool
+	// Encode returns the compressed encoding of p
+	Encode(p ECPoint) []byte
+	// Decode parses an encoding made by Encode
+	Decode(buf []byte) (ECPoint, error)
+	// HashToPoint maps msg to a point whose discrete log nobody knows
+	HashToPoint(msg []byte) ECPoint
+}
+
+// Secp256k1 returns the secp256k1 group, which NewECPrimeGroupKey uses
+func Secp256k1() Group {
+	return secp256k1Group{}
+}
+
+/*
+Secp256k1ZkpH returns the generator H that secp256k1-zkp, and with it Elements
+and Grin, commits to values with. Its X is SHA-256 of the uncompressed
+encoding of G and its Y is the even root.
+
+Only H matches the library. Its bulletproof vector generators, challenges and
+proof encoding are not reproduced here, so its proofs still do not verify.
+*/
+func Secp256k1ZkpH() ECPoint {
+	buf := make([]byte, 65)
+	buf[0] = 4
+	curve.Gx.FillBytes(buf[1:33])
+	curve.Gy.FillBytes(buf[33:])
+	x := sha256.Sum256(buf)
+	var H ECPoint
+	if err := H.Rebuild(append([]byte{2}, x[:]...)); err != nil {
+		panic("bulletproofs: " + err.Error())
+	}
+	return H
+}
+
+// P256 returns the NIST P-256 group, for running the proofs with keys kept
+// on hardware that only offers the NIST curves
+func P256() Group {
+	return ellipticGroup{elliptic.P256(), "P-256"}
+}
+
+// secp256k1Group - secp256k1 on top of the ECPoint methods and the constant
+// time code in ctpoint.go
+type secp256k1Group struct{}
+
+func (secp256k1Group) Name() string {
+	return "secp256k1"
+}
+
+func (secp256k1Group) Order() *big.Int {
+	return curve.N
+}
+
+func (secp256k1Group) Add(p, q ECPoint) ECPoint {
+	return p.Add(q)
+}
+
+func (secp256k1Group) Neg(p ECPoint) ECPoint {
+	return p.Neg()
+}
+
+func (secp256k1Group) ScalarMult(p ECPoint, k []byte) ECPoint {
+	s := secp256k1Scalars.fromBig(new(big.Int).SetBytes(k))
+	r := ctMultiScalarMul([]jacobianPoint{toJacobian(p)}, []scalar{s})
+	return r.affine()
+}
+
+func (secp256k1Group) MultiScalarMul(points []ECPoint, scalars []*big.Int) ECPoint {
+	return multiScalarMul(points, scalars)
+}
+
+func (secp256k1Group) IsOnCurve(p ECPoint) bool {
+	return p.valid()
+}
+
+func (secp256k1Group) Encode(p ECPoint) []byte {
+	return p.Bytes()
+}
+
+func (secp256k1Group) Decode(buf []byte) (ECPoint, error) {
+	var p ECPoint
+	err := p.Rebuild(buf)
+	return p, err
+}
+
+// HashToPoint - hash_to_curve of RFC 9380 with the suite HashToCurveSuite
+// under the tag HashToCurveDST, see hashtocurve.go
+func (secp256k1Group) HashToPoint(msg []byte) ECPoint {
+	return secp256k1Suite.hashToCurve(msg, []byte(HashToCurveDST))
+}
+
+func (secp256k1Group) ctSum(points []ECPoint, scalars []scalar) ECPoint {
+	r := ctMultiScalarMul(toJacobianSlice(points), scalars)
+	return r.affine()
+}
+
+func (secp256k1Group) ctGens(G, H, X []ECPoint) ctGens {
+	return newJacobianGens(G, H, X)
+}
+
+func (secp256k1Group) fold(lo, hi []ECPoint, xlo, xhi *big.Int) []ECPoint {
+	return toAffineSlice(foldGenerators(toJacobianSlice(lo), toJacobianSlice(hi), xlo, xhi))
+}
+
+func (secp256k1Group) newTables() *fixedBaseTables {
+	return &fixedBaseTables{}
+}
+
+// ellipticGroup - a prime order curve from crypto/elliptic. Its P-256 scalar
+// multiplication runs in constant time.
+type ellipticGroup struct {
+	c    elliptic.Curve
+	name string
+}
+
+func (g ellipticGroup) Name() string {
+	return g.name
+}
+
+func (g ellipticGroup) Order() *big.Int {
+	return g.c.Params().N
+}
+
+func (g ellipticGroup) Add(p, q ECPoint) ECPoint {
+	X, Y := g.c.Add(p.X, p.Y, q.X, q.Y)
+	return ECPoint{X, Y}
+}
+
+func (g ellipticGroup) Neg(p ECPoint) ECPoint {
+	if p.Y.Sign() == 0 {
+		return p
+	}
+	return ECPoint{p.X, new(big.Int).Sub(g.c.Params().P, p.Y)}
+}
+
+func (g ellipticGroup) ScalarMult(p ECPoint, k []byte) ECPoint {
+	X, Y := g.c.ScalarMult(p.X, p.Y, k)
+	return ECPoint{X, Y}
+}
+
+// MultiScalarMul adds up one multiplication per point. crypto/elliptic has
+// nothing faster, and its P-256 multiplications are fast enough that the
+// verifier does not need more.
+func (g ellipticGroup) MultiScalarMul(points []ECPoint, scalars []*big.Int) ECPoint {
+	N := g.Order()
+	acc := ECPoint{big.NewInt(0), big.NewInt(0)}
+	buf := make([]byte, (N.BitLen()+7)/8)
+	for i := range points {
+		s := new(big.Int).Mod(scalars[i], N)
+		if s.Sign() == 0 {
+			continue
+		}
+		acc = g.Add(acc, g.ScalarMult(points[i], s.FillBytes(buf)))
+	}
+	return acc
+}
+
+func (g ellipticGroup) IsOnCurve(p ECPoint) bool {
+	return p.X != nil && p.Y != nil && g.c.IsOnCurve(p.X, p.Y)
+}
+
+func (g ellipticGroup) Encode(p ECPoint) []byte {
+	return elliptic.MarshalCompressed(g.c, p.X, p.Y)
+}
+
+func (g ellipticGroup) Decode(buf []byte) (ECPoint, error) {
+	X, Y := elliptic.UnmarshalCompressed(g.c, buf)
+	if X == nil {
+		return ECPoint{}, fmt.Errorf("%w: not a compressed %s point", ErrInvalidPoint, g.name)
+	}
+	return ECPoint{X, Y}, nil
+}
+
+// HashToPoint - hash_to_curve of RFC 9380 with the suite P256HashToCurveSuite
+// under the tag P256HashToCurveDST, see hashtocurve.go. P-256 is the only
+// curve P256 hands out, so there is no suite for any other.
+func (g ellipticGroup) HashToPoint(msg []byte) ECPoint {
+	return p256Suite.hashToCurve(msg, []byte(P256HashToCurveDST))
+}
+
+/*
+NewGroupParams - CryptoParams for vectors of length n over the group g
+
+Every generator is g.HashToPoint of its seed, see GeneratorSeeds, so no
+discrete log relation between them is known. It fails if n is less than 1 or
+a seed hashes to the identity or to a point outside the group.
+*/
+func NewGroupParams(g Group, n int) (CryptoParams, error) {
+	if n < 1 {
+		return CryptoParams{}, lengthError("NewGroupParams", n)
+	}
+	fr := newScalarField(g.Order())
+	if fr == nil {
+		return CryptoParams{}, fmt.Errorf("%w: the order of %s is even or longer than 256 bits", ErrUnsupportedGroup, g.Name())
+	}
+
+	ec := CryptoParams{
+		Group: g,
+		BPG:   make([]ECPoint, n),
+		BPH:   make([]ECPoint, n),
+		N:     g.Order(),
+		V:     n,
+		fr:    fr}
+	for _, s := range GeneratorSeeds(n) {
+		p := g.HashToPoint(s.Seed)
+		if !g.IsOnCurve(p) {
+			return CryptoParams{}, fmt.Errorf("%w: %s[%d] hashed to a point outside %s", ErrInvalidGenerators, s.Role, s.Index, g.Name())
+		}
+		*ec.generator(s) = p
+	}
+	ec.tables = ec.ops().newTables()
+	ec.id = ec.ID()
+	return ec, nil
+}
+
+/*
+GeneratorSeed - the public description of one generator, which is
+Group.HashToPoint of Seed. Seed is "bulletproofs " followed by Role and then
+Index as a big endian uint32, so "bulletproofs BPG" || 00 00 00 05 for
+BPG[5]. The hash is hash_to_curve of RFC 9380, over secp256k1 with the suite
+HashToCurveSuite and the tag HashToCurveDST, over P-256 with
+P256HashToCurveSuite and P256HashToCurveDST and over ristretto255 with
+Ristretto255HashToCurveSuite and Ristretto255HashToCurveDST, and anyone can
+re-derive the generators from this description with any implementation of
+it. NewDalekParams does not use seeds but the generators of the dalek crate.
+*/
+type GeneratorSeed struct {
+	// Role - BPG or BPH for the vector generators, U for the inner product
+	// argument and G and H for the Pedersen commitments
+	Role  string
+	Index int
+	Seed  []byte
+}
+
+// GeneratorSeeds - the seeds of every generator of params for vectors of
+// length n: BPG[0] to BPG[n-1], BPH[0] to BPH[n-1], then U, G and H
+func GeneratorSeeds(n int) []GeneratorSeed {
+	seeds := make([]GeneratorSeed, 0, 2*n+3)
+	for _, role := range []string{"BPG", "BPH"} {
+		for i := 0; i < n; i++ {
+			seeds = append(seeds, generatorSeed(role, i))
+		}
+	}
+	for _, role := range []string{"U", "G", "H"} {
+		seeds = append(seeds, generatorSeed(role, 0))
+	}
+	return seeds
+}
+
+func generatorSeed(role string, i int) GeneratorSeed {
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], uint32(i))
+	return GeneratorSeed{role, i, append([]byte("bulletproofs "+role), idx[:]...)}
+}
+
+// generator returns the field of ec that holds the generator s describes
+func (ec *CryptoParams) generator(s GeneratorSeed) *ECPoint {
+	switch s.Role {
+	case "BPG":
+		return &ec.BPG[s.Index]
+	case "BPH":
+		return &ec.BPH[s.Index]
+	case "U":
+		return &ec.U
+	case "G":
+		return &ec.G
+	default:
+		return &ec.H
+	}
+}
+
+/*
+CheckGenerators - re-derives every generator of ec from its seed over the
+group of ec, and returns ErrInvalidGenerators naming the first one that is
+not what its seed hashes to. Use it on a generator set received from
+elsewhere before trusting proofs made with it. Params from
+NewLegacyECPrimeGroupKey do not pass, since nothing is known about how their
+generators were chosen beyond the code that chose them.
+*/
+func (ec *CryptoParams) CheckGenerators() error {
+	n := len(ec.BPG)
+	if len(ec.BPH) != n {
+		return lengthError("CheckGenerators", n, len(ec.BPH))
+	}
+	g := ec.group()
+	for _, s := range GeneratorSeeds(n) {
+		p := ec.generator(s)
+		if p.X == nil || p.Y == nil || !p.Equal(g.HashToPoint(s.Seed)) {
+			return fmt.Errorf("%w: %s[%d] over %s", ErrInvalidGenerators, s.Role, s.Index, g.Name())
+		}
+	}
+	return nil
+}
+
+// group returns the group of ec, secp256k1 unless it says otherwise
+func (ec *CryptoParams) group() Group {
+	if ec.Group == nil {
+		return secp256k1Group{}
+	}
+	return ec.Group
+}
+
+/*
+proverOps - arithmetic a Group can do faster than its methods allow: the
+constant time sums of the prover, the folding of generators in the inner
+product argument and fixed base tables. It is optional, and the code only
+reaches it through CryptoParams.ops, which falls back on genericOps for
+groups that do not implement it.
+*/
+type proverOps interface {
+	// ctSum - see CryptoParams.ctSum
+	ctSum(points []ECPoint, scalars []scalar) ECPoint
+	// ctCommitAt - see CryptoParams.ctCommitAt
+	ctCommitAt(ec *CryptoParams, g, h *scalar, offset int, a, b []scalar) ECPoint
+	// ctGens - see CryptoParams.ctGens
+	ctGens(G, H, X []ECPoint) ctGens
+	// fold returns xlo*lo[i] + xhi*hi[i] for every i, for public factors
+	fold(lo, hi []ECPoint, xlo, xhi *big.Int) []ECPoint
+	// newTables returns empty fixed base tables for params over the group,
+	// or nil if it has none, see fixedTables
+	newTables() *fixedBaseTables
+}
+
+// ops returns the proverOps of the group of ec
+func (ec *CryptoParams) ops() proverOps {
+	g := ec.group()
+	if o, ok := g.(proverOps); ok {
+		return o
+	}
+	return genericOps{g}
+}
+
+// genericOps - proverOps for any Group, from its methods alone
+type genericOps struct {
+	g Group
+}
+
+// msm returns the sum of scalars[i] * points[i] for public scalars
+func (ec *CryptoParams) msm(points []ECPoint, scalars []*big.Int) ECPoint {
+	return ec.group().MultiScalarMul(points, scalars)
+}
+
+// add returns p + q
+func (ec *CryptoParams) add(p, q ECPoint) ECPoint {
+	return ec.group().Add(p, q)
+}
+
+// mult returns s p for a public scalar s
+func (ec *CryptoParams) mult(p ECPoint, s *big.Int) ECPoint {
+	return ec.msm([]ECPoint{p}, []*big.Int{s})
+}
+
+// ctSum returns the sum of scalars[i] * points[i] for public points and
+// secret scalars, see ctMultiScalarMul
+func (ec *CryptoParams) ctSum(points []ECPoint, scalars []scalar) ECPoint {
+	return ec.ops().ctSum(points, scalars)
+}
+
+// ctSum adds up one Group.ScalarMult per point. The multiplications run in
+// constant time and only their results, which are as good as public, go
+// through Add.
+func (o genericOps) ctSum(points []ECPoint, scalars []scalar) ECPoint {
+	acc := ECPoint{big.NewInt(0), big.NewInt(0)}
+	for i := range points {
+		acc = o.g.Add(acc, o.g.ScalarMult(points[i], scalars[i].bytes()))
+	}
+	return acc
+}
+
+func (o genericOps) ctCommitAt(ec *CryptoParams, g, h *scalar, offset int, a, b []scalar) ECPoint {
+	var points []ECPoint
+	var scalars []scalar
+	for i, s := range []*scalar{g, h} {
+		if s != nil {
+			points = append(points, []ECPoint{ec.G, ec.H}[i])
+			scalars = append(scalars, *s)
+		}
+	}
+	points = append(append(points, ec.BPG[offset:offset+len(a)]...), ec.BPH[offset:offset+len(b)]...)
+	scalars = append(append(scalars, a...), b...)
+	return o.ctSum(points, scalars)
+}
+
+func (o genericOps) fold(lo, hi []ECPoint, xlo, xhi *big.Int) []ECPoint {
+	r := make([]ECPoint, len(lo))
+	for i := range lo {
+		r[i] = o.g.MultiScalarMul([]ECPoint{lo[i], hi[i]}, []*big.Int{xlo, xhi})
+	}
+	return r
+}
+
+func (genericOps) newTables() *fixedBaseTables {
+	return nil
+}
+
+/*
+ctGens - the public generators a prover multiplies by secret scalars round
+after round of an inner product argument, folding them in half as it goes,
+together with a few extra points that are never folded.
+
+secp256k1 keeps them in Jacobian coordinates and reuses its table space from
+one round to the next, genericOps keeps ECPoints and uses the Group methods.
+*/
+type ctGens interface {
+	// commit returns <sg, G[g:]> + <sh, H[h:]> + <sx, X>, taking len(sg)
+	// points of G from g on, len(sh) of H from h on and len(sx) of X
+	commit(g int, sg []scalar, h int, sh []scalar, sx []scalar) ECPoint
+	// fold folds the first n points of G with foldScaled(gs, gOuter, gInner)
+	// and those of H with foldScaled(hs, hOuter, hInner)
+	fold(n int, gs, hs []scalar, gOuter, gInner, hOuter, hInner *scalar)
+}
+
+// ctGens returns copies of G and H, which the prover may fold, and the extra
+// points X
+func (ec *CryptoParams) ctGens(G, H []ECPoint, X ...ECPoint) ctGens {
+	return ec.ops().ctGens(G, H, X)
+}
+
+func (o genericOps) ctGens(G, H, X []ECPoint) ctGens {
+	return &groupGens{
+		g: o.g,
+		G: append([]ECPoint{}, G...),
+		H: append([]ECPoint{}, H...),
+		X: X,
+	}
+}
+
+// groupGens - ctGens over any Group
+type groupGens struct {
+	g       Group
+	G, H, X []ECPoint
+}
+
+func (gg *groupGens) commit(g int, sg []scalar, h int, sh []scalar, sx []scalar) ECPoint {
+	points := append(append(append([]ECPoint{}, gg.G[g:g+len(sg)]...), gg.H[h:h+len(sh)]...), gg.X[:len(sx)]...)
+	scalars := append(append(append([]scalar{}, sg...), sh...), sx...)
+	return genericOps{gg.g}.ctSum(points, scalars)
+}
+
+func (gg *groupGens) fold(n int, gs, hs []scalar, gOuter, gInner, hOuter, hInner *scalar) {
+	gg.foldScaled(gg.G[:n], gs[:n], gOuter, gInner)
+	gg.foldScaled(gg.H[:n], hs[:n], hOuter, hInner)
+}
+
+// foldScaled is the package level foldScaled with the Group methods. The
+// factors are public, so ScalarMult being constant time is only a cost here.
+func (gg *groupGens) foldScaled(P []ECPoint, s []scalar, outer, inner *scalar) {
+	nprime := len(P) / 2
+	sinv := scalarBatchInverse(s[:nprime])
+	var k scalar
+	for i := 0; i < nprime; i++ {
+		k.mul(inner, &s[nprime+i])
+		k.mul(&k, &sinv[i])
+		P[i] = gg.g.Add(P[i], gg.g.ScalarMult(P[nprime+i], k.bytes()))
+		s[i].mul(&s[i], outer)
+	}
+}
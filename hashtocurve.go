@@ -0,0 +1,229 @@
+package bp_go
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"hash"
+	"math/big"
+)
+
+/*
+Hashing to curves with the suites of RFC 9380: secp256k1_XMD:SHA-256_SSWU_RO_
+for secp256k1 and P256_XMD:SHA-256_SSWU_RO_ for P-256. A message is expanded
+with expand_message_xmd into two field elements, each is mapped onto the curve
+by the simplified SWU map, and the two points are added. secp256k1 has a = 0,
+which the map cannot handle, so its points are mapped onto the curve E' that
+is 3-isogenous to it and carried over by the isogeny. Both curves have
+cofactor 1, so nothing has to be cleared.
+
+Only generators are hashed, so the arithmetic is done on big.Ints and does
+not try to run in constant time.
+*/
+
+// HashToCurveSuite - the RFC 9380 suite secp256k1 generators are hashed with
+const HashToCurveSuite = "secp256k1_XMD:SHA-256_SSWU_RO_"
+
+// HashToCurveDST - the domain separation tag Secp256k1().HashToPoint hashes
+// under
+const HashToCurveDST = "bp-go-V01-CS02-with-" + HashToCurveSuite
+
+// P256HashToCurveSuite - the RFC 9380 suite P-256 generators are hashed with
+const P256HashToCurveSuite = "P256_XMD:SHA-256_SSWU_RO_"
+
+// P256HashToCurveDST - the domain separation tag P256().HashToPoint hashes
+// under
+const P256HashToCurveDST = "bp-go-V01-CS02-with-" + P256HashToCurveSuite
+
+/*
+h2cSuite - what hash_to_curve needs to know about a curve: its field, the
+curve y^2 = x^3 + A x + B the SWU map lands on with the non-square Z of the
+map, the isogeny from that curve (nil if it is the curve itself) and the
+addition of the curve. The field has to be 3 mod 4, so square roots are
+powers.
+*/
+type h2cSuite struct {
+	p, a, b, z *big.Int
+	iso        func(ECPoint) ECPoint
+	add        func(p, q ECPoint) ECPoint
+}
+
+var secp256k1Suite = &h2cSuite{curve.P, sswuA, sswuB, sswuZ, isoMap, ECPoint.Add}
+
+var p256Suite = func() *h2cSuite {
+	c := elliptic.P256()
+	P := c.Params().P
+	return &h2cSuite{
+		p: P,
+		a: new(big.Int).Sub(P, big.NewInt(3)),
+		b: c.Params().B,
+		z: new(big.Int).Sub(P, big.NewInt(10)),
+		add: func(p, q ECPoint) ECPoint {
+			X, Y := c.Add(p.X, p.Y, q.X, q.Y)
+			return ECPoint{X, Y}
+		},
+	}
+}()
+
+// sswuA, sswuB and sswuZ - the curve E': y^2 = x^3 + A x + B, which is
+// 3-isogenous to secp256k1, and the non-square Z of the simplified SWU map
+var sswuA, sswuB, sswuZ = hexInt("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533"), big.NewInt(1771), new(big.Int).Sub(curve.P, big.NewInt(11))
+
+// isoXNum, isoXDen, isoYNum and isoYDen - the coefficients of the 3-isogeny
+// from E' to secp256k1, lowest degree first. The denominators are monic and
+// their leading 1 is left out.
+var (
+	isoXNum = hexInts(
+		"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7",
+		"07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581",
+		"534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262",
+		"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c")
+	isoXDen = hexInts(
+		"d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b",
+		"edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14")
+	isoYNum = hexInts(
+		"4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c",
+		"c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3",
+		"29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931",
+		"2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84")
+	isoYDen = hexInts(
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b",
+		"7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573",
+		"6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f")
+)
+
+func hexInt(s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("bulletproofs: bad constant " + s)
+	}
+	return x
+}
+
+func hexInts(s ...string) []*big.Int {
+	v := make([]*big.Int, len(s))
+	for i := range s {
+		v[i] = hexInt(s[i])
+	}
+	return v
+}
+
+// hashToCurve - hash_to_curve of RFC 9380 for the suite c under the tag dst
+func (c *h2cSuite) hashToCurve(msg, dst []byte) ECPoint {
+	uniform := expandMessageXMD(sha256.New, msg, dst, 96)
+	u0 := new(big.Int).Mod(new(big.Int).SetBytes(uniform[:48]), c.p)
+	u1 := new(big.Int).Mod(new(big.Int).SetBytes(uniform[48:]), c.p)
+	q0, q1 := c.mapToCurveSSWU(u0), c.mapToCurveSSWU(u1)
+	if c.iso != nil {
+		q0, q1 = c.iso(q0), c.iso(q1)
+	}
+	return c.add(q0, q1)
+}
+
+// expandMessageXMD - expand_message_xmd of RFC 9380 with the hash newHash,
+// returning n uniform bytes. n is at most 255 digests and dst at most 255
+// bytes long.
+func expandMessageXMD(newHash func() hash.Hash, msg, dst []byte, n int) []byte {
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := newHash()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, n+h.Size())
+	bi := make([]byte, h.Size())
+	for i := 1; len(out) < n; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:n]
+}
+
+// mapToCurveSSWU - the simplified SWU map of section 6.6.2 of RFC 9380 from
+// the field element u onto the curve of c
+func (c *h2cSuite) mapToCurveSSWU(u *big.Int) ECPoint {
+	P := c.p
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, P) }
+
+	u2 := mod(new(big.Int).Mul(u, u))
+	zu2 := mod(new(big.Int).Mul(c.z, u2))
+	tv1 := mod(new(big.Int).Mul(zu2, zu2))
+	tv1 = mod(tv1.Add(tv1, zu2))
+
+	var x1 *big.Int
+	if tv1.Sign() == 0 {
+		// x1 = B / (Z A)
+		x1 = mod(new(big.Int).Mul(c.z, c.a))
+		x1 = mod(x1.Mul(x1.ModInverse(x1, P), c.b))
+	} else {
+		// x1 = (-B / A) (1 + 1 / tv1)
+		tv1.ModInverse(tv1, P).Add(tv1, big.NewInt(1))
+		x1 = new(big.Int).ModInverse(c.a, P)
+		x1 = mod(x1.Mul(x1, new(big.Int).Sub(P, c.b)))
+		x1 = mod(x1.Mul(x1, tv1))
+	}
+
+	x := x1
+	y, ok := c.sqrt(x1)
+	if !ok {
+		x = mod(new(big.Int).Mul(zu2, x1))
+		y, _ = c.sqrt(x)
+	}
+	if u.Bit(0) != y.Bit(0) {
+		y.Sub(P, y)
+	}
+	return ECPoint{x, y}
+}
+
+// sqrt returns a square root of x^3 + A x + B, the right hand side of the
+// curve of c, and whether there is one
+func (c *h2cSuite) sqrt(x *big.Int) (*big.Int, bool) {
+	return curveSqrt(c.p, c.a, c.b, x)
+}
+
+// curveSqrt returns a square root of x^3 + a x + b mod p, for p = 3 mod 4,
+// and whether there is one
+func curveSqrt(p, a, b, x *big.Int) (*big.Int, bool) {
+	gx := new(big.Int).Mul(x, x)
+	gx.Add(gx, a).Mul(gx, x).Add(gx, b).Mod(gx, p)
+
+	e := new(big.Int).Add(p, big.NewInt(1))
+	y := new(big.Int).Exp(gx, e.Rsh(e, 2), p)
+	y2 := new(big.Int).Mul(y, y)
+	return y, y2.Mod(y2, p).Cmp(gx) == 0
+}
+
+// isoMap carries a point of E' over to secp256k1 by the 3-isogeny. A zero
+// denominator, which only its kernel hits, gives the identity.
+func isoMap(p ECPoint) ECPoint {
+	P := curve.P
+	eval := func(k []*big.Int, monic bool) *big.Int {
+		acc := big.NewInt(0)
+		if monic {
+			acc.SetInt64(1)
+		}
+		for i := len(k) - 1; i >= 0; i-- {
+			acc.Mul(acc, p.X).Add(acc, k[i]).Mod(acc, P)
+		}
+		return acc
+	}
+
+	xDen, yDen := eval(isoXDen, true), eval(isoYDen, true)
+	if xDen.Sign() == 0 || yDen.Sign() == 0 {
+		return ECPoint{big.NewInt(0), big.NewInt(0)}
+	}
+	x := eval(isoXNum, false)
+	x.Mul(x, xDen.ModInverse(xDen, P)).Mod(x, P)
+	y := eval(isoYNum, false)
+	y.Mul(y, yDen.ModInverse(yDen, P)).Mul(y, p.Y).Mod(y, P)
+	return ECPoint{x, y}
+}
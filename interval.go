@@ -0,0 +1,115 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+)
+
+/*
+Interval proofs
+
+A proof that the value v committed to in V = vG + gammaH lies in the public
+interval [a, b] is an aggregate range proof over two values:
+
+	v - a, committed to by V - aG with blinding factor gamma
+	b - v, committed to by bG - V with blinding factor -gamma
+
+Both are shown to be in [0, 2^n), where n is the smallest of BitLengths with
+b - a < 2^n, so each side is only as long as the interval needs. The verifier
+works out n and both shifted commitments from V, a and b itself.
+*/
+
+// intervalBits returns the bit length of an interval proof over [a, b]
+func (ec *CryptoParams) intervalBits(a, b *big.Int) (int, error) {
+	if a == nil || b == nil || a.Sign() < 0 || b.Cmp(a) < 0 || b.Cmp(ec.N) >= 0 {
+		return 0, fmt.Errorf("%w: [%v, %v]", ErrInvalidInterval, a, b)
+	}
+	width := new(big.Int).Sub(b, a).BitLen()
+	for _, n := range BitLengths {
+		if width <= n {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: [%v, %v] is wider than 2^%d", ErrInvalidInterval, a, b, maxBits)
+}
+
+// intervalCommitments returns the commitments to v - a and b - v given the
+// commitment V to v
+func (ec *CryptoParams) intervalCommitments(comm ECPoint, a, b *big.Int) []ECPoint {
+	one := big.NewInt(1)
+	return []ECPoint{
+		ec.msm([]ECPoint{comm, ec.G}, []*big.Int{one, new(big.Int).Neg(a)}),
+		ec.msm([]ECPoint{comm, ec.G}, []*big.Int{new(big.Int).Neg(one), b}),
+	}
+}
+
+/*
+IntervalProve - Interval Proof Prove
+
+Given a value v and public bounds a <= b, commits to v with a random blinding
+factor and proves that a <= v <= b. The proof needs 2n generators, where n is
+the bit length chosen for the interval.
+*/
+func (ec *CryptoParams) IntervalProve(v, a, b *big.Int) (ECPoint, MultiRangeProof, error) {
+	gamma, err := ec.randScalar()
+	if err != nil {
+		return ECPoint{}, MultiRangeProof{}, err
+	}
+
+	mrp, comm, err := ec.IntervalProveTrans(gamma, v, a, b)
+	return comm, mrp, err
+}
+
+/*
+IntervalProveTrans - Interval Proof Prove customised for transactions
+
+Same as IntervalProve, except the commitment uses the blinding factor gamma,
+for example one made by Commitment.Generate.
+*/
+func (ec *CryptoParams) IntervalProveTrans(gamma, v, a, b *big.Int) (MultiRangeProof, ECPoint, error) {
+	n, err := ec.intervalBits(a, b)
+	if err != nil {
+		return MultiRangeProof{}, ECPoint{}, err
+	}
+	if v == nil || v.Cmp(a) < 0 || v.Cmp(b) > 0 {
+		return MultiRangeProof{}, ECPoint{}, fmt.Errorf("%w: value is outside [%v, %v]", ErrValueOutOfRange, a, b)
+	}
+
+	g := ec.fr.fromBig(gamma)
+	var negG scalar
+	negG.neg(&g)
+
+	values := []*big.Int{new(big.Int).Sub(v, a), new(big.Int).Sub(b, v)}
+	mrp, _, err := ec.mrpProve(values, []*big.Int{gamma, negG.big()}, n)
+	if err != nil {
+		return MultiRangeProof{}, ECPoint{}, err
+	}
+
+	sv := ec.fr.fromBig(v)
+	return mrp, ec.ctCommit(&sv, &g, nil, nil), nil
+}
+
+/*
+IntervalVerify - Interval Proof Verify
+
+Checks that mrp shows the value committed to in comm lies in [a, b]. Returns
+false if the proof does not verify, and an error if the bounds, the proof or
+the commitment are malformed.
+*/
+func (ec *CryptoParams) IntervalVerify(comm ECPoint, a, b *big.Int, mrp *MultiRangeProof) (bool, error) {
+	if mrp == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	n, err := ec.intervalBits(a, b)
+	if err != nil {
+		return false, err
+	}
+	if mrp.Bits != n {
+		return false, fmt.Errorf("%w: proof is over %d bits, the interval needs %d", ErrMalformedProof, mrp.Bits, n)
+	}
+	if err := checkPoints(ec.group(), comm); err != nil {
+		return false, err
+	}
+
+	return ec.mrpVerify(mrp, ec.intervalCommitments(comm, a, b), n)
+}
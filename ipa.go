@@ -0,0 +1,401 @@
+package bp_go
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/decred/base58"
+	"github.com/golang/protobuf/proto"
+	"github.com/peterdouglas/bp-go/pb"
+)
+
+/*
+Inner product arguments
+
+An inner product argument shows that the prover knows vectors a and b with
+
+	P = <a, G> + <b, H>    and    <a, b> = c
+
+for public generators G and H, a commitment P and a value c, using 2 log2(n)
+points and two scalars. Range proofs end with one, but nothing in it is
+specific to range proofs. It is exposed here so other protocols can use it,
+for example to open a vector commitment.
+
+The statement is bound in through a Transcript. A standalone argument starts
+from IPA.Transcript, which absorbs the generators, P and c. A protocol that
+runs the argument as one of its steps passes its own transcript, which must
+already hold everything P and c were computed from. In both cases the
+argument squeezes a challenge w, proves <a, b> = c against P + w c U, and for
+each round appends L and R and squeezes a challenge x.
+*/
+
+// InnerProdArg - Stores the values of the InnerProduct Arguements
+type InnerProdArg struct {
+	L []ECPoint
+	R []ECPoint
+	A *big.Int
+	B *big.Int
+}
+
+// check makes sure the argument is complete, has the rounds needed to fold
+// vectors of length n down to a single element and has its points in g
+func (ipp *InnerProdArg) check(g Group, n int) error {
+	if ipp.A == nil || ipp.B == nil {
+		return fmt.Errorf("%w: inner product argument is missing a or b", ErrMalformedProof)
+	}
+	if len(ipp.L) != len(ipp.R) || n != 1<<uint(len(ipp.L)) {
+		return fmt.Errorf("%w: inner product argument has %d L and %d R values for %d generators",
+			ErrMalformedProof, len(ipp.L), len(ipp.R), n)
+	}
+	if err := checkPoints(g, ipp.L...); err != nil {
+		return err
+	}
+	return checkPoints(g, ipp.R...)
+}
+
+// IPA - the generators an inner product argument is made over
+type IPA struct {
+	// G and H are padded to a power of two, see padGenerators
+	G, H []ECPoint
+	U    ECPoint
+
+	ec *CryptoParams
+	// size is the number of generators before padding
+	size int
+	// hScale, if not nil, makes the argument over hScale[i] * H[i] instead of
+	// H[i]. The verifier folds it into its scalars and never computes them.
+	hScale []*big.Int
+}
+
+// NewIPA returns an inner product argument over G and H, which must have the
+// same non zero length, and the point U, which should have no known discrete
+// log relation to any of them
+func (ec *CryptoParams) NewIPA(G, H []ECPoint, U ECPoint) (*IPA, error) {
+	if len(G) != len(H) || len(G) == 0 {
+		return nil, lengthError("NewIPA", len(G), len(H))
+	}
+	if err := checkPoints(ec.group(), U); err != nil {
+		return nil, err
+	}
+	Gpad, Hpad := padGenerators(ec.group(), G, H)
+	return &IPA{G: Gpad, H: Hpad, U: U, ec: ec, size: len(G)}, nil
+}
+
+// rangeProofIPA returns the argument a range proof over size bits ends with,
+// which is over the generators BPG and y^-i * BPH
+func (ec *CryptoParams) rangeProofIPA(size int, cy *big.Int) *IPA {
+	return &IPA{
+		G:      ec.BPG[:size],
+		H:      ec.BPH[:size],
+		U:      ec.U,
+		ec:     ec,
+		size:   size,
+		hScale: ec.PowerVector(size, new(big.Int).ModInverse(cy, ec.N)),
+	}
+}
+
+// Transcript starts the transcript of a standalone argument for P and c,
+// bound to the generators it is made over
+func (ipa *IPA) Transcript(P ECPoint, c *big.Int) *Transcript {
+	t := ipa.ec.NewTranscript("bulletproofs inner product")
+	t.AppendMessage("generators", pointsDigest([]ECPoint{ipa.U}, ipa.G[:ipa.size], ipa.H[:ipa.size]))
+	t.AppendPoint("P", P)
+	t.AppendScalar("c", c)
+	return t
+}
+
+/*
+Prove - Inner Product Argument Prove
+
+Proves that <a, b> = c for P = <a, G> + <b, H>. The prover never needs P or c
+itself, but t must already hold them or whatever they were computed from.
+a and b need one element for each generator given to NewIPA and are padded
+with zeros along with the generators.
+*/
+func (ipa *IPA) Prove(t *Transcript, a, b []*big.Int) (InnerProdArg, error) {
+	if len(a) != ipa.size || len(b) != ipa.size {
+		return InnerProdArg{}, lengthError("IPA.Prove", ipa.size, len(a), len(b))
+	}
+	n := len(ipa.G)
+	return ipa.prove(t, ipa.ec.padScalars(a, n), ipa.ec.padScalars(b, n)), nil
+}
+
+// prove runs the argument for a and b, which are secret and already padded
+func (ipa *IPA) prove(t *Transcript, a, b []scalar) InnerProdArg {
+	loglen := bits.Len(uint(len(a))) - 1
+	runningProof := InnerProdArg{
+		make([]ECPoint, loglen),
+		make([]ECPoint, loglen),
+		big.NewInt(0),
+		big.NewInt(0)}
+
+	// derive the scaling of U from the transcript
+	w := t.ChallengeScalar("w")
+
+	ux := ipa.ec.mult(ipa.U, w)
+	gens := ipa.ec.ctGens(ipa.G, ipa.H, ux)
+	return ipa.ec.innerProductProveSub(t, runningProof, gens, ipa.hScale, a, b)
+}
+
+/*
+IPAScalars - the scalars an inner product argument is checked with
+
+The argument holds if
+
+	P + sum_j (L[j] ipp.L[j] + R[j] ipp.R[j]) + U U + sum_i (G[i] G_i + H[i] H_i)
+
+is the point at infinity, where G_i and H_i are the padded generators of the
+IPA. A verifier with other equations to check can weight these by a random
+scalar and add them to its own multi-scalar multiplication, as BatchVerifier
+does.
+*/
+type IPAScalars struct {
+	L, R []*big.Int
+	U    *big.Int
+	G, H []*big.Int
+}
+
+// VerificationScalars replays the argument on t and returns the scalars it is
+// checked with, see IPAScalars. t must be in the same state as the prover's.
+func (ipa *IPA) VerificationScalars(t *Transcript, c *big.Int, ipp *InnerProdArg) (IPAScalars, error) {
+	if c == nil || ipp == nil {
+		return IPAScalars{}, fmt.Errorf("%w: missing inner product value or argument", ErrMalformedProof)
+	}
+	if err := ipp.check(ipa.ec.group(), len(ipa.G)); err != nil {
+		return IPAScalars{}, err
+	}
+	return ipa.verificationScalars(t, c, ipp), nil
+}
+
+// verificationScalars is VerificationScalars for an argument that has been
+// checked
+func (ipa *IPA) verificationScalars(t *Transcript, c *big.Int, ipp *InnerProdArg) IPAScalars {
+	N := ipa.ec.N
+	w := t.ChallengeScalar("w")
+
+	challenges := make([]*big.Int, len(ipp.L))
+	for j := len(challenges) - 1; j >= 0; j-- {
+		// prover sends L & R and gets a challenge
+		challenges[j] = t.ipaRound(ipp.L[j], ipp.R[j])
+	}
+
+	inverses := ipa.ec.batchInvert(challenges)
+	sc := IPAScalars{
+		L: make([]*big.Int, len(challenges)),
+		R: make([]*big.Int, len(challenges)),
+	}
+	for j := range challenges {
+		sc.L[j] = new(big.Int).Mod(new(big.Int).Mul(challenges[j], challenges[j]), N)
+		sc.R[j] = new(big.Int).Mod(new(big.Int).Mul(inverses[j], inverses[j]), N)
+	}
+
+	// w (c - ab)
+	ab := new(big.Int).Mul(ipp.A, ipp.B)
+	sc.U = new(big.Int).Mod(new(big.Int).Mul(w, new(big.Int).Sub(c, ab)), N)
+
+	// -a s_i and -b s_i^-1
+	sScalars, invsScalars := ipa.ec.ipaSVector(challenges, inverses, sc.L)
+	sc.G = ipa.ec.ScalarVectorMul(sScalars, new(big.Int).Neg(ipp.A))
+	sc.H = ipa.ec.ScalarVectorMul(invsScalars, new(big.Int).Neg(ipp.B))
+	if ipa.hScale != nil {
+		sc.H = ipa.ec.vectorHadamard(sc.H, ipa.hScale)
+	}
+	return sc
+}
+
+/*
+Verify - Inner Product Argument Verify
+
+Checks that ipp shows <a, b> = c for P = <a, G> + <b, H> with a single
+multi-scalar multiplication. t must be in the same state as the prover's.
+Returns false if the argument does not verify, and an error if it or P are
+malformed.
+*/
+func (ipa *IPA) Verify(t *Transcript, P ECPoint, c *big.Int, ipp *InnerProdArg) (bool, error) {
+	if err := checkPoints(ipa.ec.group(), P); err != nil {
+		return false, err
+	}
+	sc, err := ipa.VerificationScalars(t, c, ipp)
+	if err != nil {
+		return false, err
+	}
+
+	if !ipa.verify(P, ipp, sc) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// verify returns whether the scalars sc of ipp sum to infinity for P
+func (ipa *IPA) verify(P ECPoint, ipp *InnerProdArg, sc IPAScalars) bool {
+	size := 2 + 2*len(ipp.L) + 2*len(ipa.G)
+	points := append(make([]ECPoint, 0, size), P)
+	scalars := append(make([]*big.Int, 0, size), big.NewInt(1))
+	points = append(append(points, ipp.L...), ipp.R...)
+	scalars = append(append(scalars, sc.L...), sc.R...)
+	points = append(append(append(points, ipa.U), ipa.G...), ipa.H...)
+	scalars = append(append(append(scalars, sc.U), sc.G...), sc.H...)
+
+	sum := ipa.ec.msm(points, scalars)
+	return sum.X.Sign() == 0 && sum.Y.Sign() == 0
+}
+
+/*
+padGenerators extends G and H to the next power of two so an inner product
+argument can fold them in halves, and the vectors proven over them are padded
+with zeros to match.
+
+The extra generators cannot be the point at infinity: the zeros sit on them,
+and if they did not count towards P a prover could put anything there and
+shift <a, b> by the inner product of whatever it chose. Instead they are
+hashed to g from a digest of G and H and their index, so the prover and
+verifier derive the same points and nobody knows their discrete logs.
+*/
+func padGenerators(g Group, G, H []ECPoint) ([]ECPoint, []ECPoint) {
+	n := padCount(len(G))
+	if n == len(G) {
+		return G, H
+	}
+
+	seed := pointsDigest(G, H)
+	Gpad := append(make([]ECPoint, 0, n), G...)
+	Hpad := append(make([]ECPoint, 0, n), H...)
+	for j := uint32(0); len(Hpad) < n; j++ {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], j)
+		gen := g.HashToPoint(append(append([]byte("bulletproofs ipa padding"), seed...), buf[:]...))
+		if len(Gpad) < n {
+			Gpad = append(Gpad, gen)
+		} else {
+			Hpad = append(Hpad, gen)
+		}
+	}
+	return Gpad, Hpad
+}
+
+// padScalars converts v and appends zeros up to length n
+func (ec *CryptoParams) padScalars(v []*big.Int, n int) []scalar {
+	return append(ec.fr.fromBigs(v), make([]scalar, n-len(v))...)
+}
+
+/*
+ipaSVector returns the scalars s_i the folded generators G' = <s, G> and
+H' = <s^-1, H> are made of, together with their inverses, given the round
+challenges x_j, their inverses and their squares.
+
+s_i is the product over the rounds j of x_j if bit j of i is set and x_j^-1
+otherwise. So s_0 is the product of every x_j^-1, and setting the top bit k of
+i multiplies by x_k^2, which builds the whole vector with one multiplication
+per element. Flipping every bit of i inverts s_i, so s_i^-1 = s_(n-1-i).
+*/
+func (ec *CryptoParams) ipaSVector(challenges, inverses, squares []*big.Int) ([]*big.Int, []*big.Int) {
+	n := 1 << uint(len(challenges))
+	sScalars := make([]*big.Int, n)
+	invsScalars := make([]*big.Int, n)
+
+	sScalars[0] = big.NewInt(1)
+	for j := range inverses {
+		sScalars[0] = new(big.Int).Mod(new(big.Int).Mul(sScalars[0], inverses[j]), ec.N)
+	}
+	for i := 1; i < n; i++ {
+		k := bits.Len(uint(i)) - 1
+		sScalars[i] = new(big.Int).Mod(new(big.Int).Mul(sScalars[i-1<<uint(k)], squares[k]), ec.N)
+	}
+	for i := range sScalars {
+		invsScalars[i] = sScalars[n-1-i]
+	}
+
+	return sScalars, invsScalars
+}
+
+// pb returns the protobuf form of the argument, reporting missing fields to w
+func (ipp *InnerProdArg) pb(w *pbWriter) *pb.InnerProductProof {
+	pbIPP := &pb.InnerProductProof{}
+	if len(ipp.L) != len(ipp.R) {
+		w.fail(fmt.Sprintf("%d L values and %d R values", len(ipp.L), len(ipp.R)))
+		return pbIPP
+	}
+	for i := 0; i < len(ipp.L); i++ {
+		pbIPP.L = append(pbIPP.L, w.point("L", ipp.L[i]))
+		pbIPP.R = append(pbIPP.R, w.point("R", ipp.R[i]))
+	}
+	pbIPP.A = w.scalar("a", ipp.A)
+	pbIPP.B = w.scalar("b", ipp.B)
+	return pbIPP
+}
+
+// rebuildIPP decodes a protobuf inner product proof
+func rebuildIPP(g Group, pbIPP *pb.InnerProductProof) (InnerProdArg, error) {
+	ipp := InnerProdArg{}
+	if pbIPP == nil {
+		return ipp, fmt.Errorf("%w: missing inner product proof", ErrMalformedProof)
+	}
+	if len(pbIPP.L) != len(pbIPP.R) {
+		return ipp, fmt.Errorf("%w: %d L values and %d R values", ErrMalformedProof, len(pbIPP.L), len(pbIPP.R))
+	}
+
+	for i := 0; i < len(pbIPP.L); i++ {
+		newIPL := ECPoint{}
+		if err := rebuildPoint(g, &newIPL, pbIPP.L[i]); err != nil {
+			return ipp, err
+		}
+		newIPR := ECPoint{}
+		if err := rebuildPoint(g, &newIPR, pbIPP.R[i]); err != nil {
+			return ipp, err
+		}
+		ipp.L = append(ipp.L, newIPL)
+		ipp.R = append(ipp.R, newIPR)
+	}
+
+	ipp.A = new(big.Int).SetBytes(pbIPP.A)
+	ipp.B = new(big.Int).SetBytes(pbIPP.B)
+
+	return ipp, nil
+}
+
+// Serialize encodes the argument as a base58 protobuf InnerProductProof, with
+// the points encoded for the group of EC
+func (ipp *InnerProdArg) Serialize() (string, error) {
+	return ipp.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams is Serialize with the points encoded for the group of ec
+func (ipp *InnerProdArg) SerializeWithParams(ec *CryptoParams) (string, error) {
+	w := &pbWriter{g: ec.group()}
+	pbIPP := ipp.pb(w)
+	if w.err != nil {
+		return "", w.err
+	}
+	serialIPP, err := proto.Marshal(pbIPP)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(serialIPP), nil
+}
+
+// Rebuild decodes an argument made by Serialize
+func (ipp *InnerProdArg) Rebuild(encodedIPP string) error {
+	return ipp.RebuildWithParams(&EC, encodedIPP)
+}
+
+// RebuildWithParams decodes an argument made by SerializeWithParams(ec)
+func (ipp *InnerProdArg) RebuildWithParams(ec *CryptoParams, encodedIPP string) error {
+	bIPP := base58.Decode(encodedIPP)
+	if len(bIPP) == 0 {
+		return fmt.Errorf("%w: empty or invalid base58", ErrMalformedProof)
+	}
+
+	pbIPP := &pb.InnerProductProof{}
+	if err := proto.Unmarshal(bIPP, pbIPP); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+
+	rebuilt, err := rebuildIPP(ec.group(), pbIPP)
+	if err != nil {
+		return err
+	}
+	*ipp = rebuilt
+	return nil
+}
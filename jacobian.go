@@ -0,0 +1,315 @@
+package bp_go
+
+import "math/big"
+
+/*
+jacobianPoint - a secp256k1 point in Jacobian coordinates
+
+(X, Y, Z) stands for the affine point (X/Z^2, Y/Z^3), and Z = 0 is the point
+at infinity. Additions and doublings need no field inversion, so sums of many
+points are accumulated in this form and only converted back to an ECPoint at
+the end.
+*/
+type jacobianPoint struct {
+	x, y, z fieldElement
+}
+
+// toJacobian converts an affine ECPoint, mapping (0, 0) to infinity
+func toJacobian(p ECPoint) jacobianPoint {
+	var r jacobianPoint
+	if p.X == nil || p.Y == nil || (p.X.Sign() == 0 && p.Y.Sign() == 0) {
+		return r
+	}
+	r.x.setBig(p.X)
+	r.y.setBig(p.Y)
+	r.z = fieldOne
+	return r
+}
+
+// isInfinity reports whether p is the point at infinity
+func (p *jacobianPoint) isInfinity() bool {
+	return p.z.isZero()
+}
+
+// affine converts p back to an ECPoint, with infinity as (0, 0)
+func (p *jacobianPoint) affine() ECPoint {
+	if p.isInfinity() {
+		return ECPoint{new(big.Int), new(big.Int)}
+	}
+	var zinv, zinv2, x, y fieldElement
+	zinv.inverse(&p.z)
+	zinv2.square(&zinv)
+	x.mul(&p.x, &zinv2)
+	y.mul(&p.y, &zinv2)
+	y.mul(&y, &zinv)
+	return ECPoint{x.big(), y.big()}
+}
+
+// double sets r to 2p using dbl-2009-l for a = 0 curves
+func (r *jacobianPoint) double(p *jacobianPoint) *jacobianPoint {
+	if p.isInfinity() || p.y.isZero() {
+		*r = jacobianPoint{}
+		return r
+	}
+	var a, b, c, d, e, f, t fieldElement
+	a.square(&p.x)
+	b.square(&p.y)
+	c.square(&b)
+
+	// D = 2((X + B)^2 - A - C)
+	d.add(&p.x, &b)
+	d.square(&d)
+	d.sub(&d, &a)
+	d.sub(&d, &c)
+	d.double(&d)
+
+	// E = 3A, F = E^2
+	e.double(&a)
+	e.add(&e, &a)
+	f.square(&e)
+
+	// Z3 = 2 Y Z, computed first as r may alias p
+	var z3 fieldElement
+	z3.mul(&p.y, &p.z)
+	z3.double(&z3)
+
+	// X3 = F - 2D
+	var x3 fieldElement
+	t.double(&d)
+	x3.sub(&f, &t)
+
+	// Y3 = E (D - X3) - 8C
+	var y3 fieldElement
+	y3.sub(&d, &x3)
+	y3.mul(&e, &y3)
+	c.double(&c)
+	c.double(&c)
+	c.double(&c)
+	y3.sub(&y3, &c)
+
+	r.x, r.y, r.z = x3, y3, z3
+	return r
+}
+
+// add sets r to p + q using add-2007-bl
+func (r *jacobianPoint) add(p, q *jacobianPoint) *jacobianPoint {
+	if p.isInfinity() {
+		*r = *q
+		return r
+	}
+	if q.isInfinity() {
+		*r = *p
+		return r
+	}
+
+	var z1z1, z2z2, u1, u2, s1, s2, h, i, j, rr, v fieldElement
+	z1z1.square(&p.z)
+	z2z2.square(&q.z)
+	u1.mul(&p.x, &z2z2)
+	u2.mul(&q.x, &z1z1)
+	s1.mul(&p.y, &q.z)
+	s1.mul(&s1, &z2z2)
+	s2.mul(&q.y, &p.z)
+	s2.mul(&s2, &z1z1)
+
+	h.sub(&u2, &u1)
+	rr.sub(&s2, &s1)
+	if h.isZero() {
+		if rr.isZero() {
+			return r.double(p)
+		}
+		*r = jacobianPoint{}
+		return r
+	}
+	rr.double(&rr)
+
+	// I = (2H)^2, J = H I, V = U1 I
+	i.double(&h)
+	i.square(&i)
+	j.mul(&h, &i)
+	v.mul(&u1, &i)
+
+	// Z3 = ((Z1 + Z2)^2 - Z1Z1 - Z2Z2) H
+	var z3 fieldElement
+	z3.add(&p.z, &q.z)
+	z3.square(&z3)
+	z3.sub(&z3, &z1z1)
+	z3.sub(&z3, &z2z2)
+	z3.mul(&z3, &h)
+
+	// X3 = r^2 - J - 2V
+	var x3, t fieldElement
+	x3.square(&rr)
+	x3.sub(&x3, &j)
+	t.double(&v)
+	x3.sub(&x3, &t)
+
+	// Y3 = r (V - X3) - 2 S1 J
+	var y3 fieldElement
+	y3.sub(&v, &x3)
+	y3.mul(&rr, &y3)
+	t.mul(&s1, &j)
+	t.double(&t)
+	y3.sub(&y3, &t)
+
+	r.x, r.y, r.z = x3, y3, z3
+	return r
+}
+
+// neg sets r to -p
+func (r *jacobianPoint) neg(p *jacobianPoint) *jacobianPoint {
+	r.x = p.x
+	r.y.neg(&p.y)
+	r.z = p.z
+	return r
+}
+
+// addAffine sets r to p + q where q has Z = 1, using madd-2007-bl
+func (r *jacobianPoint) addAffine(p, q *jacobianPoint) *jacobianPoint {
+	if p.isInfinity() {
+		*r = *q
+		return r
+	}
+	if q.isInfinity() {
+		*r = *p
+		return r
+	}
+
+	var z1z1, u2, s2, h, hh, i, j, rr, v fieldElement
+	z1z1.square(&p.z)
+	u2.mul(&q.x, &z1z1)
+	s2.mul(&q.y, &p.z)
+	s2.mul(&s2, &z1z1)
+
+	h.sub(&u2, &p.x)
+	rr.sub(&s2, &p.y)
+	if h.isZero() {
+		if rr.isZero() {
+			return r.double(p)
+		}
+		*r = jacobianPoint{}
+		return r
+	}
+	rr.double(&rr)
+
+	// HH = H^2, I = 4HH, J = H I, V = X1 I
+	hh.square(&h)
+	i.double(&hh)
+	i.double(&i)
+	j.mul(&h, &i)
+	v.mul(&p.x, &i)
+
+	// Z3 = (Z1 + H)^2 - Z1Z1 - HH
+	var z3 fieldElement
+	z3.add(&p.z, &h)
+	z3.square(&z3)
+	z3.sub(&z3, &z1z1)
+	z3.sub(&z3, &hh)
+
+	// X3 = r^2 - J - 2V
+	var x3, t fieldElement
+	x3.square(&rr)
+	x3.sub(&x3, &j)
+	t.double(&v)
+	x3.sub(&x3, &t)
+
+	// Y3 = r (V - X3) - 2 Y1 J
+	var y3 fieldElement
+	y3.sub(&v, &x3)
+	y3.mul(&rr, &y3)
+	t.mul(&p.y, &j)
+	t.double(&t)
+	y3.sub(&y3, &t)
+
+	r.x, r.y, r.z = x3, y3, z3
+	return r
+}
+
+// mul sets r to k*p with 4 bit windows, where p has Z = 1. k must be public.
+func (r *jacobianPoint) mul(p *jacobianPoint, k *scalarLimbs) *jacobianPoint {
+	var table [16]jacobianPoint
+	table[1] = *p
+	for d := 2; d < 16; d++ {
+		table[d].addAffine(&table[d-1], p)
+	}
+
+	var acc jacobianPoint
+	for pos := 252; pos >= 0; pos -= 4 {
+		for i := 0; i < 4; i++ {
+			acc.double(&acc)
+		}
+		if d := k.window(uint(pos), 4); d != 0 {
+			acc.add(&acc, &table[d])
+		}
+	}
+	*r = acc
+	return r
+}
+
+// normalizeBatch rescales every point to Z = 1 with a single field inversion,
+// using Montgomery's trick. Points at infinity are left alone.
+func normalizeBatch(points []jacobianPoint) {
+	prods := make([]fieldElement, len(points))
+	acc := fieldOne
+	for i := range points {
+		prods[i] = acc
+		if !points[i].isInfinity() {
+			acc.mul(&acc, &points[i].z)
+		}
+	}
+
+	var inv fieldElement
+	inv.inverse(&acc)
+	for i := len(points) - 1; i >= 0; i-- {
+		p := &points[i]
+		if p.isInfinity() {
+			continue
+		}
+		// inv is 1 / (z_0 ... z_i) here
+		var zinv, zinv2 fieldElement
+		zinv.mul(&inv, &prods[i])
+		inv.mul(&inv, &p.z)
+
+		zinv2.square(&zinv)
+		p.x.mul(&p.x, &zinv2)
+		p.y.mul(&p.y, &zinv2)
+		p.y.mul(&p.y, &zinv)
+		p.z = fieldOne
+	}
+}
+
+// toJacobianSlice converts a slice of ECPoints
+func toJacobianSlice(points []ECPoint) []jacobianPoint {
+	r := make([]jacobianPoint, len(points))
+	for i := range points {
+		r[i] = toJacobian(points[i])
+	}
+	return r
+}
+
+// toAffineSlice converts points back to ECPoints with one batched inversion
+func toAffineSlice(points []jacobianPoint) []ECPoint {
+	norm := append([]jacobianPoint{}, points...)
+	normalizeBatch(norm)
+
+	r := make([]ECPoint, len(norm))
+	for i := range norm {
+		if norm[i].isInfinity() {
+			r[i] = ECPoint{new(big.Int), new(big.Int)}
+			continue
+		}
+		r[i] = ECPoint{norm[i].x.big(), norm[i].y.big()}
+	}
+	return r
+}
+
+// foldGenerators returns xlo*lo[i] + xhi*hi[i] for every i, with Z = 1
+func foldGenerators(lo, hi []jacobianPoint, xlo, xhi *big.Int) []jacobianPoint {
+	s := []scalarLimbs{toScalarLimbs(xlo), toScalarLimbs(xhi)}
+	r := make([]jacobianPoint, len(lo))
+	for i := range r {
+		r[i] = straus([]jacobianPoint{lo[i], hi[i]}, s)
+	}
+	normalizeBatch(r)
+	return r
+}
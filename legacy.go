@@ -0,0 +1,149 @@
+package bp_go
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+/*
+Verifying proofs made before the Fiat-Shamir transcript
+
+Earlier versions took every challenge from SHA-256 of the decimal
+coordinates of a few points, with nothing else hashed: y from A, z from S, x
+from T1 and T2, the scaling of U from the commitment P of the inner product
+argument, and the challenge of each round from its L and R. Proofs they
+serialised only verify with those challenges and with the generators of
+NewLegacyECPrimeGroupKey, so neither MRPVerify nor the legacy params alone
+can check them. LegacyRPVerify and LegacyMRPVerify replay the old challenges
+for that. There is no legacy prover, since its challenges do not bind the
+statement.
+*/
+
+// legacyChallenge - SHA-256 of the decimal X and Y of every point, mod N
+func (ec *CryptoParams) legacyChallenge(points ...ECPoint) *big.Int {
+	var s string
+	for _, p := range points {
+		s += p.X.String() + p.Y.String()
+	}
+	digest := sha256.Sum256([]byte(s))
+	return new(big.Int).Mod(new(big.Int).SetBytes(digest[:]), ec.N)
+}
+
+// LegacyRPVerify verifies a range proof serialised by a version before the
+// Fiat-Shamir transcript against the commitment comm. ec must come from
+// NewLegacyECPrimeGroupKey with the vector length the proof was made with.
+func (ec *CryptoParams) LegacyRPVerify(comm *ECPoint, rp *RangeProof) (bool, error) {
+	if comm == nil || rp == nil {
+		return false, fmt.Errorf("%w: missing commitment or proof", ErrMalformedProof)
+	}
+	return ec.LegacyMRPVerify(rp.multi(), []ECPoint{*comm})
+}
+
+/*
+LegacyMRPVerify verifies an aggregate range proof serialised by a version
+before the Fiat-Shamir transcript, which splits the vector length of ec
+evenly between the values of comms. ec must come from
+NewLegacyECPrimeGroupKey with the vector length the proof was made with.
+
+It checks the same two equations as the old verifier did,
+
+	t G + tau H = sum_j z^(2+j) V_j + delta(y, z) G + x T1 + x^2 T2
+	P + w t U + sum_j (x_j^2 L_j + x_j^-2 R_j) = sum_i (a s_i G_i + b s_i^-1 y^-i H_i) + w ab U
+
+where P = A + x S - mu H + sum_i (-z G_i + (z + z^(2+j) 2^(i mod n) y^-i) H_i),
+each with one multi-scalar multiplication.
+*/
+func (ec *CryptoParams) LegacyMRPVerify(mrp *MultiRangeProof, comms []ECPoint) (bool, error) {
+	if mrp == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	m := len(comms)
+	size := len(ec.BPG)
+	if m == 0 || size%m != 0 || !isPowerOfTwo(size) || len(ec.BPH) != size {
+		return false, lengthError("LegacyMRPVerify", size, m)
+	}
+	n := size / m
+	g := ec.group()
+	if err := checkPoints(g, comms...); err != nil {
+		return false, err
+	}
+	if err := mrp.check(g); err != nil {
+		return false, err
+	}
+	if err := mrp.IPP.check(g, size); err != nil {
+		return false, err
+	}
+	N := ec.N
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, N) }
+
+	cy := ec.legacyChallenge(mrp.A)
+	cz := ec.legacyChallenge(mrp.S)
+	cx := ec.legacyChallenge(mrp.T1, mrp.T2)
+	cx2 := mod(new(big.Int).Mul(cx, cx))
+	zPowers := ec.PowerVector(m+2, cz)[2:]
+
+	// (t - delta) G + tau H - sum_j z^(2+j) V_j - x T1 - x^2 T2
+	delta := ec.DeltaMRP(ec.PowerVector(size, cy), cz, m)
+	points := append([]ECPoint{ec.G, ec.H, mrp.T1, mrp.T2}, comms...)
+	scalars := []*big.Int{
+		mod(new(big.Int).Sub(mrp.Th, delta)), mrp.Tau,
+		mod(new(big.Int).Neg(cx)), mod(new(big.Int).Neg(cx2))}
+	for j := range comms {
+		scalars = append(scalars, mod(new(big.Int).Neg(zPowers[j])))
+	}
+	if sum := ec.msm(points, scalars); sum.X.Sign() != 0 || sum.Y.Sign() != 0 {
+		return false, nil
+	}
+
+	// P, with its scalars on H_i already divided by y^i
+	yInv := ec.PowerVector(size, new(big.Int).ModInverse(cy, N))
+	gScalars := make([]*big.Int, size)
+	hScalars := make([]*big.Int, size)
+	for j := 0; j < m; j++ {
+		zTwo := new(big.Int).Set(zPowers[j])
+		for i := j * n; i < (j+1)*n; i++ {
+			gScalars[i] = mod(new(big.Int).Neg(cz))
+			h := new(big.Int).Mul(zTwo, yInv[i])
+			hScalars[i] = mod(h.Add(h, cz))
+			zTwo = mod(zTwo.Lsh(zTwo, 1))
+		}
+	}
+	points = append(append([]ECPoint{mrp.A, mrp.S, ec.H}, ec.BPG...), ec.BPH...)
+	scalars = append(append([]*big.Int{big.NewInt(1), cx, mod(new(big.Int).Neg(mrp.Mu))}, gScalars...), hScalars...)
+	P := ec.msm(points, scalars)
+
+	ipp := &mrp.IPP
+	w := ec.legacyChallenge(P)
+	challenges := make([]*big.Int, len(ipp.L))
+	for j := range challenges {
+		challenges[j] = ec.legacyChallenge(ipp.L[j], ipp.R[j])
+		if challenges[j].Sign() == 0 {
+			return false, nil
+		}
+	}
+	inverses := ec.batchInvert(challenges)
+	squares := make([]*big.Int, len(challenges))
+	invSquares := make([]*big.Int, len(challenges))
+	for j := range challenges {
+		squares[j] = mod(new(big.Int).Mul(challenges[j], challenges[j]))
+		invSquares[j] = mod(new(big.Int).Mul(inverses[j], inverses[j]))
+	}
+	s, sInv := ec.ipaSVector(challenges, inverses, squares)
+
+	// P + w (t - ab) U + sum_j (x_j^2 L_j + x_j^-2 R_j)
+	//   - sum_i (a s_i G_i + b s_i^-1 y^-i H_i)
+	ab := new(big.Int).Mul(ipp.A, ipp.B)
+	points = append(append(append([]ECPoint{P, ec.U}, ipp.L...), ipp.R...), append(ec.BPG[:size:size], ec.BPH...)...)
+	scalars = []*big.Int{big.NewInt(1), mod(new(big.Int).Mul(w, new(big.Int).Sub(mrp.Th, ab)))}
+	scalars = append(append(scalars, squares...), invSquares...)
+	for i := 0; i < size; i++ {
+		scalars = append(scalars, mod(new(big.Int).Neg(new(big.Int).Mul(ipp.A, s[i]))))
+	}
+	for i := 0; i < size; i++ {
+		h := new(big.Int).Mul(ipp.B, sInv[i])
+		scalars = append(scalars, mod(h.Neg(h.Mul(h, yInv[i]))))
+	}
+	sum := ec.msm(points, scalars)
+	return sum.X.Sign() == 0 && sum.Y.Sign() == 0, nil
+}
@@ -0,0 +1,715 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/decred/base58"
+	"github.com/golang/protobuf/proto"
+	"github.com/peterdouglas/bp-go/pb"
+)
+
+/*
+Aggregated range proofs between parties
+
+Section 4.5 of the paper lets m parties, each holding one value, make a
+single MultiRangeProof with the help of a dealer that never learns their
+values or blinding factors. Party j uses generators jn to (j+1)n - 1 and the
+powers y^(jn+i) and z^(2+j), exactly as a single prover does for value j.
+
+	Party                         Dealer
+	BitCommitment  (V, A, S)   ->
+	                           <- BitChallenge  (y, z)
+	PolyCommitment (T1, T2)    ->
+	                           <- PolyChallenge (x)
+	ProofShare                 ->
+	                              MultiRangeProof
+
+The dealer sums the points and scalars of the parties, draws the challenges
+from the same transcript as mrpProve and runs the inner product argument over
+the concatenated l and r, so MRPVerify accepts the result. It checks every
+proof share against the commitments of its party first and reports the
+parties whose messages do not check out in a PartyError. The dealer plays
+the parties that pad the count to a power of two itself.
+
+Each type is one state of the protocol and its method returns the next
+state, so neither side can be run out of order. Every state can only be used
+once: a party answering two challenges with the same blinding factors would
+hand the dealer its bits, and a dealer taking two sets of commitments would
+mix them up.
+*/
+
+// BitCommitment - a party's commitments to its value and to its bits
+type BitCommitment struct {
+	V, A, S ECPoint
+}
+
+// BitChallenge - the challenges y and z from the dealer
+type BitChallenge struct {
+	Y, Z *big.Int
+}
+
+// PolyCommitment - a party's commitments to the coefficients of t(X)
+type PolyCommitment struct {
+	T1, T2 ECPoint
+}
+
+// PolyChallenge - the challenge x from the dealer
+type PolyChallenge struct {
+	X *big.Int
+}
+
+// ProofShare - a party's part of tau_x, mu and t hat, and its l(x) and r(x)
+type ProofShare struct {
+	Tau, Mu, Th *big.Int
+	L, R        []*big.Int
+}
+
+// Party - a party that has committed to its value, waiting for its position
+type Party struct {
+	ec      *CryptoParams
+	n       int
+	V       ECPoint
+	gamma   scalar
+	aL, aR  []scalar
+	pending bool
+}
+
+// PartyAwaitingBitChallenge - a party that has sent its BitCommitment
+type PartyAwaitingBitChallenge struct {
+	*Party
+	j          int
+	alpha, rho scalar
+	sL, sR     []scalar
+	pending    bool // shadows Party.pending
+}
+
+// PartyAwaitingPolyChallenge - a party that has sent its PolyCommitment
+type PartyAwaitingPolyChallenge struct {
+	*PartyAwaitingBitChallenge
+	z2j        scalar // z^(2+j)
+	tau1, tau2 scalar
+	l0, l1     []scalar
+	r0, r1     []scalar
+	pending    bool
+}
+
+// NewParty - a party proving that v, committed to with the blinding factor
+// gamma, fits in n bits
+func (ec *CryptoParams) NewParty(v, gamma *big.Int, n int) (*Party, error) {
+	if err := checkBitLength(n); err != nil {
+		return nil, err
+	}
+	if v == nil || gamma == nil {
+		return nil, fmt.Errorf("%w: missing value or blinding factor", ErrMissingAssignment)
+	}
+	comms, _, gs, aL, aR, err := ec.rangeProofBits([]*big.Int{v}, []*big.Int{gamma}, n, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &Party{ec: ec, n: n, V: comms[0], gamma: gs[0], aL: aL, aR: aR, pending: true}, nil
+}
+
+// AssignPosition sets the party's position j among the parties and returns
+// its commitments for the dealer. A Party can only take one position.
+func (p *Party) AssignPosition(j int) (*PartyAwaitingBitChallenge, BitCommitment, error) {
+	ec := p.ec
+	if !p.pending {
+		return nil, BitCommitment{}, fmt.Errorf("%w: party already has a position", ErrMaliciousDealer)
+	}
+	offset := j * p.n
+	if j < 0 || offset+p.n > len(ec.BPG) || offset+p.n > len(ec.BPH) {
+		return nil, BitCommitment{}, lengthError("AssignPosition", j, p.n, len(ec.BPG), len(ec.BPH))
+	}
+	p.pending = false
+
+	blinds, err := ec.fr.rands(2)
+	if err != nil {
+		return nil, BitCommitment{}, err
+	}
+	sL, err := ec.fr.rands(p.n)
+	if err != nil {
+		return nil, BitCommitment{}, err
+	}
+	sR, err := ec.fr.rands(p.n)
+	if err != nil {
+		return nil, BitCommitment{}, err
+	}
+
+	next := &PartyAwaitingBitChallenge{Party: p, j: j, alpha: blinds[0], rho: blinds[1], sL: sL, sR: sR, pending: true}
+	bc := BitCommitment{
+		V: p.V,
+		A: ec.ctCommitAt(nil, &next.alpha, offset, p.aL, p.aR),
+		S: ec.ctCommitAt(nil, &next.rho, offset, sL, sR),
+	}
+	return next, bc, nil
+}
+
+// challengeScalar checks a challenge from the dealer is present and not zero
+func (ec *CryptoParams) challengeScalar(name string, c *big.Int) (scalar, error) {
+	if c == nil || new(big.Int).Mod(c, ec.N).Sign() == 0 {
+		return scalar{}, fmt.Errorf("%w: challenge %s is zero or missing", ErrMaliciousDealer, name)
+	}
+	return ec.fr.fromBig(c), nil
+}
+
+// ApplyChallenge computes t1 and t2 for the challenges y and z and returns
+// the party's commitments to them. It only accepts one challenge.
+func (p *PartyAwaitingBitChallenge) ApplyChallenge(c BitChallenge) (*PartyAwaitingPolyChallenge, PolyCommitment, error) {
+	ec, n := p.ec, p.n
+	if !p.pending {
+		return nil, PolyCommitment{}, fmt.Errorf("%w: party already answered a bit challenge", ErrMaliciousDealer)
+	}
+	sy, err := ec.challengeScalar("y", c.Y)
+	if err != nil {
+		return nil, PolyCommitment{}, err
+	}
+	sz, err := ec.challengeScalar("z", c.Z)
+	if err != nil {
+		return nil, PolyCommitment{}, err
+	}
+	p.pending = false
+
+	// y^(jn+i), z^(2+j) and 2^i
+	yOffset := ec.fr.fromBig(new(big.Int).Exp(c.Y, big.NewInt(int64(p.j*n)), ec.N))
+	PowerOfCY := scalarPowers(n, &sy)
+	two := ec.fr.fromBig(big.NewInt(2))
+	PowerOfTwos := scalarPowers(n, &two)
+	next := &PartyAwaitingPolyChallenge{PartyAwaitingBitChallenge: p, pending: true}
+	next.z2j = ec.fr.fromBig(new(big.Int).Exp(c.Z, big.NewInt(int64(2+p.j)), ec.N))
+
+	// l(X) = aL - z + sL X, r(X) = y^(jn+i) o (aR + z + sR X) + z^(2+j) 2^i
+	next.l0 = make([]scalar, n)
+	next.l1 = p.sL
+	next.r0 = make([]scalar, n)
+	next.r1 = make([]scalar, n)
+	var tmp scalar
+	for i := 0; i < n; i++ {
+		PowerOfCY[i].mul(&PowerOfCY[i], &yOffset)
+		next.l0[i].sub(&p.aL[i], &sz)
+		next.r0[i].add(&p.aR[i], &sz)
+		next.r0[i].mul(&next.r0[i], &PowerOfCY[i])
+		tmp.mul(&next.z2j, &PowerOfTwos[i])
+		next.r0[i].add(&next.r0[i], &tmp)
+		next.r1[i].mul(&p.sR[i], &PowerOfCY[i])
+	}
+
+	t1 := scalarInnerProduct(next.l1, next.r0)
+	t1b := scalarInnerProduct(next.l0, next.r1)
+	t1.add(&t1, &t1b)
+	t2 := scalarInnerProduct(next.l1, next.r1)
+
+	taus, err := ec.fr.rands(2)
+	if err != nil {
+		return nil, PolyCommitment{}, err
+	}
+	next.tau1, next.tau2 = taus[0], taus[1]
+	pc := PolyCommitment{
+		T1: ec.ctCommit(&t1, &next.tau1, nil, nil),
+		T2: ec.ctCommit(&t2, &next.tau2, nil, nil),
+	}
+	return next, pc, nil
+}
+
+// ApplyChallenge evaluates the party's polynomials at x and returns its
+// share of the proof. It only accepts one challenge.
+func (p *PartyAwaitingPolyChallenge) ApplyChallenge(c PolyChallenge) (ProofShare, error) {
+	if !p.pending {
+		return ProofShare{}, fmt.Errorf("%w: party already answered a poly challenge", ErrMaliciousDealer)
+	}
+	sx, err := p.ec.challengeScalar("x", c.X)
+	if err != nil {
+		return ProofShare{}, err
+	}
+	p.pending = false
+	var sx2, tmp scalar
+	sx2.mul(&sx, &sx)
+
+	left := make([]scalar, p.n)
+	right := make([]scalar, p.n)
+	for i := range left {
+		left[i].mul(&p.l1[i], &sx)
+		left[i].add(&left[i], &p.l0[i])
+		right[i].mul(&p.r1[i], &sx)
+		right[i].add(&right[i], &p.r0[i])
+	}
+	that := scalarInnerProduct(left, right)
+
+	// tau_x = tau2 x^2 + tau1 x + z^(2+j) gamma, mu = alpha + rho x
+	var taux, mu scalar
+	taux.mul(&p.z2j, &p.gamma)
+	tmp.mul(&p.tau2, &sx2)
+	taux.add(&taux, &tmp)
+	tmp.mul(&p.tau1, &sx)
+	taux.add(&taux, &tmp)
+	mu.mul(&p.rho, &sx)
+	mu.add(&mu, &p.alpha)
+
+	return ProofShare{
+		Tau: taux.big(),
+		Mu:  mu.big(),
+		Th:  that.big(),
+		L:   scalarsBig(left),
+		R:   scalarsBig(right),
+	}, nil
+}
+
+// dealer - what the dealer has so far
+type dealer struct {
+	ec   *CryptoParams
+	n, m int // bits per value and number of parties, not counting padding
+	t    *Transcript
+
+	// the padding parties in each state
+	pad      []*PartyAwaitingBitChallenge
+	padPoly  []*PartyAwaitingPolyChallenge
+	padShare []ProofShare
+
+	bcs     []BitCommitment
+	pcs     []PolyCommitment
+	y, z, x *big.Int
+	A, S    ECPoint
+	T1, T2  ECPoint
+	done    bool // the proof has been put together
+}
+
+// DealerAwaitingBitCommitments - a dealer waiting for the BitCommitment of
+// every party
+type DealerAwaitingBitCommitments struct{ *dealer }
+
+// DealerAwaitingPolyCommitments - a dealer that has sent the BitChallenge
+type DealerAwaitingPolyCommitments struct{ *dealer }
+
+// DealerAwaitingProofShares - a dealer that has sent the PolyChallenge
+type DealerAwaitingProofShares struct{ *dealer }
+
+// NewDealer - a dealer for m parties proving n bit values
+func (ec *CryptoParams) NewDealer(n, m int) (*DealerAwaitingBitCommitments, error) {
+	if err := checkBitLength(n); err != nil {
+		return nil, err
+	}
+	if m < 1 {
+		return nil, lengthError("NewDealer", m)
+	}
+	if _, err := ec.proofSize(n, padCount(m)); err != nil {
+		return nil, err
+	}
+
+	d := &dealer{ec: ec, n: n, m: m}
+	for j := m; j < padCount(m); j++ {
+		p, err := ec.NewParty(big.NewInt(0), big.NewInt(0), n)
+		if err != nil {
+			return nil, err
+		}
+		next, bc, err := p.AssignPosition(j)
+		if err != nil {
+			return nil, err
+		}
+		d.pad = append(d.pad, next)
+		d.bcs = append(d.bcs, bc)
+	}
+	return &DealerAwaitingBitCommitments{d}, nil
+}
+
+// sumPoints returns the sum of points
+func (ec *CryptoParams) sumPoints(points []ECPoint) ECPoint {
+	ones := make([]*big.Int, len(points))
+	for i := range ones {
+		ones[i] = big.NewInt(1)
+	}
+	return ec.msm(points, ones)
+}
+
+// ReceiveBitCommitments takes the BitCommitment of every party, in order of
+// position, and returns the BitChallenge to send to all of them
+func (d *DealerAwaitingBitCommitments) ReceiveBitCommitments(bcs []BitCommitment) (*DealerAwaitingPolyCommitments, BitChallenge, error) {
+	if d.t != nil {
+		return nil, BitChallenge{}, fmt.Errorf("%w: dealer already received bit commitments", ErrStateReused)
+	}
+	if len(bcs) != d.m {
+		return nil, BitChallenge{}, lengthError("ReceiveBitCommitments", d.m, len(bcs))
+	}
+	var bad []int
+	for j := range bcs {
+		if checkPoints(d.ec.group(), bcs[j].V, bcs[j].A, bcs[j].S) != nil {
+			bad = append(bad, j)
+		}
+	}
+	if bad != nil {
+		return nil, BitChallenge{}, &PartyError{bad}
+	}
+	d.bcs = append(append([]BitCommitment{}, bcs...), d.bcs...)
+
+	comms := make([]ECPoint, len(d.bcs))
+	var As, Ss []ECPoint
+	for j, bc := range d.bcs {
+		comms[j] = bc.V
+		As = append(As, bc.A)
+		Ss = append(Ss, bc.S)
+	}
+	d.A, d.S = d.ec.sumPoints(As), d.ec.sumPoints(Ss)
+
+	d.t = d.ec.rangeProofTranscript(d.n, comms)
+	d.t.AppendPoint("A", d.A)
+	d.t.AppendPoint("S", d.S)
+	d.y = d.t.ChallengeScalar("y")
+	d.z = d.t.ChallengeScalar("z")
+	c := BitChallenge{d.y, d.z}
+
+	for _, p := range d.pad {
+		next, pc, err := p.ApplyChallenge(c)
+		if err != nil {
+			return nil, BitChallenge{}, err
+		}
+		d.padPoly = append(d.padPoly, next)
+		d.pcs = append(d.pcs, pc)
+	}
+	return &DealerAwaitingPolyCommitments{d.dealer}, c, nil
+}
+
+// ReceivePolyCommitments takes the PolyCommitment of every party and returns
+// the PolyChallenge to send to all of them
+func (d *DealerAwaitingPolyCommitments) ReceivePolyCommitments(pcs []PolyCommitment) (*DealerAwaitingProofShares, PolyChallenge, error) {
+	if d.x != nil {
+		return nil, PolyChallenge{}, fmt.Errorf("%w: dealer already received poly commitments", ErrStateReused)
+	}
+	if len(pcs) != d.m {
+		return nil, PolyChallenge{}, lengthError("ReceivePolyCommitments", d.m, len(pcs))
+	}
+	var bad []int
+	for j := range pcs {
+		if checkPoints(d.ec.group(), pcs[j].T1, pcs[j].T2) != nil {
+			bad = append(bad, j)
+		}
+	}
+	if bad != nil {
+		return nil, PolyChallenge{}, &PartyError{bad}
+	}
+	d.pcs = append(append([]PolyCommitment{}, pcs...), d.pcs...)
+
+	var T1s, T2s []ECPoint
+	for _, pc := range d.pcs {
+		T1s = append(T1s, pc.T1)
+		T2s = append(T2s, pc.T2)
+	}
+	d.T1, d.T2 = d.ec.sumPoints(T1s), d.ec.sumPoints(T2s)
+
+	d.t.AppendPoint("T1", d.T1)
+	d.t.AppendPoint("T2", d.T2)
+	d.x = d.t.ChallengeScalar("x")
+	c := PolyChallenge{d.x}
+
+	for _, p := range d.padPoly {
+		share, err := p.ApplyChallenge(c)
+		if err != nil {
+			return nil, PolyChallenge{}, err
+		}
+		d.padShare = append(d.padShare, share)
+	}
+	return &DealerAwaitingProofShares{d.dealer}, c, nil
+}
+
+/*
+checkShare checks the share of party j against its commitments, with the two
+equations of a range proof over its own generators weighted by a random w:
+
+	w (t G + tau_x H - z^(2+j) V - delta_j G - x T1 - x^2 T2) = 0
+	A + x S - <z + l, G_j> + <z + (z^(2+j) 2^i - r_i) y^-(jn+i), H_j> - mu H = 0
+
+where delta_j = (z - z^2) sum_i y^(jn+i) - z^(3+j) (2^n - 1).
+*/
+func (d *DealerAwaitingProofShares) checkShare(j int, s *ProofShare) bool {
+	ec, n := d.ec, d.n
+	if s.Tau == nil || s.Mu == nil || s.Th == nil || len(s.L) != n || len(s.R) != n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if s.L[i] == nil || s.R[i] == nil {
+			return false
+		}
+	}
+
+	mul := func(a ...*big.Int) *big.Int {
+		res := big.NewInt(1)
+		for _, v := range a {
+			res.Mod(res.Mul(res, v), ec.N)
+		}
+		return res
+	}
+	neg := func(a *big.Int) *big.Int {
+		return new(big.Int).Mod(new(big.Int).Neg(a), ec.N)
+	}
+
+	th := new(big.Int)
+	for i := 0; i < n; i++ {
+		th.Add(th, mul(s.L[i], s.R[i]))
+	}
+	if th.Mod(th, ec.N).Cmp(new(big.Int).Mod(s.Th, ec.N)) != 0 {
+		return false
+	}
+
+	w, err := ec.randScalar()
+	if err != nil {
+		return false
+	}
+	offset := j * n
+	yOffset := new(big.Int).Exp(d.y, big.NewInt(int64(offset)), ec.N)
+	PowersOfY := ec.ScalarVectorMul(ec.PowerVector(n, d.y), yOffset)
+	PowersOfYInv := ec.batchInvert(PowersOfY)
+	PowerOfTwos := ec.PowerVector(n, big.NewInt(2))
+	z2j := new(big.Int).Exp(d.z, big.NewInt(int64(2+j)), ec.N)
+	z2 := mul(d.z, d.z)
+	x2 := mul(d.x, d.x)
+
+	sumY := new(big.Int)
+	for _, y := range PowersOfY {
+		sumY.Add(sumY, y)
+	}
+	twoN := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n)), big.NewInt(1))
+	delta := new(big.Int).Sub(mul(new(big.Int).Sub(d.z, z2), sumY), mul(d.z, z2j, twoN))
+
+	bc, pc := d.bcs[j], d.pcs[j]
+	points := []ECPoint{bc.V, pc.T1, pc.T2, bc.A, bc.S, ec.G, ec.H}
+	scalars := []*big.Int{
+		neg(mul(w, z2j)), neg(mul(w, d.x)), neg(mul(w, x2)),
+		big.NewInt(1), d.x,
+		mul(w, new(big.Int).Sub(s.Th, delta)),
+		new(big.Int).Sub(mul(w, s.Tau), s.Mu),
+	}
+	for i := 0; i < n; i++ {
+		points = append(points, ec.BPG[offset+i])
+		scalars = append(scalars, neg(new(big.Int).Add(d.z, s.L[i])))
+	}
+	for i := 0; i < n; i++ {
+		hi := mul(new(big.Int).Sub(mul(z2j, PowerOfTwos[i]), s.R[i]), PowersOfYInv[i])
+		points = append(points, ec.BPH[offset+i])
+		scalars = append(scalars, hi.Add(hi, d.z))
+	}
+
+	sum := ec.msm(points, scalars)
+	return sum.X.Sign() == 0 && sum.Y.Sign() == 0
+}
+
+// ReceiveShares takes the ProofShare of every party and returns the
+// aggregated proof. If any share does not match the commitments of its party
+// the error is a PartyError naming every such party.
+func (d *DealerAwaitingProofShares) ReceiveShares(shares []ProofShare) (MultiRangeProof, error) {
+	ec := d.ec
+	if d.done {
+		return MultiRangeProof{}, fmt.Errorf("%w: dealer already made its proof", ErrStateReused)
+	}
+	if len(shares) != d.m {
+		return MultiRangeProof{}, lengthError("ReceiveShares", d.m, len(shares))
+	}
+	var bad []int
+	for j := range shares {
+		if !d.checkShare(j, &shares[j]) {
+			bad = append(bad, j)
+		}
+	}
+	if bad != nil {
+		return MultiRangeProof{}, &PartyError{bad}
+	}
+	shares = append(append([]ProofShare{}, shares...), d.padShare...)
+	d.done = true
+
+	tau, mu, th := new(big.Int), new(big.Int), new(big.Int)
+	var left, right []*big.Int
+	for _, s := range shares {
+		tau.Add(tau, s.Tau)
+		mu.Add(mu, s.Mu)
+		th.Add(th, s.Th)
+		left = append(left, s.L...)
+		right = append(right, s.R...)
+	}
+
+	mrp := MultiRangeProof{
+		Bits: d.n,
+		A:    d.A,
+		S:    d.S,
+		T1:   d.T1,
+		T2:   d.T2,
+		Tau:  tau.Mod(tau, ec.N),
+		Th:   th.Mod(th, ec.N),
+		Mu:   mu.Mod(mu, ec.N),
+	}
+	d.t.AppendScalar("tau_x", mrp.Tau)
+	d.t.AppendScalar("mu", mrp.Mu)
+	d.t.AppendScalar("t_hat", mrp.Th)
+	mrp.IPP = ec.rangeProofIPA(len(left), d.y).prove(d.t, ec.fr.fromBigs(left), ec.fr.fromBigs(right))
+	return mrp, nil
+}
+
+// encodePB marshals msg as base58 protobuf
+func encodePB(msg proto.Message) (string, error) {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(b), nil
+}
+
+// decodePB unmarshals base58 protobuf made by encodePB into msg
+func decodePB(encoded string, msg proto.Message) error {
+	b := base58.Decode(encoded)
+	if len(b) == 0 {
+		return fmt.Errorf("%w: empty or invalid base58", ErrMalformedProof)
+	}
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	return nil
+}
+
+// Serialize - encodes the message as base58 protobuf, with the points
+// encoded for the group of EC
+func (bc *BitCommitment) Serialize() (string, error) {
+	return bc.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (bc *BitCommitment) SerializeWithParams(ec *CryptoParams) (string, error) {
+	w := &pbWriter{g: ec.group()}
+	m := &pb.BitCommitment{
+		V: w.point("V", bc.V),
+		A: w.point("A", bc.A),
+		S: w.point("S", bc.S),
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+	return encodePB(m)
+}
+
+// Rebuild - decodes a message made by Serialize
+func (bc *BitCommitment) Rebuild(encoded string) error {
+	return bc.RebuildWithParams(&EC, encoded)
+}
+
+// RebuildWithParams - decodes a message made by SerializeWithParams(ec)
+func (bc *BitCommitment) RebuildWithParams(ec *CryptoParams, encoded string) error {
+	m := &pb.BitCommitment{}
+	if err := decodePB(encoded, m); err != nil {
+		return err
+	}
+	g := ec.group()
+	if err := rebuildPoint(g, &bc.V, m.V); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &bc.A, m.A); err != nil {
+		return err
+	}
+	return rebuildPoint(g, &bc.S, m.S)
+}
+
+// Serialize - encodes the message as base58 protobuf
+func (c *BitChallenge) Serialize() (string, error) {
+	w := &pbWriter{}
+	m := &pb.BitChallenge{Y: w.scalar("y", c.Y), Z: w.scalar("z", c.Z)}
+	if w.err != nil {
+		return "", w.err
+	}
+	return encodePB(m)
+}
+
+// Rebuild - decodes a message made by Serialize
+func (c *BitChallenge) Rebuild(encoded string) error {
+	m := &pb.BitChallenge{}
+	if err := decodePB(encoded, m); err != nil {
+		return err
+	}
+	c.Y = new(big.Int).SetBytes(m.Y)
+	c.Z = new(big.Int).SetBytes(m.Z)
+	return nil
+}
+
+// Serialize - encodes the message as base58 protobuf, with the points
+// encoded for the group of EC
+func (pc *PolyCommitment) Serialize() (string, error) {
+	return pc.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (pc *PolyCommitment) SerializeWithParams(ec *CryptoParams) (string, error) {
+	w := &pbWriter{g: ec.group()}
+	m := &pb.PolyCommitment{
+		T1: w.point("T1", pc.T1),
+		T2: w.point("T2", pc.T2),
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+	return encodePB(m)
+}
+
+// Rebuild - decodes a message made by Serialize
+func (pc *PolyCommitment) Rebuild(encoded string) error {
+	return pc.RebuildWithParams(&EC, encoded)
+}
+
+// RebuildWithParams - decodes a message made by SerializeWithParams(ec)
+func (pc *PolyCommitment) RebuildWithParams(ec *CryptoParams, encoded string) error {
+	m := &pb.PolyCommitment{}
+	if err := decodePB(encoded, m); err != nil {
+		return err
+	}
+	if err := rebuildPoint(ec.group(), &pc.T1, m.T1); err != nil {
+		return err
+	}
+	return rebuildPoint(ec.group(), &pc.T2, m.T2)
+}
+
+// Serialize - encodes the message as base58 protobuf
+func (c *PolyChallenge) Serialize() (string, error) {
+	w := &pbWriter{}
+	m := &pb.PolyChallenge{X: w.scalar("x", c.X)}
+	if w.err != nil {
+		return "", w.err
+	}
+	return encodePB(m)
+}
+
+// Rebuild - decodes a message made by Serialize
+func (c *PolyChallenge) Rebuild(encoded string) error {
+	m := &pb.PolyChallenge{}
+	if err := decodePB(encoded, m); err != nil {
+		return err
+	}
+	c.X = new(big.Int).SetBytes(m.X)
+	return nil
+}
+
+// Serialize - encodes the message as base58 protobuf
+func (s *ProofShare) Serialize() (string, error) {
+	w := &pbWriter{}
+	m := &pb.ProofShare{Tau: w.scalar("tau_x", s.Tau), Mu: w.scalar("mu", s.Mu), Th: w.scalar("t_hat", s.Th)}
+	for i := range s.L {
+		m.L = append(m.L, w.scalar("l", s.L[i]))
+	}
+	for i := range s.R {
+		m.R = append(m.R, w.scalar("r", s.R[i]))
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+	return encodePB(m)
+}
+
+// Rebuild - decodes a message made by Serialize
+func (s *ProofShare) Rebuild(encoded string) error {
+	m := &pb.ProofShare{}
+	if err := decodePB(encoded, m); err != nil {
+		return err
+	}
+	s.Tau = new(big.Int).SetBytes(m.Tau)
+	s.Mu = new(big.Int).SetBytes(m.Mu)
+	s.Th = new(big.Int).SetBytes(m.Th)
+	s.L, s.R = nil, nil
+	for _, b := range m.L {
+		s.L = append(s.L, new(big.Int).SetBytes(b))
+	}
+	for _, b := range m.R {
+		s.R = append(s.R, new(big.Int).SetBytes(b))
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package bp_go
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// strausThreshold - below this many points Straus' method beats Pippenger's
+const strausThreshold = 128
+
+/*
+MultiScalarMul - Multi-Scalar Multiplication
+
+Returns the sum of scalars[i] * points[i] on secp256k1. Scalars are reduced
+mod the group order and (0, 0) is taken to be the point at infinity. Other
+groups have their own Group.MultiScalarMul.
+
+Small inputs use Straus' method: a table of the first 15 multiples of each
+point and one shared chain of doublings over 4 bit windows of the scalars.
+Large inputs use Pippenger's bucket method, which sorts the points into
+buckets by window value so each point costs one addition per window.
+*/
+func MultiScalarMul(points []ECPoint, scalars []*big.Int) (ECPoint, error) {
+	if len(points) != len(scalars) {
+		return ECPoint{}, lengthError("MultiScalarMul", len(points), len(scalars))
+	}
+	for _, s := range scalars {
+		if s == nil {
+			return ECPoint{}, lengthError("MultiScalarMul", len(points), len(scalars))
+		}
+	}
+	return multiScalarMul(points, scalars), nil
+}
+
+func multiScalarMul(points []ECPoint, scalars []*big.Int) ECPoint {
+	jp := make([]jacobianPoint, 0, len(points))
+	ss := make([]scalarLimbs, 0, len(points))
+	for i := range points {
+		s := toScalarLimbs(scalars[i])
+		if s.isZero() {
+			continue
+		}
+		jp = append(jp, toJacobian(points[i]))
+		ss = append(ss, s)
+	}
+
+	r := msmJacobian(jp, ss)
+	return r.affine()
+}
+
+// msmJacobian picks the multi-scalar multiplication method by input size.
+// The points must have Z = 1 or be the point at infinity.
+func msmJacobian(points []jacobianPoint, scalars []scalarLimbs) jacobianPoint {
+	if len(points) < strausThreshold {
+		return straus(points, scalars)
+	}
+	return pippenger(points, scalars)
+}
+
+// scalarLimbs - a scalar reduced mod N in four little endian 64 bit limbs
+type scalarLimbs [4]uint64
+
+func toScalarLimbs(s *big.Int) scalarLimbs {
+	var buf [32]byte
+	new(big.Int).Mod(s, curve.N).FillBytes(buf[:])
+	var r scalarLimbs
+	for i := 0; i < 4; i++ {
+		r[i] = beUint64(buf[32-8*(i+1):])
+	}
+	return r
+}
+
+// toScalarLimbsSlice converts every scalar of s
+func toScalarLimbsSlice(s []*big.Int) []scalarLimbs {
+	r := make([]scalarLimbs, len(s))
+	for i := range s {
+		r[i] = toScalarLimbs(s[i])
+	}
+	return r
+}
+
+func (s *scalarLimbs) isZero() bool {
+	return s[0]|s[1]|s[2]|s[3] == 0
+}
+
+// window returns the w bits of s starting at bit pos
+func (s *scalarLimbs) window(pos, w uint) uint {
+	limb := pos / 64
+	if limb >= 4 {
+		return 0
+	}
+	off := pos % 64
+	v := s[limb] >> off
+	if off+w > 64 && limb+1 < 4 {
+		v |= s[limb+1] << (64 - off)
+	}
+	return uint(v & (1<<w - 1))
+}
+
+// straus computes the sum with 4 bit windows and per point tables
+func straus(points []jacobianPoint, scalars []scalarLimbs) jacobianPoint {
+	tables := make([][16]jacobianPoint, len(points))
+	for i := range points {
+		tables[i][1] = points[i]
+		for d := 2; d < 16; d++ {
+			tables[i][d].add(&tables[i][d-1], &points[i])
+		}
+	}
+
+	var acc jacobianPoint
+	for pos := 252; pos >= 0; pos -= 4 {
+		for k := 0; k < 4; k++ {
+			acc.double(&acc)
+		}
+		for i := range points {
+			if d := scalars[i].window(uint(pos), 4); d != 0 {
+				acc.add(&acc, &tables[i][d])
+			}
+		}
+	}
+	return acc
+}
+
+// pippengerWindow returns the bucket window size for n points
+func pippengerWindow(n int) uint {
+	c := bits.Len(uint(n)) - 4
+	if c < 4 {
+		c = 4
+	}
+	if c > 16 {
+		c = 16
+	}
+	return uint(c)
+}
+
+// pippenger computes the sum with the bucket method. The points must have
+// Z = 1 or be the point at infinity.
+func pippenger(points []jacobianPoint, scalars []scalarLimbs) jacobianPoint {
+	c := pippengerWindow(len(points))
+	windows := (256 + int(c) - 1) / int(c)
+	buckets := make([]jacobianPoint, 1<<c-1)
+
+	var acc jacobianPoint
+	for w := windows - 1; w >= 0; w-- {
+		for k := uint(0); k < c; k++ {
+			acc.double(&acc)
+		}
+
+		for i := range buckets {
+			buckets[i] = jacobianPoint{}
+		}
+		for i := range points {
+			if d := scalars[i].window(uint(w)*c, c); d != 0 {
+				buckets[d-1].addAffine(&buckets[d-1], &points[i])
+			}
+		}
+
+		// sum_d d * bucket_d, as a running sum from the top bucket down
+		var running, sum jacobianPoint
+		for d := len(buckets) - 1; d >= 0; d-- {
+			running.add(&running, &buckets[d])
+			sum.add(&sum, &running)
+		}
+		acc.add(&acc, &sum)
+	}
+	return acc
+}
@@ -13,6 +13,14 @@ It has these top-level messages:
 	InnerProductProof
 	RangeProof
 	MultiRangeProof
+	WeightedInnerProductProof
+	RangeProofPlus
+	R1CSProof
+	BitCommitment
+	BitChallenge
+	PolyCommitment
+	PolyChallenge
+	ProofShare
 */
 package pb
 
@@ -128,14 +136,15 @@ func (m *InnerProductProof) GetB() []byte {
 }
 
 type RangeProof struct {
-	A   *ECPoint           `protobuf:"bytes,2,opt,name=A" json:"A,omitempty"`
-	S   *ECPoint           `protobuf:"bytes,3,opt,name=S" json:"S,omitempty"`
-	T1  *ECPoint           `protobuf:"bytes,4,opt,name=T1" json:"T1,omitempty"`
-	T2  *ECPoint           `protobuf:"bytes,5,opt,name=T2" json:"T2,omitempty"`
-	Tau []byte             `protobuf:"bytes,6,opt,name=Tau,proto3" json:"Tau,omitempty"`
-	Th  []byte             `protobuf:"bytes,7,opt,name=Th,proto3" json:"Th,omitempty"`
-	Mu  []byte             `protobuf:"bytes,8,opt,name=Mu,proto3" json:"Mu,omitempty"`
-	IPP *InnerProductProof `protobuf:"bytes,9,opt,name=IPP" json:"IPP,omitempty"`
+	A    *ECPoint           `protobuf:"bytes,2,opt,name=A" json:"A,omitempty"`
+	S    *ECPoint           `protobuf:"bytes,3,opt,name=S" json:"S,omitempty"`
+	T1   *ECPoint           `protobuf:"bytes,4,opt,name=T1" json:"T1,omitempty"`
+	T2   *ECPoint           `protobuf:"bytes,5,opt,name=T2" json:"T2,omitempty"`
+	Tau  []byte             `protobuf:"bytes,6,opt,name=Tau,proto3" json:"Tau,omitempty"`
+	Th   []byte             `protobuf:"bytes,7,opt,name=Th,proto3" json:"Th,omitempty"`
+	Mu   []byte             `protobuf:"bytes,8,opt,name=Mu,proto3" json:"Mu,omitempty"`
+	IPP  *InnerProductProof `protobuf:"bytes,9,opt,name=IPP" json:"IPP,omitempty"`
+	Bits uint32             `protobuf:"varint,10,opt,name=Bits,proto3" json:"Bits,omitempty"`
 }
 
 func (m *RangeProof) Reset()                    { *m = RangeProof{} }
@@ -199,15 +208,23 @@ func (m *RangeProof) GetIPP() *InnerProductProof {
 	return nil
 }
 
+func (m *RangeProof) GetBits() uint32 {
+	if m != nil {
+		return m.Bits
+	}
+	return 0
+}
+
 type MultiRangeProof struct {
-	A   *ECPoint           `protobuf:"bytes,2,opt,name=A" json:"A,omitempty"`
-	S   *ECPoint           `protobuf:"bytes,3,opt,name=S" json:"S,omitempty"`
-	T1  *ECPoint           `protobuf:"bytes,4,opt,name=T1" json:"T1,omitempty"`
-	T2  *ECPoint           `protobuf:"bytes,5,opt,name=T2" json:"T2,omitempty"`
-	Tau []byte             `protobuf:"bytes,8,opt,name=Tau,proto3" json:"Tau,omitempty"`
-	Th  []byte             `protobuf:"bytes,9,opt,name=Th,proto3" json:"Th,omitempty"`
-	Mu  []byte             `protobuf:"bytes,10,opt,name=Mu,proto3" json:"Mu,omitempty"`
-	IPP *InnerProductProof `protobuf:"bytes,11,opt,name=IPP" json:"IPP,omitempty"`
+	A    *ECPoint           `protobuf:"bytes,2,opt,name=A" json:"A,omitempty"`
+	S    *ECPoint           `protobuf:"bytes,3,opt,name=S" json:"S,omitempty"`
+	T1   *ECPoint           `protobuf:"bytes,4,opt,name=T1" json:"T1,omitempty"`
+	T2   *ECPoint           `protobuf:"bytes,5,opt,name=T2" json:"T2,omitempty"`
+	Tau  []byte             `protobuf:"bytes,8,opt,name=Tau,proto3" json:"Tau,omitempty"`
+	Th   []byte             `protobuf:"bytes,9,opt,name=Th,proto3" json:"Th,omitempty"`
+	Mu   []byte             `protobuf:"bytes,10,opt,name=Mu,proto3" json:"Mu,omitempty"`
+	IPP  *InnerProductProof `protobuf:"bytes,11,opt,name=IPP" json:"IPP,omitempty"`
+	Bits uint32             `protobuf:"varint,12,opt,name=Bits,proto3" json:"Bits,omitempty"`
 }
 
 func (m *MultiRangeProof) Reset()                    { *m = MultiRangeProof{} }
@@ -271,37 +288,413 @@ func (m *MultiRangeProof) GetIPP() *InnerProductProof {
 	return nil
 }
 
+func (m *MultiRangeProof) GetBits() uint32 {
+	if m != nil {
+		return m.Bits
+	}
+	return 0
+}
+
+type WeightedInnerProductProof struct {
+	L  []*ECPoint `protobuf:"bytes,1,rep,name=L" json:"L,omitempty"`
+	R  []*ECPoint `protobuf:"bytes,2,rep,name=R" json:"R,omitempty"`
+	A  *ECPoint   `protobuf:"bytes,3,opt,name=A" json:"A,omitempty"`
+	B  *ECPoint   `protobuf:"bytes,4,opt,name=B" json:"B,omitempty"`
+	R1 []byte     `protobuf:"bytes,5,opt,name=R1,proto3" json:"R1,omitempty"`
+	S1 []byte     `protobuf:"bytes,6,opt,name=S1,proto3" json:"S1,omitempty"`
+	D1 []byte     `protobuf:"bytes,7,opt,name=D1,proto3" json:"D1,omitempty"`
+}
+
+func (m *WeightedInnerProductProof) Reset()                    { *m = WeightedInnerProductProof{} }
+func (m *WeightedInnerProductProof) String() string            { return proto.CompactTextString(m) }
+func (*WeightedInnerProductProof) ProtoMessage()               {}
+func (*WeightedInnerProductProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *WeightedInnerProductProof) GetL() []*ECPoint {
+	if m != nil {
+		return m.L
+	}
+	return nil
+}
+
+func (m *WeightedInnerProductProof) GetR() []*ECPoint {
+	if m != nil {
+		return m.R
+	}
+	return nil
+}
+
+func (m *WeightedInnerProductProof) GetA() *ECPoint {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *WeightedInnerProductProof) GetB() *ECPoint {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+func (m *WeightedInnerProductProof) GetR1() []byte {
+	if m != nil {
+		return m.R1
+	}
+	return nil
+}
+
+func (m *WeightedInnerProductProof) GetS1() []byte {
+	if m != nil {
+		return m.S1
+	}
+	return nil
+}
+
+func (m *WeightedInnerProductProof) GetD1() []byte {
+	if m != nil {
+		return m.D1
+	}
+	return nil
+}
+
+type RangeProofPlus struct {
+	A    *ECPoint                   `protobuf:"bytes,1,opt,name=A" json:"A,omitempty"`
+	WIP  *WeightedInnerProductProof `protobuf:"bytes,2,opt,name=WIP" json:"WIP,omitempty"`
+	Bits uint32                     `protobuf:"varint,3,opt,name=Bits,proto3" json:"Bits,omitempty"`
+}
+
+func (m *RangeProofPlus) Reset()                    { *m = RangeProofPlus{} }
+func (m *RangeProofPlus) String() string            { return proto.CompactTextString(m) }
+func (*RangeProofPlus) ProtoMessage()               {}
+func (*RangeProofPlus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *RangeProofPlus) GetA() *ECPoint {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *RangeProofPlus) GetWIP() *WeightedInnerProductProof {
+	if m != nil {
+		return m.WIP
+	}
+	return nil
+}
+
+func (m *RangeProofPlus) GetBits() uint32 {
+	if m != nil {
+		return m.Bits
+	}
+	return 0
+}
+
+type R1CSProof struct {
+	AI  *ECPoint           `protobuf:"bytes,1,opt,name=AI" json:"AI,omitempty"`
+	AO  *ECPoint           `protobuf:"bytes,2,opt,name=AO" json:"AO,omitempty"`
+	S   *ECPoint           `protobuf:"bytes,3,opt,name=S" json:"S,omitempty"`
+	T1  *ECPoint           `protobuf:"bytes,4,opt,name=T1" json:"T1,omitempty"`
+	T3  *ECPoint           `protobuf:"bytes,5,opt,name=T3" json:"T3,omitempty"`
+	T4  *ECPoint           `protobuf:"bytes,6,opt,name=T4" json:"T4,omitempty"`
+	T5  *ECPoint           `protobuf:"bytes,7,opt,name=T5" json:"T5,omitempty"`
+	T6  *ECPoint           `protobuf:"bytes,8,opt,name=T6" json:"T6,omitempty"`
+	Tau []byte             `protobuf:"bytes,9,opt,name=Tau,proto3" json:"Tau,omitempty"`
+	Th  []byte             `protobuf:"bytes,10,opt,name=Th,proto3" json:"Th,omitempty"`
+	Mu  []byte             `protobuf:"bytes,11,opt,name=Mu,proto3" json:"Mu,omitempty"`
+	IPP *InnerProductProof `protobuf:"bytes,12,opt,name=IPP" json:"IPP,omitempty"`
+}
+
+func (m *R1CSProof) Reset()                    { *m = R1CSProof{} }
+func (m *R1CSProof) String() string            { return proto.CompactTextString(m) }
+func (*R1CSProof) ProtoMessage()               {}
+func (*R1CSProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *R1CSProof) GetAI() *ECPoint {
+	if m != nil {
+		return m.AI
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetAO() *ECPoint {
+	if m != nil {
+		return m.AO
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetS() *ECPoint {
+	if m != nil {
+		return m.S
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetT1() *ECPoint {
+	if m != nil {
+		return m.T1
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetT3() *ECPoint {
+	if m != nil {
+		return m.T3
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetT4() *ECPoint {
+	if m != nil {
+		return m.T4
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetT5() *ECPoint {
+	if m != nil {
+		return m.T5
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetT6() *ECPoint {
+	if m != nil {
+		return m.T6
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetTau() []byte {
+	if m != nil {
+		return m.Tau
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetTh() []byte {
+	if m != nil {
+		return m.Th
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetMu() []byte {
+	if m != nil {
+		return m.Mu
+	}
+	return nil
+}
+
+func (m *R1CSProof) GetIPP() *InnerProductProof {
+	if m != nil {
+		return m.IPP
+	}
+	return nil
+}
+
+type BitCommitment struct {
+	V *ECPoint `protobuf:"bytes,1,opt,name=V" json:"V,omitempty"`
+	A *ECPoint `protobuf:"bytes,2,opt,name=A" json:"A,omitempty"`
+	S *ECPoint `protobuf:"bytes,3,opt,name=S" json:"S,omitempty"`
+}
+
+func (m *BitCommitment) Reset()                    { *m = BitCommitment{} }
+func (m *BitCommitment) String() string            { return proto.CompactTextString(m) }
+func (*BitCommitment) ProtoMessage()               {}
+func (*BitCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *BitCommitment) GetV() *ECPoint {
+	if m != nil {
+		return m.V
+	}
+	return nil
+}
+
+func (m *BitCommitment) GetA() *ECPoint {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *BitCommitment) GetS() *ECPoint {
+	if m != nil {
+		return m.S
+	}
+	return nil
+}
+
+type BitChallenge struct {
+	Y []byte `protobuf:"bytes,1,opt,name=Y,proto3" json:"Y,omitempty"`
+	Z []byte `protobuf:"bytes,2,opt,name=Z,proto3" json:"Z,omitempty"`
+}
+
+func (m *BitChallenge) Reset()                    { *m = BitChallenge{} }
+func (m *BitChallenge) String() string            { return proto.CompactTextString(m) }
+func (*BitChallenge) ProtoMessage()               {}
+func (*BitChallenge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *BitChallenge) GetY() []byte {
+	if m != nil {
+		return m.Y
+	}
+	return nil
+}
+
+func (m *BitChallenge) GetZ() []byte {
+	if m != nil {
+		return m.Z
+	}
+	return nil
+}
+
+type PolyCommitment struct {
+	T1 *ECPoint `protobuf:"bytes,1,opt,name=T1" json:"T1,omitempty"`
+	T2 *ECPoint `protobuf:"bytes,2,opt,name=T2" json:"T2,omitempty"`
+}
+
+func (m *PolyCommitment) Reset()                    { *m = PolyCommitment{} }
+func (m *PolyCommitment) String() string            { return proto.CompactTextString(m) }
+func (*PolyCommitment) ProtoMessage()               {}
+func (*PolyCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *PolyCommitment) GetT1() *ECPoint {
+	if m != nil {
+		return m.T1
+	}
+	return nil
+}
+
+func (m *PolyCommitment) GetT2() *ECPoint {
+	if m != nil {
+		return m.T2
+	}
+	return nil
+}
+
+type PolyChallenge struct {
+	X []byte `protobuf:"bytes,1,opt,name=X,proto3" json:"X,omitempty"`
+}
+
+func (m *PolyChallenge) Reset()                    { *m = PolyChallenge{} }
+func (m *PolyChallenge) String() string            { return proto.CompactTextString(m) }
+func (*PolyChallenge) ProtoMessage()               {}
+func (*PolyChallenge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *PolyChallenge) GetX() []byte {
+	if m != nil {
+		return m.X
+	}
+	return nil
+}
+
+type ProofShare struct {
+	Tau []byte   `protobuf:"bytes,1,opt,name=Tau,proto3" json:"Tau,omitempty"`
+	Mu  []byte   `protobuf:"bytes,2,opt,name=Mu,proto3" json:"Mu,omitempty"`
+	Th  []byte   `protobuf:"bytes,3,opt,name=Th,proto3" json:"Th,omitempty"`
+	L   [][]byte `protobuf:"bytes,4,rep,name=L,proto3" json:"L,omitempty"`
+	R   [][]byte `protobuf:"bytes,5,rep,name=R,proto3" json:"R,omitempty"`
+}
+
+func (m *ProofShare) Reset()                    { *m = ProofShare{} }
+func (m *ProofShare) String() string            { return proto.CompactTextString(m) }
+func (*ProofShare) ProtoMessage()               {}
+func (*ProofShare) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *ProofShare) GetTau() []byte {
+	if m != nil {
+		return m.Tau
+	}
+	return nil
+}
+
+func (m *ProofShare) GetMu() []byte {
+	if m != nil {
+		return m.Mu
+	}
+	return nil
+}
+
+func (m *ProofShare) GetTh() []byte {
+	if m != nil {
+		return m.Th
+	}
+	return nil
+}
+
+func (m *ProofShare) GetL() [][]byte {
+	if m != nil {
+		return m.L
+	}
+	return nil
+}
+
+func (m *ProofShare) GetR() [][]byte {
+	if m != nil {
+		return m.R
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Commitment)(nil), "pb.Commitment")
 	proto.RegisterType((*ECPoint)(nil), "pb.ECPoint")
 	proto.RegisterType((*InnerProductProof)(nil), "pb.InnerProductProof")
 	proto.RegisterType((*RangeProof)(nil), "pb.RangeProof")
 	proto.RegisterType((*MultiRangeProof)(nil), "pb.MultiRangeProof")
+	proto.RegisterType((*WeightedInnerProductProof)(nil), "pb.WeightedInnerProductProof")
+	proto.RegisterType((*RangeProofPlus)(nil), "pb.RangeProofPlus")
+	proto.RegisterType((*R1CSProof)(nil), "pb.R1CSProof")
+	proto.RegisterType((*BitCommitment)(nil), "pb.BitCommitment")
+	proto.RegisterType((*BitChallenge)(nil), "pb.BitChallenge")
+	proto.RegisterType((*PolyCommitment)(nil), "pb.PolyCommitment")
+	proto.RegisterType((*PolyChallenge)(nil), "pb.PolyChallenge")
+	proto.RegisterType((*ProofShare)(nil), "pb.ProofShare")
 }
 
 func init() { proto.RegisterFile("bulletproofs.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 325 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x92, 0x5f, 0x6b, 0xf2, 0x30,
-	0x18, 0xc5, 0x49, 0xfa, 0xfa, 0xef, 0x51, 0xde, 0x6d, 0x61, 0x83, 0x6c, 0x83, 0x21, 0xbd, 0x99,
-	0xbb, 0x11, 0x74, 0x9f, 0xa0, 0x8a, 0x17, 0x82, 0x42, 0x89, 0x65, 0xe8, 0xee, 0xac, 0xcd, 0x66,
-	0xa1, 0x4d, 0x4a, 0x9b, 0x7c, 0xd6, 0xdd, 0xec, 0xc3, 0x8c, 0xa4, 0x2a, 0x23, 0x13, 0x76, 0xb9,
-	0xbb, 0x9c, 0xe7, 0x17, 0xce, 0x73, 0x4e, 0x08, 0x90, 0x58, 0x67, 0x19, 0x57, 0x45, 0x29, 0xe5,
-	0x5b, 0x35, 0x2c, 0x4a, 0xa9, 0x24, 0xc1, 0x45, 0xec, 0xbf, 0x02, 0x4c, 0x65, 0x9e, 0xa7, 0x2a,
-	0xe7, 0x42, 0x91, 0x3b, 0x68, 0xcf, 0xc4, 0xee, 0x65, 0x9b, 0x69, 0x4e, 0x51, 0x1f, 0x0d, 0x7a,
-	0xec, 0xa4, 0xc9, 0x35, 0x34, 0x26, 0x59, 0x2a, 0x12, 0x8a, 0x2d, 0xa8, 0x05, 0xe9, 0x01, 0x5a,
-	0x53, 0xcf, 0x4e, 0xd0, 0xda, 0xa8, 0x0d, 0xfd, 0x57, 0xab, 0x8d, 0xff, 0x04, 0xad, 0xd9, 0x34,
-	0x94, 0xa9, 0x50, 0xe4, 0xc1, 0xae, 0x29, 0x4a, 0x5e, 0x55, 0x3c, 0x39, 0x58, 0x7f, 0x9b, 0xf8,
-	0x1c, 0xae, 0xe6, 0x42, 0xf0, 0x32, 0x2c, 0x65, 0xa2, 0x77, 0x2a, 0x34, 0x31, 0xc9, 0x2d, 0xa0,
-	0x05, 0x45, 0x7d, 0x6f, 0xd0, 0x1d, 0x77, 0x87, 0x45, 0x3c, 0x3c, 0x98, 0x31, 0xb4, 0x30, 0x88,
-	0x51, 0x7c, 0x06, 0x31, 0x93, 0x21, 0x38, 0x26, 0x0a, 0x8c, 0x9a, 0x1c, 0x13, 0x4d, 0xfc, 0x0f,
-	0x04, 0xc0, 0xb6, 0xe2, 0x9d, 0x9f, 0x16, 0x04, 0xb6, 0x8e, 0xeb, 0x12, 0x18, 0xb4, 0xb2, 0x2e,
-	0x2e, 0x5a, 0x91, 0x7b, 0xc0, 0xd1, 0xc8, 0x7a, 0x3a, 0x0c, 0x47, 0x23, 0x0b, 0xc7, 0xb4, 0x71,
-	0x0e, 0x8e, 0xc9, 0x25, 0x78, 0xd1, 0x56, 0xd3, 0xa6, 0x8d, 0x63, 0x8e, 0xe4, 0x3f, 0xe0, 0x68,
-	0x4f, 0x5b, 0x76, 0x80, 0xa3, 0xbd, 0xd1, 0x4b, 0x4d, 0xdb, 0xb5, 0x5e, 0x6a, 0xf2, 0x08, 0xde,
-	0x3c, 0x0c, 0x69, 0xc7, 0xfa, 0xdd, 0x18, 0xbf, 0x1f, 0xcf, 0xc4, 0xcc, 0x0d, 0xff, 0x13, 0xc1,
-	0xc5, 0x52, 0x67, 0x2a, 0xfd, 0xfb, 0x7a, 0x6d, 0xb7, 0x5e, 0xc7, 0xa9, 0x07, 0x6e, 0xbd, 0xee,
-	0x6f, 0xf5, 0xe2, 0xa6, 0xfd, 0xb1, 0xcf, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x83, 0x65, 0x0f,
-	0x2c, 0xc7, 0x02, 0x00, 0x00,
+	// 604 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xd5, 0xae, 0xd3, 0x36, 0x99, 0xb8, 0x05, 0x56, 0x20, 0x6d, 0xa8, 0x8a, 0x22, 0x5f, 0x28,
+	0x1c, 0x8a, 0x9c, 0xb4, 0xbd, 0xdb, 0x69, 0x0f, 0x41, 0x89, 0x6a, 0xad, 0xad, 0x34, 0xcd, 0x89,
+	0xa4, 0x59, 0x12, 0x4b, 0x8e, 0x6d, 0x39, 0xf6, 0x81, 0x5f, 0xc6, 0x9f, 0xe0, 0x9f, 0x70, 0xe1,
+	0x27, 0xa0, 0x5d, 0xdb, 0xc1, 0xb8, 0xcb, 0x87, 0x00, 0x89, 0x9b, 0x9f, 0x9f, 0x35, 0x33, 0xef,
+	0xcd, 0xdb, 0x35, 0x90, 0x45, 0x16, 0x04, 0x3c, 0x8d, 0x93, 0x28, 0x7a, 0xbf, 0x3d, 0x8b, 0x93,
+	0x28, 0x8d, 0x08, 0x8e, 0x17, 0xc6, 0x0c, 0x60, 0x10, 0x6d, 0x36, 0x7e, 0xba, 0xe1, 0x61, 0x4a,
+	0x9e, 0x43, 0xf3, 0x3a, 0xbc, 0x9f, 0xcc, 0x83, 0x8c, 0x53, 0xd4, 0x45, 0xa7, 0x3a, 0xdb, 0x61,
+	0xf2, 0x14, 0xf6, 0xec, 0xc0, 0x0f, 0x97, 0x14, 0x4b, 0x22, 0x07, 0x44, 0x07, 0x34, 0xa5, 0x9a,
+	0x7c, 0x83, 0xa6, 0x02, 0xdd, 0xd1, 0x46, 0x8e, 0xee, 0x8c, 0x57, 0x70, 0x70, 0x3d, 0x70, 0x22,
+	0x3f, 0x4c, 0xc9, 0x0b, 0xd9, 0x26, 0x4e, 0xf8, 0x76, 0xcb, 0x97, 0x45, 0xe9, 0xca, 0x1b, 0x83,
+	0xc3, 0x93, 0x61, 0x18, 0xf2, 0xc4, 0x49, 0xa2, 0x65, 0x76, 0x9f, 0x3a, 0x62, 0x4c, 0xd2, 0x01,
+	0x34, 0xa2, 0xa8, 0xab, 0x9d, 0xb6, 0x7b, 0xed, 0xb3, 0x78, 0x71, 0x56, 0x14, 0x63, 0x68, 0x24,
+	0x28, 0x46, 0xb1, 0x82, 0x62, 0x62, 0x06, 0xab, 0x9c, 0xc8, 0x12, 0xc8, 0x2e, 0x27, 0xb2, 0x8d,
+	0xcf, 0x08, 0x80, 0xcd, 0xc3, 0x15, 0xdf, 0x35, 0xb0, 0xa4, 0x9c, 0x7a, 0x15, 0x4b, 0x50, 0x2e,
+	0xd5, 0x14, 0x94, 0x4b, 0x8e, 0x01, 0x7b, 0x26, 0x6d, 0x3c, 0xe4, 0xb0, 0x67, 0x4a, 0xb2, 0x47,
+	0xf7, 0x54, 0x64, 0x8f, 0x3c, 0x06, 0xcd, 0x9b, 0x67, 0x74, 0x5f, 0x8e, 0x23, 0x1e, 0xc9, 0x11,
+	0x60, 0x6f, 0x4d, 0x0f, 0xe4, 0x0b, 0xec, 0xad, 0x05, 0x1e, 0x67, 0xb4, 0x99, 0xe3, 0x71, 0x46,
+	0x5e, 0x82, 0x36, 0x74, 0x1c, 0xda, 0x92, 0xf5, 0x9e, 0x89, 0x7a, 0x0f, 0x6c, 0x62, 0xe2, 0x0b,
+	0x42, 0xa0, 0x61, 0xfb, 0xe9, 0x96, 0x42, 0x17, 0x9d, 0x1e, 0x32, 0xf9, 0x6c, 0x7c, 0x41, 0xf0,
+	0x68, 0x9c, 0x05, 0xa9, 0xff, 0xff, 0x25, 0x37, 0xeb, 0x92, 0x5b, 0x35, 0xc9, 0x50, 0x97, 0xdc,
+	0xfe, 0x6d, 0xc9, 0x7a, 0x45, 0xf2, 0x47, 0x04, 0x9d, 0x5b, 0xee, 0xaf, 0xd6, 0x29, 0x5f, 0xfe,
+	0xab, 0x40, 0x75, 0xca, 0x40, 0x29, 0x2d, 0xb3, 0x55, 0xb6, 0x20, 0x5b, 0xc8, 0x62, 0xa6, 0x74,
+	0x45, 0x67, 0x98, 0x99, 0x02, 0xbb, 0x66, 0xb1, 0x7a, 0xec, 0x4a, 0x7c, 0x65, 0x96, 0x9b, 0xbf,
+	0x32, 0x8d, 0x18, 0x8e, 0xbe, 0xad, 0xc9, 0x09, 0xb2, 0x6d, 0xde, 0x17, 0x29, 0xfb, 0xbe, 0x01,
+	0xed, 0x76, 0xe8, 0x14, 0x7b, 0x3c, 0x11, 0xe4, 0x0f, 0x45, 0x33, 0xf1, 0xe5, 0xce, 0x2b, 0xad,
+	0xe2, 0xd5, 0x27, 0x0c, 0x2d, 0x66, 0x0e, 0xdc, 0xdc, 0x9b, 0x63, 0xc0, 0xd6, 0x50, 0xd5, 0x0e,
+	0x5b, 0x43, 0x49, 0xde, 0xa8, 0x62, 0x83, 0xad, 0x9b, 0xbf, 0xca, 0x4d, 0x5f, 0x9d, 0x9b, 0xbe,
+	0x24, 0xcf, 0xe9, 0xbe, 0x8a, 0x3c, 0x97, 0xe4, 0x05, 0x3d, 0x50, 0x91, 0x17, 0x92, 0xbc, 0xa4,
+	0x4d, 0x15, 0x79, 0x59, 0xc6, 0xb1, 0x55, 0x8f, 0x23, 0xd4, 0xe2, 0xd8, 0xae, 0xc7, 0x51, 0xff,
+	0x55, 0x1c, 0x8d, 0x77, 0x70, 0x68, 0xfb, 0x69, 0xe5, 0x32, 0xed, 0x00, 0x9a, 0x28, 0xf7, 0x37,
+	0xf9, 0xb3, 0x53, 0x68, 0xbc, 0x06, 0x5d, 0x74, 0x58, 0xcf, 0x83, 0x80, 0x87, 0x2b, 0x9e, 0xdf,
+	0xb6, 0xa8, 0xb8, 0x6d, 0x05, 0x9a, 0x15, 0x77, 0x33, 0x9a, 0x19, 0x6f, 0xe1, 0xc8, 0x89, 0x82,
+	0x0f, 0x95, 0x71, 0xf2, 0x5d, 0xa0, 0x9f, 0x9d, 0x61, 0xd5, 0x82, 0xbd, 0x9e, 0x71, 0x02, 0x87,
+	0xb2, 0x56, 0xb5, 0xf1, 0xb4, 0x6c, 0x3c, 0x15, 0xbf, 0x10, 0x69, 0x83, 0xbb, 0x9e, 0x27, 0xbc,
+	0x74, 0x18, 0x7d, 0xe7, 0xf0, 0x38, 0xa3, 0x78, 0xe7, 0x68, 0xee, 0xb8, 0xb6, 0x73, 0x5c, 0x17,
+	0xa7, 0xb2, 0xd1, 0xd5, 0x44, 0xb5, 0x91, 0x40, 0x8c, 0xee, 0xe5, 0x88, 0x2d, 0xf6, 0xe5, 0x9f,
+	0xaa, 0xff, 0x75, 0x00, 0x06, 0x7e, 0x37, 0xe5, 0xbf, 0x06, 0x00, 0x00,
 }
@@ -1,9 +1,7 @@
 package bp_go
 
 import (
-	"crypto/rand"
 	"crypto/sha256"
-	"fmt"
 	"math/big"
 
 	"github.com/decred/dcrd/dcrec/secp256k1"
@@ -23,27 +21,20 @@ VectorPCommit - Vector Pedersen Commit
 Given an array of values, we commit the array with different generators
 for each element and for each randomness.
 */
-func VectorPCommit(value []*big.Int) (ECPoint, []*big.Int) {
-	R := make([]*big.Int, EC.V)
-
-	commitment := EC.Zero()
-
-	for i := 0; i < EC.V; i++ {
-		r, err := rand.Int(rand.Reader, EC.N)
-		check(err)
-
-		R[i] = r
-
-		modValue := new(big.Int).Mod(value[i], EC.N)
-
-		// mG, rH
-		lhsX, lhsY := EC.C.ScalarMult(EC.BPG[i].X, EC.BPG[i].Y, modValue.Bytes())
-		rhsX, rhsY := EC.C.ScalarMult(EC.BPH[i].X, EC.BPH[i].Y, r.Bytes())
+func (ec *CryptoParams) VectorPCommit(value []*big.Int) (ECPoint, []*big.Int, error) {
+	if len(value) < ec.V {
+		return ECPoint{}, nil, lengthError("VectorPCommit", len(value), ec.V)
+	}
 
-		commitment = commitment.Add(ECPoint{lhsX, lhsY}).Add(ECPoint{rhsX, rhsY})
+	R, err := ec.fr.rands(ec.V)
+	if err != nil {
+		return ECPoint{}, nil, err
 	}
 
-	return commitment, R
+	// sum of mG + rH
+	commitment := ec.ctCommit(nil, nil, ec.fr.fromBigs(value[:ec.V]), R)
+
+	return commitment, scalarsBig(R), nil
 }
 
 /*
@@ -52,20 +43,12 @@ TwoVectorPCommit - Two Vector P Commit
 Given an array of values, we commit the array with different generators
 for each element and for each randomness.
 */
-func TwoVectorPCommit(a []*big.Int, b []*big.Int) ECPoint {
-	if len(a) != len(b) {
-		fmt.Println("TwoVectorPCommit: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(a): %d\n", len(a))
-		fmt.Printf("len(b): %d\n", len(b))
-	}
-
-	commitment := EC.Zero()
-
-	for i := 0; i < EC.V; i++ {
-		commitment = commitment.Add(EC.BPG[i].Mult(a[i])).Add(EC.BPH[i].Mult(b[i]))
+func (ec *CryptoParams) TwoVectorPCommit(a []*big.Int, b []*big.Int) (ECPoint, error) {
+	if len(a) != len(b) || len(a) < ec.V {
+		return ECPoint{}, lengthError("TwoVectorPCommit", len(a), len(b), ec.V)
 	}
 
-	return commitment
+	return ec.ctCommit(nil, nil, ec.fr.fromBigs(a[:ec.V]), ec.fr.fromBigs(b[:ec.V])), nil
 }
 
 /*
@@ -76,59 +59,50 @@ for each element and for each randomness.
 
 We also pass in the Generators we want to use
 */
-func TwoVectorPCommitWithGens(G, H []ECPoint, a, b []*big.Int) ECPoint {
+func (ec *CryptoParams) TwoVectorPCommitWithGens(G, H []ECPoint, a, b []*big.Int) (ECPoint, error) {
 	if len(G) != len(H) || len(G) != len(a) || len(a) != len(b) {
-		fmt.Println("TwoVectorPCommitWithGens: Uh oh! Arrays not of the same length")
-		fmt.Printf("len(G): %d\n", len(G))
-		fmt.Printf("len(H): %d\n", len(H))
-		fmt.Printf("len(a): %d\n", len(a))
-		fmt.Printf("len(b): %d\n", len(b))
+		return ECPoint{}, lengthError("TwoVectorPCommitWithGens", len(G), len(H), len(a), len(b))
 	}
+	return ec.twoVectorPCommitWithGens(G, H, a, b), nil
+}
 
-	commitment := EC.Zero()
-
-	for i := 0; i < len(G); i++ {
-		modA := new(big.Int).Mod(a[i], EC.N)
-		modB := new(big.Int).Mod(b[i], EC.N)
-
-		commitment = commitment.Add(G[i].Mult(modA)).Add(H[i].Mult(modB))
-	}
+func (ec *CryptoParams) twoVectorPCommitWithGens(G, H []ECPoint, a, b []*big.Int) ECPoint {
+	points := append(append(make([]ECPoint, 0, 2*len(G)), G...), H...)
+	scalars := append(append(make([]*big.Int, 0, 2*len(a)), a...), b...)
 
-	return commitment
+	return ec.msm(points, scalars)
 }
 
 /*
 VectorPCommitTrans -Vector Pedersen Commit with Gens and BF
 This modified method is to be used with input and output transactions
 */
-func VectorPCommitTrans(pubkey *secp256k1.PublicKey, value []*big.Int, sSecret *big.Int) (ECPoint, []*big.Int, [][]byte) {
-	R := make([]*big.Int, EC.V)
+func (ec *CryptoParams) VectorPCommitTrans(pubkey *secp256k1.PublicKey, value []*big.Int, sSecret *big.Int) (ECPoint, []*big.Int, [][]byte, error) {
+	if len(value) < ec.V {
+		return ECPoint{}, nil, nil, lengthError("VectorPCommitTrans", len(value), ec.V)
+	}
 
-	commitment := EC.Zero()
+	R := make([]*big.Int, ec.V)
 
-	encValues := make([][]byte, EC.V)
+	encValues := make([][]byte, ec.V)
 
-	for i := 0; i < EC.V; i++ {
+	for i := 0; i < ec.V; i++ {
 		hash := sha256.Sum256(value[i].Bytes())
 		r := secp256k1.NonceRFC6979(sSecret, hash[:], nil, nil)
 
 		R[i] = r
 
 		// create the encrypted hash
-		fmt.Printf("values are %s\n", value[i])
 		ciphertext, err := secp256k1.Encrypt(pubkey, []byte(value[i].String()))
-		check(err)
+		if err != nil {
+			return ECPoint{}, nil, nil, err
+		}
 
 		encValues[i] = ciphertext
-
-		modValue := new(big.Int).Mod(value[i], EC.N)
-
-		// mG, rH
-		lhsX, lhsY := EC.C.ScalarMult(EC.BPG[i].X, EC.BPG[i].Y, modValue.Bytes())
-		rhsX, rhsY := EC.C.ScalarMult(EC.BPH[i].X, EC.BPH[i].Y, r.Bytes())
-
-		commitment = commitment.Add(ECPoint{lhsX, lhsY}).Add(ECPoint{rhsX, rhsY})
 	}
 
-	return commitment, R, encValues
+	// sum of mG + rH
+	commitment := ec.ctCommit(nil, nil, ec.fr.fromBigs(value[:ec.V]), ec.fr.fromBigs(R))
+
+	return commitment, R, encValues, nil
 }
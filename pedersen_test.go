@@ -38,7 +38,10 @@ func TestVectorPCommit(t *testing.T) {
 		v[j] = big.NewInt(2)
 	}
 
-	output, r := VectorPCommit(v)
+	output, r, err := VectorPCommit(v)
+	if err != nil {
+		t.Fatal(err)
+	}
 	fmt.Println(fmt.Sprintf("output is %s", output))
 	fmt.Println(fmt.Sprintf("r is %s", r))
 	if len(r) != 3 {
@@ -78,10 +81,13 @@ func TestTwoVectorPCommit(t *testing.T) {
 		v2[j] = big.NewInt(6)
 	}
 
-	output := TwoVectorPCommit(v, v2)
+	output, err := TwoVectorPCommit(v, v2)
+	if err != nil {
+		t.Fatal(err)
+	}
 	fmt.Println(fmt.Sprintf("output is %s", output))
 
-	if !EC.C.IsOnCurve(output.X, output.Y) {
+	if !EC.Group.IsOnCurve(output) {
 		fmt.Println("Failure - commit is not on curve")
 	}
 	// Need to determine how to verify this
@@ -104,7 +110,7 @@ func TestTwoVectorPCommitWithGens(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := TwoVectorPCommitWithGens(tt.args.G, tt.args.H, tt.args.a, tt.args.b); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := TwoVectorPCommitWithGens(tt.args.G, tt.args.H, tt.args.a, tt.args.b); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("TwoVectorPCommitWithGens() = %v, want %v", got, tt.want)
 			}
 		})
@@ -128,7 +134,7 @@ func TestVectorPCommitTrans(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, got2 := VectorPCommitTrans(tt.args.pubkey, tt.args.value, tt.args.sSecret)
+			got, got1, got2, _ := VectorPCommitTrans(tt.args.pubkey, tt.args.value, tt.args.sSecret)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("VectorPCommitTrans() got = %v, want %v", got, tt.want)
 			}
@@ -142,3 +148,22 @@ func TestVectorPCommitTrans(t *testing.T) {
 	}
 }
 
+
+func TestPedersenOverP256(t *testing.T) {
+	ec, err := NewGroupParams(P256(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	comm, R, err := ec.VectorPCommit(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := ec.TwoVectorPCommitWithGens(ec.BPG, ec.BPH, v, R)
+	if !comm.Equal(expected) {
+		t.Error("VectorPCommit over P-256 does not match TwoVectorPCommitWithGens")
+	}
+	if !ec.Group.IsOnCurve(comm) {
+		t.Error("commitment is not a P-256 point")
+	}
+}
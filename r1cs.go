@@ -0,0 +1,700 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/decred/base58"
+	"github.com/golang/protobuf/proto"
+	"github.com/peterdouglas/bp-go/pb"
+)
+
+/*
+Arithmetic circuits
+
+Section 5 of the paper proves that committed values satisfy a rank one
+constraint system: n multiplication gates aL_i * aR_i = aO_i, and Q linear
+constraints
+
+	W_L aL + W_R aR + W_O aO = W_V v + c
+
+over the gate wires and the values v committed to in V_j = v_j G + gamma_j H.
+
+A circuit is built by running the same code against an R1CSProver, which
+knows the values, and an R1CSVerifier, which only has the commitments. Both
+implement ConstraintSystem, so gadgets like RangeGadget are written once.
+Every linear constraint is a LinearCombination that must come to zero.
+
+The proof commits to the wires in A_I and A_O and to blinding vectors in S,
+then flattens the constraints with powers of a challenge z and shows
+
+	l(X) = (aL + y^-n o w_R) X + aO X^2 + sL X^3
+	r(X) = y^n o aR X - y^n + w_L X + w_O + y^n o sR X^3
+
+have an inner product whose X^2 coefficient is <w_V, v> + w_c + <y^-n o w_R, w_L>,
+with T1, T3, T4, T5 and T6 committing to the other coefficients. l(x) and r(x)
+go through the inner product argument with H scaled by y^-i, as in a range
+proof.
+*/
+
+type varKind uint8
+
+const (
+	varOne varKind = iota
+	varCommitted
+	varLeft
+	varRight
+	varOutput
+)
+
+// Variable - a value in a constraint system: a committed value, one of the
+// wires of a multiplication gate, or the constant One
+type Variable struct {
+	kind  varKind
+	index int
+}
+
+// One - the variable that is always 1, used for the constant terms of linear
+// combinations
+var One = Variable{kind: varOne}
+
+// Term - a coefficient times a variable
+type Term struct {
+	Var   Variable
+	Coeff *big.Int
+}
+
+// LinearCombination - a sum of terms
+type LinearCombination []Term
+
+// LC returns v as a linear combination
+func (v Variable) LC() LinearCombination {
+	return LinearCombination{{v, big.NewInt(1)}}
+}
+
+// Constant returns the linear combination c One
+func Constant(c *big.Int) LinearCombination {
+	return LinearCombination{{One, c}}
+}
+
+// Add returns lc + other
+func (lc LinearCombination) Add(other LinearCombination) LinearCombination {
+	return append(append(LinearCombination{}, lc...), other...)
+}
+
+// Sub returns lc - other
+func (lc LinearCombination) Sub(other LinearCombination) LinearCombination {
+	return lc.Add(other.Scale(big.NewInt(-1)))
+}
+
+// Scale returns c lc
+func (lc LinearCombination) Scale(c *big.Int) LinearCombination {
+	res := make(LinearCombination, len(lc))
+	for i, term := range lc {
+		res[i] = Term{term.Var, new(big.Int).Mul(term.Coeff, c)}
+	}
+	return res
+}
+
+/*
+ConstraintSystem - what gadgets use to build a circuit
+
+Multiply adds a gate with the given inputs and returns its wires, which are
+constrained to equal the inputs. AllocateMultiplier adds a gate with no
+constraints on its inputs, which the prover must give values for and the
+verifier passes nil. Evaluate returns the value of lc for the prover and nil
+for the verifier. ChallengeScalar draws a challenge bound to every value
+committed so far, which circuits can use in constraints over those values.
+*/
+type ConstraintSystem interface {
+	Multiply(left, right LinearCombination) (l, r, o Variable)
+	AllocateMultiplier(left, right *big.Int) (l, r, o Variable, err error)
+	Constrain(lc LinearCombination)
+	Evaluate(lc LinearCombination) *big.Int
+	ChallengeScalar(label string) *big.Int
+}
+
+// R1CSProof - a proof that committed values satisfy a constraint system
+type R1CSProof struct {
+	AI, AO, S          ECPoint
+	T1, T3, T4, T5, T6 ECPoint
+	Tau                *big.Int
+	Th                 *big.Int
+	Mu                 *big.Int
+	IPP                InnerProdArg
+}
+
+// r1cs - the parts of a constraint system the prover and verifier share
+type r1cs struct {
+	ec          *CryptoParams
+	t           *Transcript
+	m           int // committed values
+	n           int // multiplication gates
+	constraints []LinearCombination
+}
+
+func (ec *CryptoParams) newR1CS(t *Transcript) r1cs {
+	if t == nil {
+		t = ec.NewTranscript("bulletproofs r1cs proof")
+	}
+	t.AppendMessage("dom-sep", []byte("r1cs"))
+	t.AppendMessage("generators", ec.ID())
+	return r1cs{ec: ec, t: t}
+}
+
+// Constrain adds the constraint lc = 0
+func (cs *r1cs) Constrain(lc LinearCombination) {
+	cs.constraints = append(cs.constraints, lc)
+}
+
+// ChallengeScalar draws a challenge from the transcript
+func (cs *r1cs) ChallengeScalar(label string) *big.Int {
+	return cs.t.ChallengeScalar(label)
+}
+
+// gate allocates the wires of a new multiplication gate
+func (cs *r1cs) gate() (l, r, o Variable) {
+	i := cs.n
+	cs.n++
+	return Variable{varLeft, i}, Variable{varRight, i}, Variable{varOutput, i}
+}
+
+// size returns the number of generators the proof uses
+func (cs *r1cs) size() (int, error) {
+	size := padCount(cs.n)
+	if size == 0 {
+		size = 1
+	}
+	if size > len(cs.ec.BPG) || size > len(cs.ec.BPH) {
+		return 0, lengthError("r1cs", cs.n, len(cs.ec.BPG), len(cs.ec.BPH))
+	}
+	return size, nil
+}
+
+// weights flattens the constraints with the powers z^(q+1) into w_L, w_R
+// and w_O over the padded gates, w_V over the commitments and the constant
+// w_c. The committed and constant terms move to the right hand side.
+func (cs *r1cs) weights(z *big.Int, size int) (wL, wR, wO, wV []*big.Int, wc *big.Int) {
+	N := cs.ec.N
+	zeros := func(l int) []*big.Int {
+		v := make([]*big.Int, l)
+		for i := range v {
+			v[i] = new(big.Int)
+		}
+		return v
+	}
+	wL, wR, wO, wV = zeros(size), zeros(size), zeros(size), zeros(cs.m)
+	wc = new(big.Int)
+
+	zq := new(big.Int).Set(z)
+	c := new(big.Int)
+	for _, lc := range cs.constraints {
+		for _, term := range lc {
+			c.Mul(zq, term.Coeff)
+			switch i := term.Var.index; term.Var.kind {
+			case varLeft:
+				wL[i].Add(wL[i], c)
+			case varRight:
+				wR[i].Add(wR[i], c)
+			case varOutput:
+				wO[i].Add(wO[i], c)
+			case varCommitted:
+				wV[i].Sub(wV[i], c)
+			case varOne:
+				wc.Sub(wc, c)
+			}
+		}
+		zq.Mod(zq.Mul(zq, z), N)
+	}
+
+	for _, w := range [][]*big.Int{wL, wR, wO, wV, {wc}} {
+		for _, x := range w {
+			x.Mod(x, N)
+		}
+	}
+	return wL, wR, wO, wV, wc
+}
+
+/*
+R1CSProver - builds a constraint system with values and proves it
+
+The values and blinding factors are kept as scalars and every point built
+from them goes through the constant time code, like the range prover.
+Gadgets that read values through Evaluate see them as big.Int.
+*/
+type R1CSProver struct {
+	r1cs
+	v, gamma   []scalar
+	aL, aR, aO []scalar
+}
+
+// NewR1CSProver starts a proof on t, or on a fresh transcript if t is nil.
+// The verifier has to start from a transcript in the same state.
+func (ec *CryptoParams) NewR1CSProver(t *Transcript) *R1CSProver {
+	return &R1CSProver{r1cs: ec.newR1CS(t)}
+}
+
+// Commit commits to v with the blinding factor gamma and returns the
+// commitment, which the verifier needs, and the variable for v
+func (p *R1CSProver) Commit(v, gamma *big.Int) (ECPoint, Variable) {
+	sv, sg := p.ec.fr.fromBig(v), p.ec.fr.fromBig(gamma)
+	V := p.ec.ctCommit(&sv, &sg, nil, nil)
+	p.t.AppendPoint("V", V)
+
+	p.v = append(p.v, sv)
+	p.gamma = append(p.gamma, sg)
+	p.m++
+	return V, Variable{varCommitted, p.m - 1}
+}
+
+// eval returns the value of lc
+func (p *R1CSProver) eval(lc LinearCombination) scalar {
+	var sum, tmp scalar
+	one := p.ec.fr.one()
+	for _, term := range lc {
+		var v *scalar
+		switch i := term.Var.index; term.Var.kind {
+		case varOne:
+			v = &one
+		case varCommitted:
+			v = &p.v[i]
+		case varLeft:
+			v = &p.aL[i]
+		case varRight:
+			v = &p.aR[i]
+		case varOutput:
+			v = &p.aO[i]
+		}
+		c := p.ec.fr.fromBig(term.Coeff)
+		tmp.mul(&c, v)
+		sum.add(&sum, &tmp)
+	}
+	return sum
+}
+
+// Evaluate returns the value of lc
+func (p *R1CSProver) Evaluate(lc LinearCombination) *big.Int {
+	v := p.eval(lc)
+	return v.big()
+}
+
+func (p *R1CSProver) assign(l, r scalar) (Variable, Variable, Variable) {
+	var o scalar
+	o.mul(&l, &r)
+	p.aL = append(p.aL, l)
+	p.aR = append(p.aR, r)
+	p.aO = append(p.aO, o)
+	return p.gate()
+}
+
+// Multiply adds a gate multiplying left by right
+func (p *R1CSProver) Multiply(left, right LinearCombination) (Variable, Variable, Variable) {
+	l, r, o := p.assign(p.eval(left), p.eval(right))
+	p.Constrain(left.Sub(l.LC()))
+	p.Constrain(right.Sub(r.LC()))
+	return l, r, o
+}
+
+// AllocateMultiplier adds a gate with the inputs left and right
+func (p *R1CSProver) AllocateMultiplier(left, right *big.Int) (Variable, Variable, Variable, error) {
+	if left == nil || right == nil {
+		return Variable{}, Variable{}, Variable{}, fmt.Errorf("%w: gate %d", ErrMissingAssignment, p.n)
+	}
+	l, r, o := p.assign(p.ec.fr.fromBig(left), p.ec.fr.fromBig(right))
+	return l, r, o, nil
+}
+
+// Prove proves the constraint system. It fails with ErrUnsatisfiedConstraint
+// if the values do not satisfy it.
+func (p *R1CSProver) Prove() (R1CSProof, error) {
+	ec := p.ec
+	proof := R1CSProof{}
+	size, err := p.size()
+	if err != nil {
+		return proof, err
+	}
+	for q, lc := range p.constraints {
+		if v := p.eval(lc); !v.equal(&scalar{}) {
+			return proof, fmt.Errorf("%w: constraint %d", ErrUnsatisfiedConstraint, q)
+		}
+	}
+
+	aL := append(append([]scalar{}, p.aL...), make([]scalar, size-p.n)...)
+	aR := append(append([]scalar{}, p.aR...), make([]scalar, size-p.n)...)
+	aO := append(append([]scalar{}, p.aO...), make([]scalar, size-p.n)...)
+
+	blinds, err := ec.fr.rands(3)
+	if err != nil {
+		return proof, err
+	}
+	alpha, beta, rho := blinds[0], blinds[1], blinds[2]
+	sL, err := ec.fr.rands(size)
+	if err != nil {
+		return proof, err
+	}
+	sR, err := ec.fr.rands(size)
+	if err != nil {
+		return proof, err
+	}
+
+	proof.AI = ec.ctCommit(nil, &alpha, aL, aR)
+	proof.AO = ec.ctCommit(nil, &beta, aO, nil)
+	proof.S = ec.ctCommit(nil, &rho, sL, sR)
+
+	t := p.t
+	t.AppendUint64("m", uint64(p.m))
+	t.AppendUint64("n", uint64(p.n))
+	t.AppendPoint("A_I", proof.AI)
+	t.AppendPoint("A_O", proof.AO)
+	t.AppendPoint("S", proof.S)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+
+	bwL, bwR, bwO, bwV, bwc := p.weights(cz, size)
+	wL, wR, wO, wV := ec.fr.fromBigs(bwL), ec.fr.fromBigs(bwR), ec.fr.fromBigs(bwO), ec.fr.fromBigs(bwV)
+	sy := ec.fr.fromBig(cy)
+	var syinv scalar
+	syinv.inverse(&sy)
+	PowerOfCY := scalarPowers(size, &sy)
+	PowerOfCYInv := scalarPowers(size, &syinv)
+
+	// the coefficients of l(X) and r(X), l0 = r2 = 0
+	l1 := make([]scalar, size)
+	l2 := aO
+	l3 := sL
+	r0 := make([]scalar, size)
+	r1 := make([]scalar, size)
+	r3 := make([]scalar, size)
+	var tmp scalar
+	for i := 0; i < size; i++ {
+		tmp.mul(&PowerOfCYInv[i], &wR[i])
+		l1[i].add(&aL[i], &tmp)
+		r0[i].sub(&wO[i], &PowerOfCY[i])
+		r1[i].mul(&PowerOfCY[i], &aR[i])
+		r1[i].add(&r1[i], &wL[i])
+		r3[i].mul(&PowerOfCY[i], &sR[i])
+	}
+
+	ip := func(terms ...[]scalar) scalar {
+		var sum scalar
+		for k := 0; k < len(terms); k += 2 {
+			x := scalarInnerProduct(terms[k], terms[k+1])
+			sum.add(&sum, &x)
+		}
+		return sum
+	}
+	t1 := ip(l1, r0)
+	t2 := ip(l1, r1, l2, r0)
+	t3 := ip(l2, r1, l3, r0)
+	t4 := ip(l1, r3, l3, r1)
+	t5 := ip(l2, r3)
+	t6 := ip(l3, r3)
+
+	// t2 = <w_V, v> + w_c + <y^-n o w_R, w_L> whenever the gates and the
+	// constraints hold
+	want := scalarInnerProduct(wV, p.v)
+	wc := ec.fr.fromBig(bwc)
+	want.add(&want, &wc)
+	for i := 0; i < size; i++ {
+		tmp.mul(&PowerOfCYInv[i], &wR[i])
+		tmp.mul(&tmp, &wL[i])
+		want.add(&want, &tmp)
+	}
+	if !t2.equal(&want) {
+		return proof, fmt.Errorf("%w: t2 does not match the constraints", ErrUnsatisfiedConstraint)
+	}
+
+	taus, err := ec.fr.rands(5)
+	if err != nil {
+		return proof, err
+	}
+	proof.T1 = ec.ctCommit(&t1, &taus[0], nil, nil)
+	proof.T3 = ec.ctCommit(&t3, &taus[1], nil, nil)
+	proof.T4 = ec.ctCommit(&t4, &taus[2], nil, nil)
+	proof.T5 = ec.ctCommit(&t5, &taus[3], nil, nil)
+	proof.T6 = ec.ctCommit(&t6, &taus[4], nil, nil)
+
+	t.AppendPoint("T_1", proof.T1)
+	t.AppendPoint("T_3", proof.T3)
+	t.AppendPoint("T_4", proof.T4)
+	t.AppendPoint("T_5", proof.T5)
+	t.AppendPoint("T_6", proof.T6)
+	sx := ec.fr.fromBig(t.ChallengeScalar("x"))
+	xs := scalarPowers(7, &sx)
+
+	// l(x), r(x) and their inner product
+	left := make([]scalar, size)
+	right := make([]scalar, size)
+	for i := 0; i < size; i++ {
+		left[i].mul(&l1[i], &xs[1])
+		tmp.mul(&l2[i], &xs[2])
+		left[i].add(&left[i], &tmp)
+		tmp.mul(&l3[i], &xs[3])
+		left[i].add(&left[i], &tmp)
+
+		right[i].mul(&r1[i], &xs[1])
+		right[i].add(&right[i], &r0[i])
+		tmp.mul(&r3[i], &xs[3])
+		right[i].add(&right[i], &tmp)
+	}
+	that := scalarInnerProduct(left, right)
+
+	// tau_x = sum_i tau_i x^i + x^2 <w_V, gamma>
+	taux := scalarInnerProduct(wV, p.gamma)
+	taux.mul(&taux, &xs[2])
+	for k, i := range []int{1, 3, 4, 5, 6} {
+		tmp.mul(&taus[k], &xs[i])
+		taux.add(&taux, &tmp)
+	}
+
+	// mu = alpha x + beta x^2 + rho x^3
+	var mu scalar
+	mu.mul(&alpha, &xs[1])
+	tmp.mul(&beta, &xs[2])
+	mu.add(&mu, &tmp)
+	tmp.mul(&rho, &xs[3])
+	mu.add(&mu, &tmp)
+
+	proof.Tau, proof.Mu, proof.Th = taux.big(), mu.big(), that.big()
+	t.AppendScalar("tau_x", proof.Tau)
+	t.AppendScalar("mu", proof.Mu)
+	t.AppendScalar("t_hat", proof.Th)
+	proof.IPP = ec.rangeProofIPA(size, cy).prove(t, left, right)
+
+	return proof, nil
+}
+
+// R1CSVerifier - builds a constraint system from commitments and verifies
+// proofs of it
+type R1CSVerifier struct {
+	r1cs
+	V []ECPoint
+}
+
+// NewR1CSVerifier starts verifying on t, or on a fresh transcript if t is nil
+func (ec *CryptoParams) NewR1CSVerifier(t *Transcript) *R1CSVerifier {
+	return &R1CSVerifier{r1cs: ec.newR1CS(t)}
+}
+
+// Commit returns the variable for the value committed to in V
+func (v *R1CSVerifier) Commit(V ECPoint) Variable {
+	v.t.AppendPoint("V", V)
+	v.V = append(v.V, V)
+	v.m++
+	return Variable{varCommitted, v.m - 1}
+}
+
+// Evaluate returns nil, the verifier knows no values
+func (v *R1CSVerifier) Evaluate(lc LinearCombination) *big.Int {
+	return nil
+}
+
+// Multiply adds a gate multiplying left by right
+func (v *R1CSVerifier) Multiply(left, right LinearCombination) (Variable, Variable, Variable) {
+	l, r, o := v.gate()
+	v.Constrain(left.Sub(l.LC()))
+	v.Constrain(right.Sub(r.LC()))
+	return l, r, o
+}
+
+// AllocateMultiplier adds a gate, ignoring the values
+func (v *R1CSVerifier) AllocateMultiplier(left, right *big.Int) (Variable, Variable, Variable, error) {
+	l, r, o := v.gate()
+	return l, r, o, nil
+}
+
+func (proof *R1CSProof) check(g Group) error {
+	if proof.Tau == nil || proof.Th == nil || proof.Mu == nil {
+		return fmt.Errorf("%w: missing tau, t hat or mu", ErrMalformedProof)
+	}
+	return checkPoints(g, proof.AI, proof.AO, proof.S, proof.T1, proof.T3, proof.T4, proof.T5, proof.T6)
+}
+
+/*
+Verify checks proof against the constraint system built so far. Returns false
+if the proof does not verify, and an error if the proof or the commitments are
+malformed.
+
+The check of t hat and the inner product argument are weighted with a random
+scalar and checked with one multi-exponentiation, as in mrpVerify.
+*/
+func (v *R1CSVerifier) Verify(proof *R1CSProof) (bool, error) {
+	ec := v.ec
+	if proof == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	if err := checkPoints(ec.group(), v.V...); err != nil {
+		return false, err
+	}
+	if err := proof.check(ec.group()); err != nil {
+		return false, err
+	}
+	size, err := v.size()
+	if err != nil {
+		return false, err
+	}
+	if err := proof.IPP.check(ec.group(), size); err != nil {
+		return false, err
+	}
+
+	t := v.t
+	t.AppendUint64("m", uint64(v.m))
+	t.AppendUint64("n", uint64(v.n))
+	t.AppendPoint("A_I", proof.AI)
+	t.AppendPoint("A_O", proof.AO)
+	t.AppendPoint("S", proof.S)
+	cy := t.ChallengeScalar("y")
+	cz := t.ChallengeScalar("z")
+	t.AppendPoint("T_1", proof.T1)
+	t.AppendPoint("T_3", proof.T3)
+	t.AppendPoint("T_4", proof.T4)
+	t.AppendPoint("T_5", proof.T5)
+	t.AppendPoint("T_6", proof.T6)
+	cx := t.ChallengeScalar("x")
+	t.AppendScalar("tau_x", proof.Tau)
+	t.AppendScalar("mu", proof.Mu)
+	t.AppendScalar("t_hat", proof.Th)
+	ipa := ec.rangeProofIPA(size, cy)
+	sc := ipa.verificationScalars(t, proof.Th, &proof.IPP)
+
+	r, err := ec.randScalar()
+	if err != nil {
+		return false, err
+	}
+
+	mul := func(a ...*big.Int) *big.Int {
+		res := big.NewInt(1)
+		for _, v := range a {
+			res.Mod(res.Mul(res, v), ec.N)
+		}
+		return res
+	}
+	neg := func(a *big.Int) *big.Int {
+		return new(big.Int).Mod(new(big.Int).Neg(a), ec.N)
+	}
+
+	wL, wR, wO, wV, wc := v.weights(cz, size)
+	xs := ec.PowerVector(7, cx)
+	yInv := ipa.hScale
+
+	// delta = <y^-n o w_R, w_L>
+	delta := new(big.Int)
+	for i := 0; i < size; i++ {
+		delta.Add(delta, mul(yInv[i], wR[i], wL[i]))
+	}
+
+	points := make([]ECPoint, 0, 2*size+len(v.V)+2*len(sc.L)+11)
+	scalars := make([]*big.Int, 0, cap(points))
+
+	// r (t hat G + tau_x H - x^2 <w_V, V> - x^2 (delta + w_c) G - sum_i x^i T_i)
+	for j := range v.V {
+		points = append(points, v.V[j])
+		scalars = append(scalars, neg(mul(r, xs[2], wV[j])))
+	}
+	points = append(points, proof.T1, proof.T3, proof.T4, proof.T5, proof.T6)
+	for _, i := range []int{1, 3, 4, 5, 6} {
+		scalars = append(scalars, neg(mul(r, xs[i])))
+	}
+	g := new(big.Int).Sub(proof.Th, mul(xs[2], new(big.Int).Add(delta, wc)))
+	h := new(big.Int).Sub(mul(r, proof.Tau), proof.Mu)
+
+	// x A_I + x^2 A_O + x^3 S - mu H + <x y^-n o w_R, BPG>
+	// + <y^-n o (x w_L + w_O) - 1, BPH> and the inner product argument
+	points = append(points, proof.AI, proof.AO, proof.S)
+	scalars = append(scalars, xs[1], xs[2], xs[3])
+	for j := range sc.L {
+		points = append(points, proof.IPP.L[j], proof.IPP.R[j])
+		scalars = append(scalars, sc.L[j], sc.R[j])
+	}
+	points = append(points, ec.G, ec.H, ec.U)
+	scalars = append(scalars, mul(r, g), h, sc.U)
+
+	one := big.NewInt(1)
+	for i := 0; i < size; i++ {
+		points = append(points, ec.BPG[i])
+		scalars = append(scalars, new(big.Int).Add(mul(xs[1], yInv[i], wR[i]), sc.G[i]))
+	}
+	for i := 0; i < size; i++ {
+		hi := mul(yInv[i], new(big.Int).Add(mul(xs[1], wL[i]), wO[i]))
+		points = append(points, ec.BPH[i])
+		scalars = append(scalars, hi.Add(hi, sc.H[i]).Sub(hi, one))
+	}
+
+	sum := ec.msm(points, scalars)
+	if sum.X.Sign() != 0 || sum.Y.Sign() != 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Serialize - encodes the proof as base58 protobuf, with the points encoded
+// for the group of EC
+func (proof *R1CSProof) Serialize() (string, error) {
+	return proof.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (proof *R1CSProof) SerializeWithParams(ec *CryptoParams) (string, error) {
+	w := &pbWriter{g: ec.group()}
+	pbp := &pb.R1CSProof{
+		AI:  w.point("A_I", proof.AI),
+		AO:  w.point("A_O", proof.AO),
+		S:   w.point("S", proof.S),
+		T1:  w.point("T1", proof.T1),
+		T3:  w.point("T3", proof.T3),
+		T4:  w.point("T4", proof.T4),
+		T5:  w.point("T5", proof.T5),
+		T6:  w.point("T6", proof.T6),
+		Tau: w.scalar("tau_x", proof.Tau),
+		Th:  w.scalar("t_hat", proof.Th),
+		Mu:  w.scalar("mu", proof.Mu),
+		IPP: proof.IPP.pb(w),
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+
+	serial, err := proto.Marshal(pbp)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(serial), nil
+}
+
+// Rebuild - decodes a proof made by Serialize
+func (proof *R1CSProof) Rebuild(encoded string) error {
+	return proof.RebuildWithParams(&EC, encoded)
+}
+
+// RebuildWithParams - decodes a proof made by SerializeWithParams(ec)
+func (proof *R1CSProof) RebuildWithParams(ec *CryptoParams, encoded string) error {
+	b := base58.Decode(encoded)
+	if len(b) == 0 {
+		return fmt.Errorf("%w: empty or invalid base58", ErrMalformedProof)
+	}
+
+	pbp := &pb.R1CSProof{}
+	if err := proto.Unmarshal(b, pbp); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+
+	points := []*ECPoint{&proof.AI, &proof.AO, &proof.S, &proof.T1, &proof.T3, &proof.T4, &proof.T5, &proof.T6}
+	pbPoints := []*pb.ECPoint{pbp.AI, pbp.AO, pbp.S, pbp.T1, pbp.T3, pbp.T4, pbp.T5, pbp.T6}
+	for i := range points {
+		if err := rebuildPoint(ec.group(), points[i], pbPoints[i]); err != nil {
+			return err
+		}
+	}
+
+	proof.Tau = new(big.Int).SetBytes(pbp.Tau)
+	proof.Th = new(big.Int).SetBytes(pbp.Th)
+	proof.Mu = new(big.Int).SetBytes(pbp.Mu)
+
+	ipp, err := rebuildIPP(ec.group(), pbp.IPP)
+	if err != nil {
+		return err
+	}
+	proof.IPP = ipp
+	return nil
+}
@@ -0,0 +1,159 @@
+package bp_go
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/gtank/ristretto255"
+)
+
+// Ristretto255HashToCurveSuite - the RFC 9380 suite ristretto255 generators
+// are hashed with
+const Ristretto255HashToCurveSuite = "ristretto255_XMD:SHA-512_R255MAP_RO_"
+
+// Ristretto255HashToCurveDST - the domain separation tag
+// Ristretto255().HashToPoint hashes under
+const Ristretto255HashToCurveDST = "bp-go-V01-CS02-with-" + Ristretto255HashToCurveSuite
+
+// ristrettoOrder - 2^252 + 27742317777372353535851937790883648493
+var ristrettoOrder = hexInt("1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ed")
+
+// Ristretto255 returns the ristretto255 group of RFC 9496, which the dalek
+// bulletproofs crate works over, see NewDalekParams
+func Ristretto255() Group {
+	return ristrettoGroup{}
+}
+
+/*
+ristrettoGroup - ristretto255 on top of github.com/gtank/ristretto255, whose
+arithmetic runs in constant time
+
+A ristretto255 element has several Edwards representatives, so its ECPoint
+does not hold curve coordinates. X is the 32 byte encoding of the element
+read as a big endian integer and Y is 1, or both are 0 for the identity. The
+encoding is canonical, so ECPoint.Equal still compares elements. The ECPoint
+methods, which do secp256k1 arithmetic, do not apply to these points.
+*/
+type ristrettoGroup struct{}
+
+func (ristrettoGroup) Name() string {
+	return "ristretto255"
+}
+
+func (ristrettoGroup) Order() *big.Int {
+	return ristrettoOrder
+}
+
+func (g ristrettoGroup) Add(p, q ECPoint) ECPoint {
+	return ristrettoPoint(ristretto255.NewElement().Add(ristrettoElement(p), ristrettoElement(q)))
+}
+
+func (g ristrettoGroup) Neg(p ECPoint) ECPoint {
+	return ristrettoPoint(ristretto255.NewElement().Negate(ristrettoElement(p)))
+}
+
+// ScalarMult reduces k through the wide reduction of ristretto255 scalars,
+// which runs in constant time like the multiplication itself
+func (g ristrettoGroup) ScalarMult(p ECPoint, k []byte) ECPoint {
+	var wide [64]byte
+	for i := range k {
+		wide[i] = k[len(k)-1-i]
+	}
+	s := ristretto255.NewScalar().FromUniformBytes(wide[:])
+	return ristrettoPoint(ristretto255.NewElement().ScalarMult(s, ristrettoElement(p)))
+}
+
+func (g ristrettoGroup) MultiScalarMul(points []ECPoint, scalars []*big.Int) ECPoint {
+	es := make([]*ristretto255.Element, len(points))
+	ss := make([]*ristretto255.Scalar, len(points))
+	for i := range points {
+		es[i] = ristrettoElement(points[i])
+		ss[i] = ristrettoScalar(scalars[i])
+	}
+	return ristrettoPoint(ristretto255.NewElement().VarTimeMultiScalarMult(ss, es))
+}
+
+func (g ristrettoGroup) IsOnCurve(p ECPoint) bool {
+	if p.X == nil || p.Y == nil || p.Y.Cmp(big.NewInt(1)) != 0 || p.X.Sign() == 0 || p.X.BitLen() > 256 {
+		return false
+	}
+	return ristretto255.NewElement().Decode(p.X.FillBytes(make([]byte, 32))) == nil
+}
+
+func (g ristrettoGroup) Encode(p ECPoint) []byte {
+	return p.X.FillBytes(make([]byte, 32))
+}
+
+// Decode accepts the encoding of the identity, which IsOnCurve then rejects
+func (g ristrettoGroup) Decode(buf []byte) (ECPoint, error) {
+	e := ristretto255.NewElement()
+	if len(buf) != 32 || e.Decode(buf) != nil {
+		return ECPoint{}, fmt.Errorf("%w: not a canonical ristretto255 encoding", ErrInvalidPoint)
+	}
+	return ristrettoPoint(e), nil
+}
+
+// HashToPoint - hash_to_ristretto255 of RFC 9380 with the suite
+// Ristretto255HashToCurveSuite under the tag Ristretto255HashToCurveDST: 64
+// bytes of expand_message_xmd with SHA-512, mapped by ristrettoFromUniformBytes
+func (g ristrettoGroup) HashToPoint(msg []byte) ECPoint {
+	return ristrettoFromUniformBytes(expandMessageXMD(sha512.New, msg, []byte(Ristretto255HashToCurveDST), 64))
+}
+
+// ristrettoFromUniformBytes - the element derivation function of section
+// 4.3.4 of RFC 9496, which curve25519-dalek calls from_uniform_bytes
+func ristrettoFromUniformBytes(b []byte) ECPoint {
+	return ristrettoPoint(ristretto255.NewElement().FromUniformBytes(b))
+}
+
+// ristrettoElement converts an ECPoint of ristrettoGroup back to an element.
+// Points reach the group only after IsOnCurve or Decode accepted them, so
+// anything else is a bug.
+func ristrettoElement(p ECPoint) *ristretto255.Element {
+	e := ristretto255.NewElement()
+	if p.X.Sign() == 0 && p.Y.Sign() == 0 {
+		return e
+	}
+	if p.X.BitLen() > 256 || e.Decode(p.X.FillBytes(make([]byte, 32))) != nil {
+		panic("bulletproofs: not a ristretto255 point")
+	}
+	return e
+}
+
+// ristrettoPoint converts an element to its ECPoint
+func ristrettoPoint(e *ristretto255.Element) ECPoint {
+	x := new(big.Int).SetBytes(e.Encode(nil))
+	if x.Sign() == 0 {
+		return ECPoint{x, big.NewInt(0)}
+	}
+	return ECPoint{x, big.NewInt(1)}
+}
+
+// ristrettoScalar converts x mod the order to a ristretto255 scalar
+func ristrettoScalar(x *big.Int) *ristretto255.Scalar {
+	s := ristretto255.NewScalar()
+	if err := s.Decode(scalarLE(x, ristrettoOrder)); err != nil {
+		panic("bulletproofs: " + err.Error())
+	}
+	return s
+}
+
+// scalarLE returns x mod N as 32 little endian bytes, the way
+// curve25519-dalek encodes scalars
+func scalarLE(x, N *big.Int) []byte {
+	b := new(big.Int).Mod(x, N).FillBytes(make([]byte, 32))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// leInt reads b as a little endian integer
+func leInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
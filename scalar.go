@@ -0,0 +1,391 @@
+package bp_go
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+	"math/bits"
+)
+
+/*
+scalar - an element of the scalar field of a group in constant time
+
+The prover keeps every secret (value bits, blinding factors and the vectors
+derived from them) in this form rather than in big.Int, whose arithmetic
+takes time that depends on the values. The limbs hold x * 2^256 mod N
+(Montgomery form) and every operation runs the same instructions whatever the
+inputs are.
+
+Each scalar points at the field it belongs to, so the same code serves every
+group whose order fits in 256 bits. Only the zero value has no field, and as
+it is zero in every field, operations on it take the field of their other
+operand or return the zero value again.
+*/
+type scalar struct {
+	v [4]uint64
+	f *scalarField
+}
+
+// scalarField - the constants of Montgomery arithmetic mod an odd N below 2^256
+type scalarField struct {
+	n     [4]uint64
+	nInv  uint64    // -N^-1 mod 2^64
+	r2    [4]uint64 // 2^512 mod N, used to move into Montgomery form
+	r     [4]uint64 // 1 in Montgomery form, that is 2^256 mod N
+	order *big.Int
+}
+
+// secp256k1Scalars - the scalar field of secp256k1
+var secp256k1Scalars = newScalarField(curve.N)
+
+// newScalarField returns the field of integers mod order, or nil if order is
+// even or does not fit in 256 bits
+func newScalarField(order *big.Int) *scalarField {
+	if order.Sign() <= 0 || order.Bit(0) == 0 || order.BitLen() > 256 {
+		return nil
+	}
+	f := &scalarField{order: new(big.Int).Set(order)}
+	f.n = bigLimbs(order)
+
+	// N^-1 mod 2^64 by Newton's iteration, each step doubling the correct bits
+	inv := f.n[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - f.n[0]*inv
+	}
+	f.nInv = -inv
+
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	f.r = bigLimbs(new(big.Int).Mod(r, order))
+	f.r2 = bigLimbs(new(big.Int).Mod(new(big.Int).Mul(r, r), order))
+	return f
+}
+
+// bigLimbs returns the little endian limbs of x, which must be below 2^256
+func bigLimbs(x *big.Int) [4]uint64 {
+	var buf [32]byte
+	x.FillBytes(buf[:])
+	var r [4]uint64
+	for i := 0; i < 4; i++ {
+		r[i] = beUint64(buf[32-8*(i+1):])
+	}
+	return r
+}
+
+// fieldOf returns the field of x, or of y if x is the zero value
+func fieldOf(x, y *scalar) *scalarField {
+	if x.f != nil {
+		return x.f
+	}
+	return y.f
+}
+
+// one returns 1
+func (f *scalarField) one() scalar {
+	return scalar{f.r, f}
+}
+
+// fromBig converts x mod N to a scalar. Any x in [0, 2^256) is read with a
+// fixed number of steps, which covers every secret the prover is given; only
+// values outside that range fall back to a big.Int reduction.
+//
+// x is not reduced before it is multiplied by 2^512 mod N: Montgomery
+// multiplication only needs one factor below N and the other below 2^256, so
+// the product comes out reduced however much larger than N the value is.
+func (f *scalarField) fromBig(x *big.Int) scalar {
+	raw := scalar{f: f}
+	if x.Sign() < 0 || x.BitLen() > 256 {
+		raw.v = bigLimbs(new(big.Int).Mod(x, f.order))
+	} else {
+		raw.v = bigLimbs(x)
+	}
+	r2 := scalar{f.r2, f}
+	var r scalar
+	r.mul(&raw, &r2)
+	return r
+}
+
+// fromBigs converts every element of v
+func (f *scalarField) fromBigs(v []*big.Int) []scalar {
+	r := make([]scalar, len(v))
+	for i := range v {
+		r[i] = f.fromBig(v[i])
+	}
+	return r
+}
+
+// secretBits returns the limbs of a value that is about to be split into bits
+// and whether it lies in [0, 2^n). The check looks at every limb whatever the
+// value is and the only branch is on its result.
+func secretBits(v *big.Int, n int) (scalarLimbs, bool) {
+	var r scalarLimbs
+	if v == nil || v.Sign() < 0 || v.BitLen() > 256 {
+		return r, false
+	}
+	var buf [32]byte
+	v.FillBytes(buf[:])
+	var high uint64
+	for i := 0; i < 4; i++ {
+		r[i] = beUint64(buf[32-8*(i+1):])
+		// the bits of limb i at or above n
+		shift := n - 64*i
+		switch {
+		case shift <= 0:
+			high |= r[i]
+		case shift < 64:
+			high |= r[i] >> uint(shift)
+		}
+	}
+	return r, high == 0
+}
+
+// rand returns a uniformly random scalar, sampling as many bytes as N takes
+// until they are below N
+func (f *scalarField) rand() (scalar, error) {
+	var buf [32]byte
+	l := (f.order.BitLen() + 7) / 8
+	for {
+		if _, err := io.ReadFull(rand.Reader, buf[32-l:]); err != nil {
+			return scalar{}, err
+		}
+		raw := scalar{f: f}
+		for i := 0; i < 4; i++ {
+			raw.v[i] = beUint64(buf[32-8*(i+1):])
+		}
+		_, borrow := raw.subN()
+		if borrow == 1 {
+			r2 := scalar{f.r2, f}
+			var r scalar
+			r.mul(&raw, &r2)
+			return r, nil
+		}
+	}
+}
+
+// rands returns l random scalars
+func (f *scalarField) rands(l int) ([]scalar, error) {
+	r := make([]scalar, l)
+	for i := range r {
+		s, err := f.rand()
+		if err != nil {
+			return nil, err
+		}
+		r[i] = s
+	}
+	return r, nil
+}
+
+// limbs returns the plain (non Montgomery) value of s
+func (s *scalar) limbs() scalarLimbs {
+	var r scalar
+	one := scalar{[4]uint64{1, 0, 0, 0}, s.f}
+	r.mul(s, &one)
+	return scalarLimbs(r.v)
+}
+
+// bytes returns the big endian encoding of s, in constant time
+func (s *scalar) bytes() []byte {
+	l := s.limbs()
+	buf := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		putBeUint64(buf[32-8*(i+1):], l[i])
+	}
+	return buf
+}
+
+// big returns s as a big.Int. Only call it on values that are about to be
+// made public.
+func (s *scalar) big() *big.Int {
+	return new(big.Int).SetBytes(s.bytes())
+}
+
+// subN returns s - N and the borrow out
+func (s *scalar) subN() (scalar, uint64) {
+	n := &s.f.n
+	r := scalar{f: s.f}
+	var b uint64
+	r.v[0], b = bits.Sub64(s.v[0], n[0], 0)
+	r.v[1], b = bits.Sub64(s.v[1], n[1], b)
+	r.v[2], b = bits.Sub64(s.v[2], n[2], b)
+	r.v[3], b = bits.Sub64(s.v[3], n[3], b)
+	return r, b
+}
+
+// reduce sets z to carry*2^256 + t mod N, given that value is below 2N
+func (z *scalar) reduce(t *scalar, carry uint64) *scalar {
+	s, b := t.subN()
+	keep := -(b &^ carry)
+	z.v[0] = (t.v[0] & keep) | (s.v[0] &^ keep)
+	z.v[1] = (t.v[1] & keep) | (s.v[1] &^ keep)
+	z.v[2] = (t.v[2] & keep) | (s.v[2] &^ keep)
+	z.v[3] = (t.v[3] & keep) | (s.v[3] &^ keep)
+	z.f = t.f
+	return z
+}
+
+// add sets z to x + y
+func (z *scalar) add(x, y *scalar) *scalar {
+	f := fieldOf(x, y)
+	if f == nil {
+		*z = scalar{}
+		return z
+	}
+	t := scalar{f: f}
+	var c uint64
+	t.v[0], c = bits.Add64(x.v[0], y.v[0], 0)
+	t.v[1], c = bits.Add64(x.v[1], y.v[1], c)
+	t.v[2], c = bits.Add64(x.v[2], y.v[2], c)
+	t.v[3], c = bits.Add64(x.v[3], y.v[3], c)
+	return z.reduce(&t, c)
+}
+
+// sub sets z to x - y
+func (z *scalar) sub(x, y *scalar) *scalar {
+	f := fieldOf(x, y)
+	if f == nil {
+		*z = scalar{}
+		return z
+	}
+	var t [4]uint64
+	var b uint64
+	t[0], b = bits.Sub64(x.v[0], y.v[0], 0)
+	t[1], b = bits.Sub64(x.v[1], y.v[1], b)
+	t[2], b = bits.Sub64(x.v[2], y.v[2], b)
+	t[3], b = bits.Sub64(x.v[3], y.v[3], b)
+
+	// add N back if we borrowed
+	mask := -b
+	var c uint64
+	z.v[0], c = bits.Add64(t[0], f.n[0]&mask, 0)
+	z.v[1], c = bits.Add64(t[1], f.n[1]&mask, c)
+	z.v[2], c = bits.Add64(t[2], f.n[2]&mask, c)
+	z.v[3], _ = bits.Add64(t[3], f.n[3]&mask, c)
+	z.f = f
+	return z
+}
+
+// neg sets z to -x
+func (z *scalar) neg(x *scalar) *scalar {
+	var zero scalar
+	return z.sub(&zero, x)
+}
+
+// mul sets z to x * y with CIOS Montgomery multiplication
+func (z *scalar) mul(x, y *scalar) *scalar {
+	f := fieldOf(x, y)
+	if f == nil {
+		*z = scalar{}
+		return z
+	}
+	n := &f.n
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		// t += x * y[i]
+		var c uint64
+		c, t[0] = madd(x.v[0], y.v[i], t[0], 0)
+		c, t[1] = madd(x.v[1], y.v[i], t[1], c)
+		c, t[2] = madd(x.v[2], y.v[i], t[2], c)
+		c, t[3] = madd(x.v[3], y.v[i], t[3], c)
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] = c
+
+		// t = (t + m N) / 2^64
+		m := t[0] * f.nInv
+		c, _ = madd(m, n[0], t[0], 0)
+		c, t[0] = madd(m, n[1], t[1], c)
+		c, t[1] = madd(m, n[2], t[2], c)
+		c, t[2] = madd(m, n[3], t[3], c)
+		t[3], c = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + c
+	}
+	r := scalar{[4]uint64{t[0], t[1], t[2], t[3]}, f}
+	return z.reduce(&r, t[4])
+}
+
+// selectScalar returns a if bit is 1 and b if bit is 0
+func selectScalar(a, b *scalar, bit uint64) scalar {
+	mask := -bit
+	return scalar{[4]uint64{
+		(a.v[0] & mask) | (b.v[0] &^ mask),
+		(a.v[1] & mask) | (b.v[1] &^ mask),
+		(a.v[2] & mask) | (b.v[2] &^ mask),
+		(a.v[3] & mask) | (b.v[3] &^ mask),
+	}, fieldOf(a, b)}
+}
+
+// equal reports whether s and x are the same scalar
+func (s *scalar) equal(x *scalar) bool {
+	return (s.v[0]^x.v[0])|(s.v[1]^x.v[1])|(s.v[2]^x.v[2])|(s.v[3]^x.v[3]) == 0
+}
+
+// scalarInnerProduct returns <a, b>
+func scalarInnerProduct(a, b []scalar) scalar {
+	var r, t scalar
+	for i := range a {
+		t.mul(&a[i], &b[i])
+		r.add(&r, &t)
+	}
+	return r
+}
+
+// scalarPowers returns 1, x, x^2, ..., x^(l-1). x must not be the zero value,
+// whose field would be unknown.
+func scalarPowers(l int, x *scalar) []scalar {
+	r := make([]scalar, l)
+	if l == 0 {
+		return r
+	}
+	r[0] = x.f.one()
+	for i := 1; i < l; i++ {
+		r[i].mul(&r[i-1], x)
+	}
+	return r
+}
+
+// scalarsBig converts scalars that are about to be made public to big.Int
+func scalarsBig(v []scalar) []*big.Int {
+	r := make([]*big.Int, len(v))
+	for i := range v {
+		r[i] = v[i].big()
+	}
+	return r
+}
+
+// inverse sets z to x^-1 as x^(N-2), or to 0 if x is 0
+func (z *scalar) inverse(x *scalar) *scalar {
+	if x.f == nil {
+		*z = scalar{}
+		return z
+	}
+	e := scalarLimbs(bigLimbs(new(big.Int).Sub(x.f.order, big.NewInt(2))))
+	r := x.f.one()
+	for i := 255; i >= 0; i-- {
+		r.mul(&r, &r)
+		if (e[i/64]>>uint(i%64))&1 == 1 {
+			r.mul(&r, x)
+		}
+	}
+	*z = r
+	return z
+}
+
+// scalarBatchInverse returns the inverses of the non zero scalars v with a
+// single inversion, using Montgomery's trick
+func scalarBatchInverse(v []scalar) []scalar {
+	r := make([]scalar, len(v))
+	if len(v) == 0 {
+		return r
+	}
+	acc := v[0].f.one()
+	for i := range v {
+		r[i] = acc
+		acc.mul(&acc, &v[i])
+	}
+
+	// acc is 1 / (v_0 ... v_i) at the start of round i
+	acc.inverse(&acc)
+	for i := len(v) - 1; i >= 0; i-- {
+		r[i].mul(&r[i], &acc)
+		acc.mul(&acc, &v[i])
+	}
+	return r
+}
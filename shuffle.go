@@ -0,0 +1,157 @@
+package bp_go
+
+import (
+	"fmt"
+	"math/big"
+)
+
+/*
+Shuffle proofs
+
+A shuffle proof shows that the outputs commit to the values committed to in
+the inputs in some order, without revealing the order. Each output gets a
+fresh blinding factor, so the outputs cannot be matched to the inputs by
+comparing commitments either.
+
+The proof is an R1CSProof of ShuffleGadget over the two lists. The
+commitments are absorbed into the transcript before the challenge the gadget
+uses is drawn, so the prover cannot pick the values to suit it.
+*/
+
+// ShuffleProof - a proof that one list of commitments is a permutation of
+// another. It serializes like an R1CSProof.
+type ShuffleProof struct {
+	R1CSProof
+}
+
+/*
+ShuffleGadget - constrains y to be a permutation of x
+
+With a challenge z it checks prod_i (x_i - z) = prod_i (y_i - z), which holds
+for a random z only if the two lists are equal as multisets. It uses 2(k-1)
+gates for lists of length k. Every value in x and y has to be committed
+before the gadget runs, since z is only bound to commitments made so far.
+*/
+func ShuffleGadget(cs ConstraintSystem, x, y []LinearCombination) error {
+	if len(x) != len(y) {
+		return lengthError("ShuffleGadget", len(x), len(y))
+	}
+	k := len(x)
+	if k == 0 {
+		return nil
+	}
+	if k == 1 {
+		cs.Constrain(x[0].Sub(y[0]))
+		return nil
+	}
+
+	z := Constant(cs.ChallengeScalar("k-shuffle z"))
+	product := func(v []LinearCombination) LinearCombination {
+		_, _, o := cs.Multiply(v[k-1].Sub(z), v[k-2].Sub(z))
+		for i := k - 3; i >= 0; i-- {
+			_, _, o = cs.Multiply(o.LC(), v[i].Sub(z))
+		}
+		return o.LC()
+	}
+	cs.Constrain(product(x).Sub(product(y)))
+	return nil
+}
+
+// shuffleTranscript starts the transcript of a shuffle of k values
+func (ec *CryptoParams) shuffleTranscript(k int) *Transcript {
+	t := ec.NewTranscript("bulletproofs shuffle proof")
+	t.AppendUint64("k", uint64(k))
+	return t
+}
+
+/*
+ShuffleProve - Shuffle Proof Prove
+
+Given the values and blinding factors of the input commitments, commits to
+the values in the order output i = input perm[i] under fresh random blinding
+factors and proves the outputs are a shuffle of the inputs. Returns the
+proof, the outputs and their blinding factors.
+*/
+func (ec *CryptoParams) ShuffleProve(values, blinds []*big.Int, perm []int) (ShuffleProof, []ECPoint, []*big.Int, error) {
+	newBlinds, err := ec.RandVector(len(values))
+	if err != nil {
+		return ShuffleProof{}, nil, nil, err
+	}
+	proof, outputs, err := ec.ShuffleProveTrans(values, blinds, perm, newBlinds)
+	return proof, outputs, newBlinds, err
+}
+
+// ShuffleProveTrans - ShuffleProve with the blinding factors of the outputs
+// given by the caller, for example ones made by Commitment.Generate
+func (ec *CryptoParams) ShuffleProveTrans(values, blinds []*big.Int, perm []int, newBlinds []*big.Int) (ShuffleProof, []ECPoint, error) {
+	k := len(values)
+	if len(blinds) != k || len(perm) != k || len(newBlinds) != k {
+		return ShuffleProof{}, nil, lengthError("ShuffleProve", k, len(blinds), len(perm), len(newBlinds))
+	}
+	seen := make([]bool, k)
+	for _, j := range perm {
+		if j < 0 || j >= k || seen[j] {
+			return ShuffleProof{}, nil, fmt.Errorf("%w: %v", ErrInvalidPermutation, perm)
+		}
+		seen[j] = true
+	}
+	for j := range values {
+		if values[j] == nil || blinds[j] == nil || newBlinds[j] == nil {
+			return ShuffleProof{}, nil, fmt.Errorf("%w: value or blinding factor %d", ErrMissingAssignment, j)
+		}
+	}
+
+	p := ec.NewR1CSProver(ec.shuffleTranscript(k))
+	x := make([]LinearCombination, k)
+	y := make([]LinearCombination, k)
+	outputs := make([]ECPoint, k)
+	for i := range values {
+		_, v := p.Commit(values[i], blinds[i])
+		x[i] = v.LC()
+	}
+	for i, j := range perm {
+		V, v := p.Commit(values[j], newBlinds[i])
+		outputs[i] = V
+		y[i] = v.LC()
+	}
+	if err := ShuffleGadget(p, x, y); err != nil {
+		return ShuffleProof{}, nil, err
+	}
+
+	proof, err := p.Prove()
+	if err != nil {
+		return ShuffleProof{}, nil, err
+	}
+	return ShuffleProof{proof}, outputs, nil
+}
+
+/*
+ShuffleVerify - Shuffle Proof Verify
+
+Checks that outputs commit to a permutation of the values committed to in
+inputs. Returns false if the proof does not verify, and an error if the proof
+or the commitments are malformed.
+*/
+func (ec *CryptoParams) ShuffleVerify(inputs, outputs []ECPoint, proof *ShuffleProof) (bool, error) {
+	if proof == nil {
+		return false, fmt.Errorf("%w: missing proof", ErrMalformedProof)
+	}
+	k := len(inputs)
+	if len(outputs) != k {
+		return false, lengthError("ShuffleVerify", k, len(outputs))
+	}
+
+	v := ec.NewR1CSVerifier(ec.shuffleTranscript(k))
+	x := make([]LinearCombination, k)
+	y := make([]LinearCombination, k)
+	for i := range inputs {
+		x[i] = v.Commit(inputs[i]).LC()
+	}
+	for i := range outputs {
+		y[i] = v.Commit(outputs[i]).LC()
+	}
+	if err := ShuffleGadget(v, x, y); err != nil {
+		return false, err
+	}
+	return v.Verify(&proof.R1CSProof)
+}
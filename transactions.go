@@ -6,8 +6,6 @@ import (
 	"github.com/decred/base58"
 	"github.com/golang/protobuf/proto"
 	"github.com/peterdouglas/bp-go/pb"
-	"encoding/hex"
-	"log"
 	"crypto/sha256"
 	"fmt"
 	"bytes"
@@ -22,10 +20,16 @@ type Transaction struct {
 
 // Generate a single commitment from a commitment struct
 func (c *Commitment) Generate(receiverKey *secp256k1.PublicKey, v, sSecret *big.Int)  error {
+	return EC.GenerateCommitment(c, receiverKey, v, sSecret)
+}
+
+// GenerateCommitment fills in c with a commitment to v using the G and H of ec
+func (ec *CryptoParams) GenerateCommitment(c *Commitment, receiverKey *secp256k1.PublicKey, v, sSecret *big.Int) error {
 	hash := sha256.Sum256(v.Bytes())
 
 	gamma := secp256k1.NonceRFC6979(sSecret, hash[:], nil, nil)
-	c.Comm = EC.G.Mult(v).Add(EC.H.Mult(gamma))
+	sv, sg := ec.fr.fromBig(v), ec.fr.fromBig(gamma)
+	c.Comm = ec.ctCommit(&sv, &sg, nil, nil)
 	c.Blind = gamma
 	// now we encrypt the value so the receiver can recreate the trans
 	ciphertext, err := secp256k1.Encrypt(receiverKey, []byte(v.String()))
@@ -38,57 +42,70 @@ func (c *Commitment) Generate(receiverKey *secp256k1.PublicKey, v, sSecret *big.
 }
 
 
-func (rp *MultiRangeProof) Bytes() []byte {
+// Bytes - the points and scalars of the proof concatenated, with the points
+// encoded for the group of EC, or ErrMalformedProof if any are missing
+func (rp *MultiRangeProof) Bytes() ([]byte, error) {
+	return rp.BytesWithParams(&EC)
+}
+
+// BytesWithParams - Bytes with the points encoded for the group of ec
+func (rp *MultiRangeProof) BytesWithParams(ec *CryptoParams) ([]byte, error) {
+	return proofBytes(&pbWriter{g: ec.group()}, rp)
+}
+
+// proofBytes - concatenates A, S, T1, T2, tau_x, t_hat, mu, the L and R of
+// every round and a and b, encoding the points with w
+func proofBytes(w *pbWriter, rp *MultiRangeProof) ([]byte, error) {
 	var retBytes bytes.Buffer
-	retBytes.Write(rp.A.Bytes())
-	retBytes.Write(rp.S.Bytes())
-	retBytes.Write(rp.T1.Bytes())
-	retBytes.Write(rp.T2.Bytes())
-	retBytes.Write(rp.Tau.Bytes())
-	retBytes.Write(rp.Th.Bytes())
-	if len(rp.Th.Bytes()) != 32 {
-		fmt.Printf("Tau %v\n", rp.Th.Sign())
-	}
-	retBytes.Write(rp.Mu.Bytes())
+	retBytes.Write(w.point("A", rp.A).GetCompressed())
+	retBytes.Write(w.point("S", rp.S).GetCompressed())
+	retBytes.Write(w.point("T1", rp.T1).GetCompressed())
+	retBytes.Write(w.point("T2", rp.T2).GetCompressed())
+	retBytes.Write(w.scalar("tau_x", rp.Tau))
+	retBytes.Write(w.scalar("t_hat", rp.Th))
+	retBytes.Write(w.scalar("mu", rp.Mu))
 
 	// now for the IPP bytes
-	for i :=0; i < len(rp.IPP.R);i++  {
-		retBytes.Write(rp.IPP.L[i].Bytes())
-		retBytes.Write(rp.IPP.R[i].Bytes())
+	ipp := rp.IPP.pb(w)
+	for i := 0; i < len(ipp.L); i++ {
+		retBytes.Write(ipp.L[i].GetCompressed())
+		retBytes.Write(ipp.R[i].GetCompressed())
+	}
+	retBytes.Write(ipp.A)
+	retBytes.Write(ipp.B)
+	if w.err != nil {
+		return nil, w.err
 	}
-	retBytes.Write(rp.IPP.A.Bytes())
-	retBytes.Write(rp.IPP.B.Bytes())
-
-
-	return retBytes.Bytes()
 
+	return retBytes.Bytes(), nil
 }
 
+// Serialize - encodes the proof as base58 protobuf, with the points encoded
+// for the group of EC
 func (mp *MultiRangeProof) Serialize() (string, error) {
+	return mp.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (mp *MultiRangeProof) SerializeWithParams(ec *CryptoParams) (string, error) {
 	// create the protobuff object for serialization
 	pbmp := &pb.MultiRangeProof{}
+	w := &pbWriter{g: ec.group()}
 
-	pbmp.A = &pb.ECPoint{mp.A.Bytes()}
-	pbmp.S = &pb.ECPoint{mp.S.Bytes()}
-	pbmp.T1 = &pb.ECPoint{mp.T1.Bytes()}
-	pbmp.T2 = &pb.ECPoint{mp.T2.Bytes()}
-
-	pbmp.Tau = mp.Tau.Bytes()
-	pbmp.Th = mp.Th.Bytes()
-	pbmp.Mu = mp.Mu.Bytes()
-
-	pbmp.IPP = &pb.InnerProductProof{}
-	
-	for i := 0;i < len(mp.IPP.L) ;i++  {
-		newIPL := &pb.ECPoint{mp.IPP.L[i].Bytes()}
-		newIPR := &pb.ECPoint{mp.IPP.R[i].Bytes()}
-		pbmp.IPP.L = append(pbmp.IPP.L, newIPL)
-		pbmp.IPP.R = append(pbmp.IPP.R, newIPR)
-	}
+	pbmp.A = w.point("A", mp.A)
+	pbmp.S = w.point("S", mp.S)
+	pbmp.T1 = w.point("T1", mp.T1)
+	pbmp.T2 = w.point("T2", mp.T2)
 
+	pbmp.Tau = w.scalar("tau_x", mp.Tau)
+	pbmp.Th = w.scalar("t_hat", mp.Th)
+	pbmp.Mu = w.scalar("mu", mp.Mu)
+	pbmp.Bits = uint32(mp.Bits)
 
-	pbmp.IPP.A = mp.IPP.A.Bytes()
-	pbmp.IPP.B = mp.IPP.B.Bytes()
+	pbmp.IPP = mp.IPP.pb(w)
+	if w.err != nil {
+		return "", w.err
+	}
 
 	serialMp, err := proto.Marshal(pbmp)
 	if err != nil {
@@ -98,156 +115,193 @@ func (mp *MultiRangeProof) Serialize() (string, error) {
 	}
 }
 
-func (mp *MultiRangeProof) Rebuild(encodedMP string) (error) {
-	bRp, err := hex.DecodeString(encodedMP)
-	if err != nil {
-		return err
+// pbWriter - fills in the protobuf form of a proof, keeping the first field
+// that is missing, so a zero or partly filled proof fails to serialise with
+// ErrMalformedProof rather than a nil pointer panic. Points are encoded with
+// g.Encode.
+type pbWriter struct {
+	g   Group
+	err error
+}
+
+// fail records that what is missing, unless something already is
+func (w *pbWriter) fail(what string) {
+	if w.err == nil {
+		w.err = fmt.Errorf("%w: %s is missing", ErrMalformedProof, what)
 	}
+}
 
-	pbRp := &pb.MultiRangeProof{}
+// point returns the protobuf form of the point name
+func (w *pbWriter) point(name string, p ECPoint) *pb.ECPoint {
+	if p.X == nil || p.Y == nil {
+		w.fail(name)
+		return nil
+	}
+	return &pb.ECPoint{Compressed: w.g.Encode(p)}
+}
 
-	if err := proto.Unmarshal(bRp, pbRp); err != nil {
-		log.Fatalln("Failed to parse range proof:", err)
+// scalar returns the bytes of the scalar name
+func (w *pbWriter) scalar(name string, x *big.Int) []byte {
+	if x == nil {
+		w.fail(name)
+		return nil
+	}
+	return x.Bytes()
+}
+
+// rebuildPoint decodes a protobuf point of the group g into p
+func rebuildPoint(g Group, p *ECPoint, pbPoint *pb.ECPoint) error {
+	if pbPoint == nil {
+		return fmt.Errorf("%w: missing point", ErrMalformedProof)
+	}
+	q, err := g.Decode(pbPoint.GetCompressed())
+	if err != nil {
 		return err
 	}
+	*p = q
+	return nil
+}
 
+// Rebuild - decodes a proof made by Serialize
+func (mp *MultiRangeProof) Rebuild(encodedMP string) error {
+	return mp.RebuildWithParams(&EC, encodedMP)
+}
 
+// RebuildWithParams - decodes a proof made by SerializeWithParams(ec)
+func (mp *MultiRangeProof) RebuildWithParams(ec *CryptoParams, encodedMP string) error {
+	bRp := base58.Decode(encodedMP)
+	if len(bRp) == 0 {
+		return fmt.Errorf("%w: empty or invalid base58", ErrMalformedProof)
+	}
 
-	mp.A.Rebuild(pbRp.A.GetCompressed())
-	mp.S.Rebuild(pbRp.S.GetCompressed())
-	mp.T1.Rebuild(pbRp.T1.GetCompressed())
-	mp.T2.Rebuild(pbRp.T2.GetCompressed())
+	pbRp := &pb.MultiRangeProof{}
 
+	if err := proto.Unmarshal(bRp, pbRp); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+
+	g := ec.group()
+	if err := rebuildPoint(g, &mp.A, pbRp.A); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &mp.S, pbRp.S); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &mp.T1, pbRp.T1); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &mp.T2, pbRp.T2); err != nil {
+		return err
+	}
 
 	mp.Tau = new(big.Int).SetBytes(pbRp.Tau)
 	mp.Th = new(big.Int).SetBytes(pbRp.Th)
 	mp.Mu = new(big.Int).SetBytes(pbRp.Mu)
+	mp.Bits = int(pbRp.Bits)
 
-
-	mp.IPP = InnerProdArg{}
-
-	for i := 0;i < len(pbRp.IPP.L) ;i++  {
-		newIPL :=  ECPoint{}
-		err := newIPL.Rebuild(pbRp.IPP.L[i].GetCompressed())
-		if err != nil {
-			log.Fatal(err)
-		}
-		newIPR :=  ECPoint{}
-		err = newIPR.Rebuild(pbRp.IPP.R[i].GetCompressed())
-		if err != nil {
-			log.Fatal(err)
-		}
-		mp.IPP.L = append(mp.IPP.L, newIPL)
-		mp.IPP.R = append(mp.IPP.R, newIPR)
+	ipp, err := rebuildIPP(g, pbRp.IPP)
+	if err != nil {
+		return err
 	}
-
-
-	mp.IPP.A = new(big.Int).SetBytes(pbRp.IPP.A)
-	mp.IPP.B = new(big.Int).SetBytes(pbRp.IPP.B)
+	mp.IPP = ipp
 
 	return nil
 }
 
-func (rp *RangeProof) Rebuild(encodedRP string) (error) {
+// Rebuild - decodes a proof made by Serialize
+func (rp *RangeProof) Rebuild(encodedRP string) error {
+	return rp.RebuildWithParams(&EC, encodedRP)
+}
+
+// RebuildWithParams - decodes a proof made by SerializeWithParams(ec)
+func (rp *RangeProof) RebuildWithParams(ec *CryptoParams, encodedRP string) error {
 	bRp := base58.Decode(encodedRP)
+	if len(bRp) == 0 {
+		return fmt.Errorf("%w: empty or invalid base58", ErrMalformedProof)
+	}
+
 	pbRp := &pb.RangeProof{}
 
 	if err := proto.Unmarshal(bRp, pbRp); err != nil {
-		log.Fatalln("Failed to parse range proof:", err)
-		return err
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
 	}
 
-
-	rp.A.Rebuild(pbRp.A.GetCompressed())
-	rp.S.Rebuild(pbRp.S.GetCompressed())
-	rp.T1.Rebuild(pbRp.T1.GetCompressed())
-	rp.T2.Rebuild(pbRp.T2.GetCompressed())
-
+	g := ec.group()
+	if err := rebuildPoint(g, &rp.A, pbRp.A); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &rp.S, pbRp.S); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &rp.T1, pbRp.T1); err != nil {
+		return err
+	}
+	if err := rebuildPoint(g, &rp.T2, pbRp.T2); err != nil {
+		return err
+	}
 
 	rp.Tau = new(big.Int).SetBytes(pbRp.Tau)
 	rp.Th = new(big.Int).SetBytes(pbRp.Th)
 	rp.Mu = new(big.Int).SetBytes(pbRp.Mu)
+	rp.Bits = int(pbRp.Bits)
 
-
-	rp.IPP = InnerProdArg{}
-
-	for i := 0;i < len(pbRp.IPP.L) ;i++  {
-		newIPL :=  ECPoint{}
-		err := newIPL.Rebuild(pbRp.IPP.L[i].GetCompressed())
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		newIPR :=  ECPoint{}
-		err = newIPR.Rebuild(pbRp.IPP.R[i].GetCompressed())
-		if err != nil {
-			log.Fatal(err)
-		}
-		rp.IPP.L = append(rp.IPP.L, newIPL)
-		rp.IPP.R = append(rp.IPP.R, newIPR)
+	ipp, err := rebuildIPP(g, pbRp.IPP)
+	if err != nil {
+		return err
 	}
-
-	rp.IPP.A = new(big.Int).SetBytes(pbRp.IPP.A)
-	rp.IPP.B = new(big.Int).SetBytes(pbRp.IPP.B)
+	rp.IPP = ipp
 
 	return nil
 }
 
-func (rp *RangeProof) Verify(x, y *big.Int) bool {
-	comm := ECPoint{x, y}
-	return RPVerifyTrans(&comm, rp)
+func (rp *RangeProof) Verify(x, y *big.Int) (bool, error) {
+	return rp.VerifyWithParams(&EC, x, y)
 }
 
-func (rp *RangeProof) Bytes() []byte {
-	var retBytes bytes.Buffer
-	retBytes.Write(rp.A.Bytes())
-	retBytes.Write(rp.S.Bytes())
-	retBytes.Write(rp.T1.Bytes())
-	retBytes.Write(rp.T2.Bytes())
-	retBytes.Write(rp.Tau.Bytes())
-	retBytes.Write(rp.Th.Bytes())
-
-	retBytes.Write(rp.Mu.Bytes())
-
-	// now for the IPP bytes
-	for i :=0; i < len(rp.IPP.R);i++  {
-		retBytes.Write(rp.IPP.L[i].Bytes())
-		retBytes.Write(rp.IPP.R[i].Bytes())
-	}
-	retBytes.Write(rp.IPP.A.Bytes())
-	retBytes.Write(rp.IPP.B.Bytes())
-
+// VerifyWithParams verifies the proof against the commitment (x, y) using ec
+func (rp *RangeProof) VerifyWithParams(ec *CryptoParams, x, y *big.Int) (bool, error) {
+	comm := ECPoint{x, y}
+	return ec.RPVerifyTrans(&comm, rp)
+}
 
-	return retBytes.Bytes()
+// Bytes - the points and scalars of the proof concatenated, with the points
+// encoded for the group of EC, or ErrMalformedProof if any are missing
+func (rp *RangeProof) Bytes() ([]byte, error) {
+	return rp.BytesWithParams(&EC)
+}
 
+// BytesWithParams - Bytes with the points encoded for the group of ec
+func (rp *RangeProof) BytesWithParams(ec *CryptoParams) ([]byte, error) {
+	return proofBytes(&pbWriter{g: ec.group()}, rp.multi())
 }
 
+// Serialize - encodes the proof as base58 protobuf, with the points encoded
+// for the group of EC
 func (rp *RangeProof) Serialize() (string, error) {
+	return rp.SerializeWithParams(&EC)
+}
+
+// SerializeWithParams - Serialize with the points encoded for the group of ec
+func (rp *RangeProof) SerializeWithParams(ec *CryptoParams) (string, error) {
 	// create the protobuff object for serialization
 	pbrp := &pb.RangeProof{}
+	w := &pbWriter{g: ec.group()}
 
-	pbrp.A = &pb.ECPoint{rp.A.Bytes()}
-	pbrp.S = &pb.ECPoint{rp.S.Bytes()}
-	pbrp.T1 = &pb.ECPoint{rp.T1.Bytes()}
-	pbrp.T2 = &pb.ECPoint{rp.T2.Bytes()}
-
-	pbrp.Tau = rp.Tau.Bytes()
-	pbrp.Th = rp.Th.Bytes()
-	pbrp.Mu = rp.Mu.Bytes()
+	pbrp.A = w.point("A", rp.A)
+	pbrp.S = w.point("S", rp.S)
+	pbrp.T1 = w.point("T1", rp.T1)
+	pbrp.T2 = w.point("T2", rp.T2)
 
-	pbrp.IPP = &pb.InnerProductProof{}
+	pbrp.Tau = w.scalar("tau_x", rp.Tau)
+	pbrp.Th = w.scalar("t_hat", rp.Th)
+	pbrp.Mu = w.scalar("mu", rp.Mu)
+	pbrp.Bits = uint32(rp.Bits)
 
-	for i := 0;i < len(rp.IPP.L) ;i++  {
-		newIPL := &pb.ECPoint{rp.IPP.L[i].Bytes()}
-		newIPR := &pb.ECPoint{rp.IPP.R[i].Bytes()}
-		pbrp.IPP.L = append(pbrp.IPP.L, newIPL)
-		pbrp.IPP.R = append(pbrp.IPP.R, newIPR)
+	pbrp.IPP = rp.IPP.pb(w)
+	if w.err != nil {
+		return "", w.err
 	}
 
-
-	pbrp.IPP.A = rp.IPP.A.Bytes()
-	pbrp.IPP.B = rp.IPP.B.Bytes()
-
 	serialMp, err := proto.Marshal(pbrp)
 	if err != nil {
 		return "", err
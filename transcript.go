@@ -0,0 +1,226 @@
+package bp_go
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math/big"
+
+	"github.com/gtank/merlin"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// TranscriptHash selects the hash function a Transcript is built on
+type TranscriptHash int
+
+const (
+	// SHA256 - the default transcript hash
+	SHA256 TranscriptHash = iota
+	// SHA3 - SHA3-256 as standardised in FIPS 202
+	SHA3
+	// Keccak - the original Keccak-256 padding, as used by Ethereum
+	Keccak
+	// BLAKE2b - BLAKE2b with a 256 bit digest
+	BLAKE2b
+)
+
+// New returns a fresh hash.Hash for the transcript hash
+func (h TranscriptHash) New() hash.Hash {
+	switch h {
+	case SHA3:
+		return sha3.New256()
+	case Keccak:
+		return sha3.NewLegacyKeccak256()
+	case BLAKE2b:
+		d, _ := blake2b.New256(nil) // only fails for keys longer than 64 bytes
+		return d
+	default:
+		return sha256.New()
+	}
+}
+
+/*
+Transcript - a Fiat-Shamir transcript in the style of Merlin
+
+Every public value of a protocol is appended to the transcript under a label
+before any challenge that depends on it is squeezed out, so each challenge
+commits to the whole conversation so far rather than to a single message.
+The state is a running hash: appending a message replaces the state with
+H(state || label || message), where label and message are each prefixed with
+their length as a little endian uint32. Challenges are reduced mod the group
+order and absorbed back into the state.
+*/
+type Transcript struct {
+	hash  TranscriptHash
+	order *big.Int
+	state []byte
+}
+
+// NewTranscript starts a transcript for the protocol named by label, with
+// challenges reduced mod the order of secp256k1
+func NewTranscript(label string, h TranscriptHash) *Transcript {
+	t := &Transcript{hash: h, order: curve.N}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// NewTranscript starts a transcript that uses the hash and group order of ec
+func (ec *CryptoParams) NewTranscript(label string) *Transcript {
+	t := &Transcript{hash: ec.Hash, order: ec.N}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// writeFrame writes a length prefixed message to h
+func writeFrame(h hash.Hash, msg []byte) {
+	var l [4]byte
+	binary.LittleEndian.PutUint32(l[:], uint32(len(msg)))
+	h.Write(l[:])
+	h.Write(msg)
+}
+
+// AppendMessage absorbs msg under label
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	h := t.hash.New()
+	h.Write(t.state)
+	writeFrame(h, []byte(label))
+	writeFrame(h, msg)
+	t.state = h.Sum(nil)
+}
+
+// AppendUint64 absorbs v as 8 little endian bytes
+func (t *Transcript) AppendUint64(label string, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	t.AppendMessage(label, b[:])
+}
+
+// AppendScalar absorbs s as 32 big endian bytes after reducing it mod the order
+func (t *Transcript) AppendScalar(label string, s *big.Int) {
+	t.AppendMessage(label, new(big.Int).Mod(s, t.order).FillBytes(make([]byte, 32)))
+}
+
+// AppendPoint absorbs the compressed encoding of p
+func (t *Transcript) AppendPoint(label string, p ECPoint) {
+	t.AppendMessage(label, pointBytes(p))
+}
+
+// ChallengeScalar squeezes a non-zero challenge for label out of the
+// transcript. 64 bytes of output are reduced mod the order so the bias is
+// negligible.
+func (t *Transcript) ChallengeScalar(label string) *big.Int {
+	for {
+		wide := make([]byte, 0, 64)
+		for i := byte(0); len(wide) < 64; i++ {
+			h := t.hash.New()
+			h.Write(t.state)
+			writeFrame(h, []byte("challenge"))
+			writeFrame(h, []byte(label))
+			h.Write([]byte{i})
+			wide = h.Sum(wide)
+		}
+		t.AppendMessage(label, wide[:64])
+
+		c := new(big.Int).Mod(new(big.Int).SetBytes(wide[:64]), t.order)
+		if c.Sign() != 0 {
+			return c
+		}
+	}
+}
+
+// ipaTranscript - the transcript an inner product argument runs on, which
+// absorbs the L and R of each round and returns the round challenge
+type ipaTranscript interface {
+	ipaRound(L, R ECPoint) *big.Int
+}
+
+func (t *Transcript) ipaRound(L, R ECPoint) *big.Int {
+	t.AppendPoint("L", L)
+	t.AppendPoint("R", R)
+	return t.ChallengeScalar("x")
+}
+
+/*
+MerlinTranscript - a Merlin transcript (https://merlin.cool) over
+ristretto255, the transcript the dalek bulletproofs crate runs its proofs on
+
+It is built on STROBE-128 rather than on a TranscriptHash. Scalars are
+appended as 32 little endian bytes, points as their ristretto255 encoding,
+and challenges are 64 bytes read as a little endian integer and reduced mod
+the order of ristretto255, all as curve25519-dalek does it.
+*/
+type MerlinTranscript struct {
+	t *merlin.Transcript
+}
+
+// NewMerlinTranscript starts a Merlin transcript for the protocol named by
+// label
+func NewMerlinTranscript(label string) *MerlinTranscript {
+	return &MerlinTranscript{merlin.NewTranscript(label)}
+}
+
+// AppendMessage absorbs msg under label
+func (t *MerlinTranscript) AppendMessage(label string, msg []byte) {
+	t.t.AppendMessage([]byte(label), msg)
+}
+
+// AppendUint64 absorbs v as 8 little endian bytes
+func (t *MerlinTranscript) AppendUint64(label string, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	t.AppendMessage(label, b[:])
+}
+
+// AppendScalar absorbs s as 32 little endian bytes after reducing it mod the
+// order of ristretto255
+func (t *MerlinTranscript) AppendScalar(label string, s *big.Int) {
+	t.AppendMessage(label, scalarLE(s, ristrettoOrder))
+}
+
+// AppendPoint absorbs the ristretto255 encoding of p
+func (t *MerlinTranscript) AppendPoint(label string, p ECPoint) {
+	t.AppendMessage(label, ristrettoGroup{}.Encode(p))
+}
+
+// ChallengeBytes squeezes n bytes for label out of the transcript
+func (t *MerlinTranscript) ChallengeBytes(label string, n int) []byte {
+	return t.t.ExtractBytes([]byte(label), n)
+}
+
+// ChallengeScalar squeezes a challenge for label out of the transcript, 64
+// bytes reduced mod the order of ristretto255. Unlike Transcript it does not
+// retry on zero, since dalek does not.
+func (t *MerlinTranscript) ChallengeScalar(label string) *big.Int {
+	wide := leInt(t.ChallengeBytes(label, 64))
+	return wide.Mod(wide, ristrettoOrder)
+}
+
+func (t *MerlinTranscript) ipaRound(L, R ECPoint) *big.Int {
+	t.AppendPoint("L", L)
+	t.AppendPoint("R", R)
+	return t.ChallengeScalar("u")
+}
+
+// pointBytes returns the compressed encoding of p, or 33 zero bytes for the
+// point at infinity
+func pointBytes(p ECPoint) []byte {
+	if p.X == nil || p.Y == nil || (p.X.Sign() == 0 && p.Y.Sign() == 0) {
+		return make([]byte, 33)
+	}
+	return p.Bytes()
+}
+
+// pointsDigest returns a SHA-256 digest identifying the given generator sets
+func pointsDigest(sets ...[]ECPoint) []byte {
+	h := sha256.New()
+	for _, set := range sets {
+		var l [4]byte
+		binary.LittleEndian.PutUint32(l[:], uint32(len(set)))
+		h.Write(l[:])
+		for _, p := range set {
+			h.Write(pointBytes(p))
+		}
+	}
+	return h.Sum(nil)
+}